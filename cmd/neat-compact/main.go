@@ -0,0 +1,57 @@
+// Command neat-compact shrinks NEAT checkpoint files by dropping state that is not needed to
+// resume a run (ancestry, species member lists and old fitness history).
+//
+// Usage:
+//
+//	neat-compact [flags] checkpoint.gz [more.gz ...]
+//
+// Checkpoints are rewritten in place unless -out is given (only valid with a single input).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/baldhumanity/neat-go/neat"
+)
+
+func main() {
+	out := flag.String("out", "", "write the compacted checkpoint here instead of overwriting the input")
+	history := flag.Int("history", 0, "fitness history entries kept per species (0 = only the most recent)")
+	keepAncestors := flag.Bool("keep-ancestors", false, "keep the ancestry of the current population")
+	keepMembers := flag.Bool("keep-members", false, "keep species member lists")
+	keepHallOfFame := flag.Bool("keep-hall-of-fame", false, "keep the hall of fame")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] checkpoint.gz [more.gz ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *out != "" && flag.NArg() > 1 {
+		log.Fatalf("-out can only be used with a single checkpoint")
+	}
+
+	opts := neat.CompactOptions{
+		KeepAncestors:  *keepAncestors,
+		HistoryLength:  *history,
+		KeepMembers:    *keepMembers,
+		KeepHallOfFame: *keepHallOfFame,
+	}
+	for _, path := range flag.Args() {
+		target := path
+		if *out != "" {
+			target = *out
+		}
+		result, err := neat.CompactCheckpoint(path, target, opts)
+		if err != nil {
+			log.Fatalf("Failed to compact %s: %v", path, err)
+		}
+		fmt.Printf("%s: %d -> %d bytes\n", target, result.BytesBefore, result.BytesAfter)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/baldhumanity/neat-go/neat"
+	"github.com/baldhumanity/neat-go/neat/nn"
+)
+
+// runCommand implements "run" and, with resume set, "resume".
+func runCommand(args []string, resume bool) error {
+	name := "run"
+	if resume {
+		name = "resume"
+	}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	configPath := fs.String("config", "", "NEAT config file")
+	checkpointPath := fs.String("checkpoint", "", "checkpoint to resume from (resume only)")
+	evalCommand := fs.String("eval", "", "external evaluator command (line-based JSON protocol)")
+	pluginPath := fs.String("plugin", "", "Go plugin exporting EvalGenomes")
+	generations := fs.Int("generations", 100, "generations to run")
+	prefix := fs.String("checkpoint-prefix", "neat-checkpoint", "prefix of checkpoint files")
+	every := fs.Int("checkpoint-every", 10, "save a checkpoint every N generations (0 = only at the end)")
+	fs.Parse(args)
+
+	required := []string{"config"}
+	if resume {
+		required = append(required, "checkpoint")
+	}
+	if err := requireFlags(fs, required...); err != nil {
+		return err
+	}
+	if (*evalCommand == "") == (*pluginPath == "") {
+		return fmt.Errorf("exactly one of -eval and -plugin is required")
+	}
+
+	var fitnessFunc neat.FitnessFunc
+	if *pluginPath != "" {
+		fn, err := loadPluginEvaluator(*pluginPath)
+		if err != nil {
+			return err
+		}
+		fitnessFunc = fn
+	} else {
+		evaluator, err := startProcessEvaluator(*evalCommand)
+		if err != nil {
+			return err
+		}
+		defer evaluator.Close()
+		fitnessFunc = evaluator.Evaluate
+	}
+
+	var pop *neat.Population
+	var err error
+	if resume {
+		pop, err = neat.LoadCheckpoint(*checkpointPath, *configPath)
+	} else {
+		var config *neat.Config
+		config, err = neat.LoadConfig(*configPath)
+		if err == nil {
+			pop, err = neat.NewPopulation(config)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	pop.AddReporter(neat.NewStdOutReporter())
+
+	target := pop.Generation + *generations
+	for pop.Generation < target {
+		winner, err := pop.RunGeneration(fitnessFunc)
+		if err != nil {
+			if errors.Is(err, neat.ErrExtinction) {
+				fmt.Println("All species went extinct.")
+				break
+			}
+			return err
+		}
+		if winner != nil {
+			fmt.Printf("Fitness threshold met by genome %d (fitness %.4f).\n", winner.Key, winner.Fitness)
+			break
+		}
+		if *every > 0 && pop.Generation%*every == 0 {
+			if err := pop.SaveCheckpoint(fmt.Sprintf("%s-%d.gz", *prefix, pop.Generation)); err != nil {
+				return err
+			}
+		}
+	}
+	return pop.SaveCheckpoint(fmt.Sprintf("%s-%d.gz", *prefix, pop.Generation))
+}
+
+// inspectCommand prints the state of a checkpoint: a species table and the champions.
+func inspectCommand(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	configPath := fs.String("config", "", "NEAT config file the checkpoint was created with")
+	checkpointPath := fs.String("checkpoint", "", "checkpoint file")
+	fs.Parse(args)
+	if err := requireFlags(fs, "config", "checkpoint"); err != nil {
+		return err
+	}
+	pop, err := loadQuietly(*checkpointPath, *configPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Generation:  %d\n", pop.Generation)
+	fmt.Printf("Population:  %d genomes\n", len(pop.Population))
+	fmt.Printf("Species:     %d\n", len(pop.SpeciesSet.Species))
+	if pop.BestGenome != nil {
+		nodes, conns := pop.BestGenome.Size()
+		fmt.Printf("Best genome: %d (fitness %.4f, %d nodes, %d enabled connections)\n", pop.BestGenome.Key, pop.BestGenome.Fitness, nodes, conns)
+	}
+	fmt.Println()
+
+	speciesKeys := make([]int, 0, len(pop.SpeciesSet.Species))
+	for sid := range pop.SpeciesSet.Species {
+		speciesKeys = append(speciesKeys, sid)
+	}
+	sort.Ints(speciesKeys)
+	champions := pop.SpeciesSet.Champions()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "species\tsize\tcreated\tlast improved\tfitness\tchampion\tchampion fitness\t")
+	for _, sid := range speciesKeys {
+		sp := pop.SpeciesSet.Species[sid]
+		champion, fitness := "-", "-"
+		if g, ok := champions[sid]; ok {
+			champion = strconv.Itoa(g.Key)
+			fitness = fmt.Sprintf("%.4f", g.Fitness)
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%.4f\t%s\t%s\t\n", sid, len(sp.Members), sp.Created, sp.LastImproved, sp.Fitness, champion, fitness)
+	}
+	return w.Flush()
+}
+
+// exportCommand writes one genome of a checkpoint as JSON, Graphviz DOT or a Keras model.
+func exportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	configPath := fs.String("config", "", "NEAT config file the checkpoint was created with")
+	checkpointPath := fs.String("checkpoint", "", "checkpoint file")
+	genome := fs.String("genome", "best", "genome to export: 'best' or a genome key in the current population")
+	format := fs.String("format", "json", "output format: json, dot or keras")
+	out := fs.String("out", "", "output file (default: standard output); for keras, the architecture file, with the weights written next to it as *.weights.json")
+	fs.Parse(args)
+	if err := requireFlags(fs, "config", "checkpoint"); err != nil {
+		return err
+	}
+	pop, err := loadQuietly(*checkpointPath, *configPath)
+	if err != nil {
+		return err
+	}
+
+	var g *neat.Genome
+	if *genome == "best" {
+		g = pop.BestGenome
+		if g == nil {
+			return fmt.Errorf("checkpoint has no best genome")
+		}
+	} else {
+		key, err := strconv.Atoi(*genome)
+		if err != nil {
+			return fmt.Errorf("invalid -genome '%s': must be 'best' or a genome key", *genome)
+		}
+		var ok bool
+		if g, ok = pop.Population[key]; !ok {
+			return fmt.Errorf("genome %d is not in the checkpoint's population", key)
+		}
+	}
+	speciesKey, ok := pop.SpeciesSet.GetSpeciesID(g.Key)
+	if !ok {
+		speciesKey = -1
+	}
+
+	var data []byte
+	switch *format {
+	case "json":
+		data, err = neat.GenomeJSON(g, speciesKey, pop.Generation)
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	case "dot":
+		data = []byte(neat.GenomeDOT(g))
+	case "keras":
+		if *out == "" {
+			return fmt.Errorf("-format keras requires -out")
+		}
+		model, err := nn.ExportKeras(g)
+		if err != nil {
+			return err
+		}
+		return model.Save(*out, strings.TrimSuffix(*out, filepath.Ext(*out))+".weights.json")
+	default:
+		return fmt.Errorf("invalid -format '%s': must be json, dot or keras", *format)
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*out, data, 0644)
+}
+
+// loadQuietly loads a checkpoint without the library's progress messages, which would otherwise
+// mix with the command's output.
+func loadQuietly(checkpointPath, configPath string) (*neat.Population, error) {
+	defaultLogger := neat.DefaultLogger
+	neat.DefaultLogger = neat.NopLogger{}
+	defer func() { neat.DefaultLogger = defaultLogger }()
+	return neat.LoadCheckpoint(checkpointPath, configPath)
+}
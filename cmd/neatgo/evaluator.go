@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"plugin"
+	"sort"
+
+	"github.com/baldhumanity/neat-go/neat"
+)
+
+// evalRequest is one line sent to an external evaluator: a genome in the JSON layout of neat.GenomeJSON.
+type evalRequest struct {
+	Key    int             `json:"key"`
+	Genome json.RawMessage `json:"genome"`
+}
+
+// evalResponse is one line read back from an external evaluator.
+type evalResponse struct {
+	Key       int       `json:"key"`
+	Fitness   float64   `json:"fitness"`
+	Fitnesses []float64 `json:"fitnesses,omitempty"` // Objective values for the nsga2 strategy
+}
+
+// processEvaluator evaluates genomes with a long-running external process speaking a line-based
+// JSON protocol: for every genome it receives one evalRequest line on stdin and must answer with
+// one evalResponse line on stdout, in any order. Its stderr is passed through.
+type processEvaluator struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+}
+
+func startProcessEvaluator(command string) (*processEvaluator, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open evaluator stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open evaluator stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start evaluator '%s': %w", command, err)
+	}
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	return &processEvaluator{cmd: cmd, stdin: stdin, stdout: scanner}, nil
+}
+
+// Evaluate is a neat.FitnessFunc.
+func (pe *processEvaluator) Evaluate(genomes map[int]*neat.Genome) error {
+	keys := make([]int, 0, len(genomes))
+	for k := range genomes {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	// Write from a separate goroutine so an evaluator answering early can't deadlock on full pipes.
+	writeErr := make(chan error, 1)
+	go func() {
+		w := bufio.NewWriter(pe.stdin)
+		for _, k := range keys {
+			data, err := neat.GenomeJSON(genomes[k], -1, -1)
+			if err != nil {
+				writeErr <- fmt.Errorf("failed to encode genome %d: %w", k, err)
+				return
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, data); err != nil {
+				writeErr <- fmt.Errorf("failed to encode genome %d: %w", k, err)
+				return
+			}
+			line, _ := json.Marshal(evalRequest{Key: k, Genome: compact.Bytes()})
+			w.Write(line)
+			w.WriteByte('\n')
+		}
+		writeErr <- w.Flush()
+	}()
+
+	pending := make(map[int]bool, len(keys))
+	for _, k := range keys {
+		pending[k] = true
+	}
+	for len(pending) > 0 {
+		if !pe.stdout.Scan() {
+			if err := pe.stdout.Err(); err != nil {
+				return fmt.Errorf("failed to read evaluator output: %w", err)
+			}
+			return fmt.Errorf("evaluator exited with %d genomes unanswered", len(pending))
+		}
+		var resp evalResponse
+		if err := json.Unmarshal(pe.stdout.Bytes(), &resp); err != nil {
+			return fmt.Errorf("invalid evaluator output %q: %w", pe.stdout.Text(), err)
+		}
+		if !pending[resp.Key] {
+			return fmt.Errorf("evaluator answered for unexpected genome %d", resp.Key)
+		}
+		delete(pending, resp.Key)
+		genomes[resp.Key].Fitness = resp.Fitness
+		genomes[resp.Key].Fitnesses = resp.Fitnesses
+	}
+	if err := <-writeErr; err != nil {
+		return fmt.Errorf("failed to send genomes to evaluator: %w", err)
+	}
+	return nil
+}
+
+// Close stops the evaluator process by closing its stdin.
+func (pe *processEvaluator) Close() error {
+	pe.stdin.Close()
+	return pe.cmd.Wait()
+}
+
+// loadPluginEvaluator opens a Go plugin exporting
+//
+//	func EvalGenomes(genomes map[int]*neat.Genome) error
+//
+// built with `go build -buildmode=plugin` against the same version of this module.
+func loadPluginEvaluator(path string) (neat.FitnessFunc, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin '%s': %w", path, err)
+	}
+	sym, err := p.Lookup("EvalGenomes")
+	if err != nil {
+		return nil, fmt.Errorf("plugin '%s' does not export EvalGenomes: %w", path, err)
+	}
+	fn, ok := sym.(func(map[int]*neat.Genome) error)
+	if !ok {
+		return nil, fmt.Errorf("plugin '%s': EvalGenomes has type %T, want func(map[int]*neat.Genome) error", path, sym)
+	}
+	return fn, nil
+}
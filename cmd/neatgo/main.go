@@ -0,0 +1,71 @@
+// Command neatgo runs, resumes, inspects and exports NEAT evolutions from the command line.
+//
+// Usage:
+//
+//	neatgo run     -config FILE (-eval CMD | -plugin FILE.so) [-generations N] [-checkpoint-prefix P] [-checkpoint-every N]
+//	neatgo resume  -config FILE -checkpoint FILE (-eval CMD | -plugin FILE.so) [same flags as run]
+//	neatgo inspect -config FILE -checkpoint FILE
+//	neatgo export  -config FILE -checkpoint FILE [-genome best|KEY] [-format json|dot|keras] [-out FILE]
+//
+// Fitness is computed either by a Go plugin exporting EvalGenomes (see loadPluginEvaluator) or by
+// an external command speaking a line-based JSON protocol on stdin/stdout: for every genome it
+// reads {"key":K,"genome":{...}} and answers {"key":K,"fitness":F}. The genome layout is the one
+// written by neat.GenomeJSON.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = runCommand(os.Args[2:], false)
+	case "resume":
+		err = runCommand(os.Args[2:], true)
+	case "inspect":
+		err = inspectCommand(os.Args[2:])
+	case "export":
+		err = exportCommand(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "neatgo: unknown command '%s'\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "neatgo %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: neatgo <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  run      start a new evolution")
+	fmt.Fprintln(os.Stderr, "  resume   continue an evolution from a checkpoint")
+	fmt.Fprintln(os.Stderr, "  inspect  print a summary of a checkpoint")
+	fmt.Fprintln(os.Stderr, "  export   write a genome from a checkpoint as JSON, DOT or a Keras model")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Run 'neatgo <command> -h' for the flags of a command.")
+}
+
+// requireFlags returns an error naming the first empty required flag.
+func requireFlags(fs *flag.FlagSet, names ...string) error {
+	for _, name := range names {
+		if fs.Lookup(name).Value.String() == "" {
+			return fmt.Errorf("-%s is required", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Cart-pole constants from Barto, Sutton & Anderson (1983), as used by Gym's CartPole.
+const (
+	gravity        = 9.8
+	cartMass       = 1.0
+	poleMass       = 0.1
+	totalMass      = cartMass + poleMass
+	poleHalfLength = 0.5
+	poleMassLength = poleMass * poleHalfLength
+	forceMagnitude = 10.0
+	timeStep       = 0.02 // Seconds per step (Euler integration)
+
+	positionLimit = 2.4                  // Cart position (m) at which the episode fails
+	angleLimit    = 12 * math.Pi / 180.0 // Pole angle (rad) at which the episode fails
+)
+
+// CartPole is the classic pole balancing task as a neat.Environment. The agent pushes the cart
+// left or right every step and earns a reward of 1 for each step the pole stays up.
+type CartPole struct {
+	x, xDot, theta, thetaDot float64
+}
+
+// Reset implements neat.Environment: the state starts with small random values drawn from seed.
+func (c *CartPole) Reset(seed int64) ([]float64, error) {
+	rng := rand.New(rand.NewSource(seed))
+	c.x = rng.Float64()*0.1 - 0.05
+	c.xDot = rng.Float64()*0.1 - 0.05
+	c.theta = rng.Float64()*0.1 - 0.05
+	c.thetaDot = rng.Float64()*0.1 - 0.05
+	return c.observation(), nil
+}
+
+// Step implements neat.Environment. The cart is pushed right if action[0] > 0.5, otherwise left.
+func (c *CartPole) Step(action []float64) ([]float64, float64, bool, error) {
+	force := -forceMagnitude
+	if action[0] > 0.5 {
+		force = forceMagnitude
+	}
+	sin, cos := math.Sin(c.theta), math.Cos(c.theta)
+	temp := (force + poleMassLength*c.thetaDot*c.thetaDot*sin) / totalMass
+	thetaAcc := (gravity*sin - cos*temp) / (poleHalfLength * (4.0/3.0 - poleMass*cos*cos/totalMass))
+	xAcc := temp - poleMassLength*thetaAcc*cos/totalMass
+
+	c.x += timeStep * c.xDot
+	c.xDot += timeStep * xAcc
+	c.theta += timeStep * c.thetaDot
+	c.thetaDot += timeStep * thetaAcc
+
+	failed := math.Abs(c.x) > positionLimit || math.Abs(c.theta) > angleLimit
+	return c.observation(), 1.0, failed, nil
+}
+
+// observation scales the state to roughly [-1, 1].
+func (c *CartPole) observation() []float64 {
+	return []float64{c.x / positionLimit, c.xDot / 2.0, c.theta / angleLimit, c.thetaDot / 2.0}
+}
@@ -0,0 +1,93 @@
+// Command cartpole evolves recurrent networks that balance a pole on a cart, simulated in Go.
+// Every genome is scored by the mean number of steps it keeps the pole up over a few episodes
+// with fixed starting states. The config enables no_fitness_termination, so a run always lasts
+// the full number of generations; with a fixed -seed it doubles as a regression benchmark.
+//
+//	go run . -generations 50 -seed 1
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/baldhumanity/neat-go/neat"
+	"github.com/baldhumanity/neat-go/neat/nn"
+)
+
+// recurrentPolicy builds a fresh recurrent network for every episode, so no state carries over.
+func recurrentPolicy(g *neat.Genome) (neat.Policy, error) {
+	net, err := nn.CreateRecurrentNetwork(g)
+	if err != nil {
+		return nil, err
+	}
+	return net.Activate, nil
+}
+
+func main() {
+	configPath := flag.String("config", "./configs/cartpole-config", "NEAT config file")
+	generations := flag.Int("generations", 50, "number of generations to run")
+	episodes := flag.Int("episodes", 5, "episodes per genome")
+	maxSteps := flag.Int("max-steps", 500, "step limit per episode")
+	seed := flag.Int64("seed", 1, "random seed for evolution (0 = time-based)")
+	flag.Parse()
+
+	if *seed != 0 {
+		rand.Seed(*seed)
+	}
+	config, err := neat.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	pop, err := neat.NewPopulation(config)
+	if err != nil {
+		log.Fatalf("Failed to create population: %v", err)
+	}
+	pop.SetLogger(neat.NopLogger{})
+
+	seeds := make([]int64, *episodes)
+	for i := range seeds {
+		seeds[i] = int64(i)
+	}
+	evaluator := neat.NewEpisodeEvaluator(&CartPole{}, recurrentPolicy, seeds, *maxSteps)
+
+	start := time.Now()
+	solvedAt := -1
+	for pop.Generation < *generations {
+		if _, err := pop.RunGeneration(evaluator.FitnessFunc()); err != nil {
+			log.Fatalf("Generation %d failed: %v", pop.Generation, err)
+		}
+		best := pop.BestGenome
+		nodes, conns := best.Size()
+		fmt.Printf("Generation %3d: best %.1f steps (genome %d, %d nodes, %d connections), %d species\n",
+			pop.Generation, best.Fitness, best.Key, nodes, conns, len(pop.SpeciesSet.Species))
+		if solvedAt < 0 && best.Fitness >= float64(*maxSteps) {
+			solvedAt = pop.Generation
+		}
+	}
+	elapsed := time.Since(start)
+
+	fmt.Println("\n--- Evolution Complete ---")
+	if solvedAt >= 0 {
+		fmt.Printf("Balanced for %d steps on every episode first in generation %d\n", *maxSteps, solvedAt)
+	} else {
+		fmt.Printf("Not solved within %d generations (best %.1f steps)\n", *generations, pop.BestGenome.Fitness)
+	}
+	fmt.Printf("%d generations, %d simulation steps in %v (%.0f steps/s)\n",
+		*generations, evaluator.Steps, elapsed.Round(time.Millisecond), float64(evaluator.Steps)/elapsed.Seconds())
+
+	// Replay the champion from starting states it was not trained on.
+	for s := int64(100); s < 105; s++ {
+		policy, err := recurrentPolicy(pop.BestGenome)
+		if err != nil {
+			log.Fatalf("Failed to build the best genome's network: %v", err)
+		}
+		_, steps, err := neat.RunEpisode(&CartPole{}, policy, s, *maxSteps)
+		if err != nil {
+			log.Fatalf("Replay failed: %v", err)
+		}
+		fmt.Printf("Replay from seed %d: %d steps\n", s, steps)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// gymRequest is one line sent to gym_bridge.py.
+type gymRequest struct {
+	Cmd    string    `json:"cmd"`
+	Env    string    `json:"env,omitempty"`
+	Seed   *int64    `json:"seed,omitempty"`
+	Action []float64 `json:"action,omitempty"`
+}
+
+// gymReply is one line read back from gym_bridge.py.
+type gymReply struct {
+	ObservationSize int       `json:"observation_size"`
+	ActionSize      int       `json:"action_size"`
+	Discrete        bool      `json:"discrete"`
+	Observation     []float64 `json:"observation"`
+	Reward          float64   `json:"reward"`
+	Done            bool      `json:"done"`
+	Error           string    `json:"error"`
+}
+
+// GymEnv is a neat.Environment backed by a Python Gym environment running in gym_bridge.py.
+type GymEnv struct {
+	ObservationSize int
+	ActionSize      int
+	Discrete        bool // Actions are argmax-ed into a discrete choice by the bridge
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+}
+
+// StartGymEnv starts the bridge with the given Python interpreter and creates environment name.
+func StartGymEnv(python, bridge, name string) (*GymEnv, error) {
+	cmd := exec.Command(python, bridge)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s %s: %w", python, bridge, err)
+	}
+	env := &GymEnv{cmd: cmd, stdin: stdin, stdout: bufio.NewScanner(stdout)}
+	reply, err := env.call(gymRequest{Cmd: "make", Env: name})
+	if err != nil {
+		env.Close()
+		return nil, err
+	}
+	env.ObservationSize, env.ActionSize, env.Discrete = reply.ObservationSize, reply.ActionSize, reply.Discrete
+	return env, nil
+}
+
+func (e *GymEnv) call(req gymRequest) (*gymReply, error) {
+	line, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := e.stdin.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to send '%s' to the gym bridge: %w", req.Cmd, err)
+	}
+	if !e.stdout.Scan() {
+		if err := e.stdout.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read from the gym bridge: %w", err)
+		}
+		return nil, fmt.Errorf("gym bridge exited")
+	}
+	var reply gymReply
+	if err := json.Unmarshal(e.stdout.Bytes(), &reply); err != nil {
+		return nil, fmt.Errorf("invalid reply from the gym bridge %q: %w", e.stdout.Text(), err)
+	}
+	if reply.Error != "" {
+		return nil, fmt.Errorf("gym bridge: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+// Reset implements neat.Environment.
+func (e *GymEnv) Reset(seed int64) ([]float64, error) {
+	reply, err := e.call(gymRequest{Cmd: "reset", Seed: &seed})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Observation, nil
+}
+
+// Step implements neat.Environment.
+func (e *GymEnv) Step(action []float64) ([]float64, float64, bool, error) {
+	reply, err := e.call(gymRequest{Cmd: "step", Action: action})
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return reply.Observation, reply.Reward, reply.Done, nil
+}
+
+// Close stops the bridge process.
+func (e *GymEnv) Close() error {
+	e.stdin.Close()
+	return e.cmd.Wait()
+}
@@ -0,0 +1,72 @@
+"""Serves a Gymnasium (or classic Gym) environment to neat-go over stdin/stdout.
+
+Every request is one JSON object per line and gets one JSON reply per line:
+
+    {"cmd": "make", "env": "CartPole-v1"}
+        -> {"observation_size": 4, "action_size": 2, "discrete": true}
+    {"cmd": "reset", "seed": 7}
+        -> {"observation": [...]}
+    {"cmd": "step", "action": [0.1, 0.9]}
+        -> {"observation": [...], "reward": 1.0, "done": false}
+
+For discrete action spaces the action is the index of the largest value; continuous actions are
+passed through, clipped to the action space. Errors are answered with {"error": "..."}.
+"""
+
+import json
+import sys
+
+try:
+    import gymnasium as gym
+except ImportError:  # Fall back to the classic gym package.
+    import gym
+
+import numpy as np
+
+
+def flatten(observation):
+    return np.asarray(observation, dtype=np.float64).ravel().tolist()
+
+
+def main():
+    env = None
+    for line in sys.stdin:
+        request = json.loads(line)
+        try:
+            cmd = request["cmd"]
+            if cmd == "make":
+                env = gym.make(request["env"])
+                space = env.action_space
+                discrete = hasattr(space, "n")
+                reply = {
+                    "observation_size": int(np.prod(env.observation_space.shape)),
+                    "action_size": int(space.n) if discrete else int(np.prod(space.shape)),
+                    "discrete": discrete,
+                }
+            elif cmd == "reset":
+                result = env.reset(seed=request.get("seed"))
+                observation = result[0] if isinstance(result, tuple) else result
+                reply = {"observation": flatten(observation)}
+            elif cmd == "step":
+                action = np.asarray(request["action"], dtype=np.float64)
+                if hasattr(env.action_space, "n"):
+                    action = int(np.argmax(action))
+                else:
+                    action = np.clip(action, env.action_space.low, env.action_space.high)
+                result = env.step(action)
+                if len(result) == 5:
+                    observation, reward, terminated, truncated, _ = result
+                    done = terminated or truncated
+                else:
+                    observation, reward, done, _ = result
+                reply = {"observation": flatten(observation), "reward": float(reward), "done": bool(done)}
+            else:
+                reply = {"error": "unknown command %r" % cmd}
+        except Exception as e:  # Report to the Go side instead of dying silently.
+            reply = {"error": "%s: %s" % (type(e).__name__, e)}
+        sys.stdout.write(json.dumps(reply) + "\n")
+        sys.stdout.flush()
+
+
+if __name__ == "__main__":
+    main()
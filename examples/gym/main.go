@@ -0,0 +1,113 @@
+// Command gym evolves controllers for OpenAI Gym / Gymnasium control tasks such as CartPole and
+// LunarLander. The environment runs in a Python process (gym_bridge.py) that the Go side drives
+// over a line-based JSON protocol on stdin/stdout; every genome is scored by the mean reward of a
+// few episodes with fixed seeds.
+//
+// Requires Python with gymnasium (or gym) and numpy installed:
+//
+//	go run . -env CartPole-v1
+//	go run . -env LunarLander-v2 -generations 300 -max-steps 1000
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/baldhumanity/neat-go/neat"
+	"github.com/baldhumanity/neat-go/neat/nn"
+)
+
+// defaultConfigs maps environments to the configuration shipped with this example.
+var defaultConfigs = map[string]string{
+	"CartPole-v1":    "./configs/cartpole-config",
+	"LunarLander-v2": "./configs/lunarlander-config",
+	"LunarLander-v3": "./configs/lunarlander-config",
+}
+
+// networkPolicy turns a genome into a policy: the network's outputs are the action, argmax-ed by
+// the bridge for discrete action spaces.
+func networkPolicy(g *neat.Genome) (neat.Policy, error) {
+	net, err := nn.CreateFeedForwardNetwork(g)
+	if err != nil {
+		return nil, err
+	}
+	return net.Activate, nil
+}
+
+func main() {
+	envName := flag.String("env", "CartPole-v1", "Gym environment id")
+	configPath := flag.String("config", "", "NEAT config file (default: the one in ./configs for -env)")
+	python := flag.String("python", "python3", "Python interpreter with gymnasium installed")
+	bridge := flag.String("bridge", "./gym_bridge.py", "path to gym_bridge.py")
+	generations := flag.Int("generations", 100, "maximum number of generations")
+	episodes := flag.Int("episodes", 3, "episodes per genome, with seeds 0..episodes-1")
+	maxSteps := flag.Int("max-steps", 500, "step limit per episode")
+	flag.Parse()
+
+	if *configPath == "" {
+		path, ok := defaultConfigs[*envName]
+		if !ok {
+			log.Fatalf("No default config for %s; pass -config", *envName)
+		}
+		*configPath = path
+	}
+	config, err := neat.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	env, err := StartGymEnv(*python, *bridge, *envName)
+	if err != nil {
+		log.Fatalf("Failed to start %s: %v", *envName, err)
+	}
+	defer env.Close()
+	if env.ObservationSize != config.Genome.NumInputs || env.ActionSize != config.Genome.NumOutputs {
+		log.Fatalf("%s has %d observations and %d actions, but the config has num_inputs = %d and num_outputs = %d",
+			*envName, env.ObservationSize, env.ActionSize, config.Genome.NumInputs, config.Genome.NumOutputs)
+	}
+
+	seeds := make([]int64, *episodes)
+	for i := range seeds {
+		seeds[i] = int64(i)
+	}
+	evaluator := neat.NewEpisodeEvaluator(env, networkPolicy, seeds, *maxSteps)
+
+	pop, err := neat.NewPopulation(config)
+	if err != nil {
+		log.Fatalf("Failed to create population: %v", err)
+	}
+	pop.AddReporter(neat.NewStdOutReporter())
+
+	for pop.Generation < *generations {
+		winner, err := pop.RunGeneration(evaluator.FitnessFunc())
+		if err != nil {
+			log.Fatalf("Generation %d failed: %v", pop.Generation, err)
+		}
+		if winner != nil {
+			fmt.Println("\nFitness threshold met!")
+			break
+		}
+	}
+
+	best := pop.BestGenome
+	if best == nil {
+		fmt.Println("No genome was evaluated.")
+		return
+	}
+	fmt.Printf("Best genome %d: mean reward %.2f over %d episodes (%d environment steps in total)\n",
+		best.Key, best.Fitness, len(seeds), evaluator.Steps)
+
+	// Replay the champion on unseen seeds to check it generalizes.
+	policy, err := networkPolicy(best)
+	if err != nil {
+		log.Fatalf("Failed to build the best genome's network: %v", err)
+	}
+	for seed := int64(1000); seed < 1003; seed++ {
+		reward, steps, err := neat.RunEpisode(env, policy, seed, *maxSteps)
+		if err != nil {
+			log.Fatalf("Replay failed: %v", err)
+		}
+		fmt.Printf("Replay with seed %d: reward %.2f in %d steps\n", seed, reward, steps)
+	}
+}
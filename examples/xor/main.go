@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/baldhumanity/neat-go/neat"
 	"github.com/baldhumanity/neat-go/neat/nn"
@@ -89,8 +88,8 @@ func evalGenomes(genomes map[int]*neat.Genome) error {
 func main() {
 	// Config and Checkpoint file paths
 	configPath := "./configs/xor-config"
-	checkpointPrefix := "xor_checkpoint"
-	checkpointFile := checkpointPrefix + ".gz"
+	checkpointFile := "xor_checkpoint.gz"
+	reportFile := "xor_report.html"
 	fmt.Printf("Loading configuration from: %s\n", configPath)
 
 	// Load configuration.
@@ -99,113 +98,59 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	var pop *neat.Population
-	// Attempt to load from checkpoint.
-	if _, err := os.Stat(checkpointFile); err == nil {
-		fmt.Printf("Attempting to load population state from %s\n", checkpointFile)
-		pop, err = neat.LoadCheckpoint(checkpointFile, configPath)
-		if err != nil {
-			log.Printf("WARN: Failed to load checkpoint: %v. Starting new evolution.\n", err)
-			pop = nil // Ensure pop is nil if loading failed
-		}
-	} else {
-		fmt.Println("No checkpoint file found. Starting new evolution.")
+	pop, err := neat.NewPopulation(config)
+	if err != nil {
+		log.Fatalf("Failed to create new population: %v", err)
 	}
 
-	// If loading failed or no checkpoint existed, create a new population.
-	if pop == nil {
-		pop, err = neat.NewPopulation(config)
-		if err != nil {
-			log.Fatalf("Failed to create new population: %v", err)
-		}
-	}
+	// Collect per-generation statistics for the run report and print progress messages.
+	stats := neat.NewStatistics()
+	pop.Statistics = stats
+	pop.AddReporter(neat.NewStdOutReporter())
 
-	// Run the evolution.
+	// Run the evolution for up to 300 generations in total, continuing from the checkpoint of an
+	// earlier, interrupted run if there is one, and checkpointing every 2 generations.
 	numGenerations := 300
-	// Determine remaining generations if loaded from checkpoint
-	startGen := pop.Generation + 1 // Start from the generation *after* the loaded one
-	remGenerations := numGenerations - startGen + 1
-	if remGenerations <= 0 {
-		fmt.Println("Loaded checkpoint is already at or beyond the target number of generations.")
-		// Display winner from loaded population
-	} else {
-		fmt.Printf("Running for %d generations (%d to %d)...\n", remGenerations, startGen, numGenerations)
-		// Modify the Run function to accept starting generation and checkpoint interval?
-		// For now, let's modify the loop here.
-
-		winnerFound := false
-		for g := 0; g < remGenerations; g++ {
-			winner, err := pop.RunGeneration(evalGenomes) // Need a RunGeneration function
-			if err != nil {
-				log.Fatalf("Generation %d failed: %v", pop.Generation, err)
-			}
-
-			if winner != nil {
-				fmt.Println("\nFitness threshold met!")
-				pop.BestGenome = winner // Update best before final save
-				winnerFound = true
-				break
-
-			}
-
-			// Save checkpoint periodically
-			if pop.Generation%2 == 0 {
-				checkpointFilename := fmt.Sprintf("%s_gen%d.gz", checkpointPrefix, pop.Generation)
-				err := pop.SaveCheckpoint(checkpointFilename)
-				if err != nil {
-					log.Printf("WARN: Failed to save checkpoint for generation %d: %v", pop.Generation, err)
-				}
-			}
-		}
-		if !winnerFound {
-			fmt.Printf("\nReached maximum generations (%d).\n", numGenerations)
-		}
-	}
-
-	// Save final checkpoint
-	finalCheckpointFile := fmt.Sprintf("%s_final.gz", checkpointPrefix)
-	err = pop.SaveCheckpoint(finalCheckpointFile)
+	solution, err := pop.Run(evalGenomes,
+		neat.MaxGenerations(numGenerations),
+		neat.ResumeFrom(checkpointFile),
+		neat.CheckpointEvery(2, checkpointFile),
+	)
 	if err != nil {
-		log.Printf("WARN: Failed to save final checkpoint: %v", err)
+		log.Fatalf("Generation %d failed: %v", pop.Generation, err)
+	}
+	if solution != nil {
+		fmt.Println("\nFitness threshold met!")
+	} else {
+		fmt.Printf("\nReached maximum generations (%d).\n", numGenerations)
 	}
 
 	// Evolution finished - Use pop.BestGenome which was updated during the run
 	winner := pop.BestGenome
 	fmt.Println("\n--- Evolution Complete ---")
 	if winner != nil {
-		fmt.Printf("Best genome found (Key: %d, Fitness: %.4f, Gen: %d):\n", winner.Key, winner.Fitness, pop.Generation) // Show final generation
-		fmt.Printf(" Nodes: %d, Connections: %d\n", len(winner.Nodes), len(winner.Connections))
-
-		// Show the performance of the winner.
+		// Record the winner's truth table for the run report.
 		winnerNet, err := nn.CreateFeedForwardNetwork(winner)
 		if err != nil {
 			log.Fatalf("Failed to create network from winner genome: %v", err)
 		}
-
-		fmt.Println("\nWinner network output:")
-		fmt.Println(" Input | Expected | Output")
-		fmt.Println("-----------------------------")
+		rows := make([][]string, 0, len(xorInputs))
 		for i, inputs := range xorInputs {
 			output, err := winnerNet.Activate(inputs)
 			if err != nil {
-				fmt.Printf(" %v |   %.1f    | Error: %v\n", inputs, xorOutputs[i][0], err)
-			} else {
-				fmt.Printf(" %v |   %.1f    | %.4f\n", inputs, xorOutputs[i][0], output[0])
-			}
-		}
-
-		// Inspect the winner genome's output node(s)
-		fmt.Println("\nWinner Genome Output Node Details:")
-		for _, key := range winner.Config.OutputKeys {
-			if node, ok := winner.Nodes[key]; ok {
-				fmt.Printf("  Node %d: Activation='%s', Response=%.3f, Bias=%.3f\n",
-					key, node.Activation, node.Response, node.Bias)
+				rows = append(rows, []string{fmt.Sprint(inputs), fmt.Sprintf("%.1f", xorOutputs[i][0]), fmt.Sprintf("error: %v", err)})
 			} else {
-				fmt.Printf("  Output node key %d not found in winner genome!\n", key)
+				rows = append(rows, []string{fmt.Sprint(inputs), fmt.Sprintf("%.1f", xorOutputs[i][0]), fmt.Sprintf("%.4f", output[0])})
 			}
 		}
-
+		stats.AddTable("Winner network output", []string{"Input", "Expected", "Output"}, rows)
+		fmt.Printf("Best genome found (Key: %d, Fitness: %.4f, Gen: %d)\n", winner.Key, winner.Fitness, pop.Generation)
 	} else {
 		fmt.Println("No winner found within the given generations.")
 	}
+
+	if err := neat.WriteRunReport(pop, stats, reportFile); err != nil {
+		log.Fatalf("Failed to write run report: %v", err)
+	}
+	fmt.Printf("Run report written to %s\n", reportFile)
 }
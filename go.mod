@@ -2,6 +2,24 @@ module github.com/baldhumanity/neat-go
 
 go 1.21
 
-require gopkg.in/ini.v1 v1.67.0
+require (
+	go.etcd.io/bbolt v1.3.10
+	gonum.org/v1/gonum v0.15.0
+	gonum.org/v1/plot v0.14.0
+	gopkg.in/ini.v1 v1.67.0
+)
 
-require github.com/stretchr/testify v1.10.0 // indirect
+require (
+	git.sr.ht/~sbinet/gg v0.5.0 // indirect
+	github.com/ajstarks/svgo v0.0.0-20211024235047-1546f124cd8b // indirect
+	github.com/campoy/embedmd v1.0.0 // indirect
+	github.com/go-fonts/liberation v0.3.2 // indirect
+	github.com/go-latex/latex v0.0.0-20231108140139-5c1ce85aa4ea // indirect
+	github.com/go-pdf/fpdf v0.9.0 // indirect
+	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/testify v1.10.0 // indirect
+	golang.org/x/image v0.14.0 // indirect
+	golang.org/x/sys v0.11.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
@@ -3,6 +3,8 @@ package neat
 import (
 	"fmt"
 	"math"
+	"strings"
+	"sync"
 )
 
 // ActivationType defines the type for activation functions.
@@ -10,6 +12,8 @@ type ActivationType func(input float64, params ...float64) float64
 
 // ActivationFunctions maps function names to the actual activation functions.
 // This allows configuration to specify activations by name.
+// Use RegisterActivation to add custom functions; writing to the map directly is not safe
+// while networks are being built.
 var ActivationFunctions = map[string]ActivationType{
 	"sigmoid":  Sigmoid,
 	"tanh":     Tanh,
@@ -28,15 +32,63 @@ var ActivationFunctions = map[string]ActivationType{
 	"hat":    Hat,
 	"square": Square,
 	"cube":   Cube,
-	// Custom/advanced ones (like Softplus, ELU) could be added if required.
+	"elu":    ELU,
+	// Remaining neat-python functions and names, plus common modern ones.
+	"sin":      Sine,     // neat-python name for sine
+	"gauss":    Gaussian, // neat-python name for gaussian
+	"softplus": Softplus,
+	"lelu":     LeakyReLU,
+	"selu":     SELU,
+	"swish":    Swish,
+	"silu":     Swish, // Alias for swish
+	"step":     Step,
 }
 
+// activationMu guards ActivationFunctions.
+var activationMu sync.RWMutex
+
 // GetActivation retrieves an activation function by name.
 func GetActivation(name string) (ActivationType, error) {
+	activationMu.RLock()
+	defer activationMu.RUnlock()
 	if fn, ok := ActivationFunctions[name]; ok {
 		return fn, nil
 	}
-	return nil, fmt.Errorf("unknown activation function: %s", name)
+	return nil, fmt.Errorf("%w: %s", ErrUnknownActivation, name)
+}
+
+// RegisterActivation adds a custom activation function under name, making it usable in
+// activation_default, activation_options and output_activation. Register functions before
+// loading the config that refers to them. Existing names cannot be replaced.
+func RegisterActivation(name string, fn ActivationType) error {
+	if err := validateFunctionName(name); err != nil {
+		return fmt.Errorf("invalid activation name: %w", err)
+	}
+	if fn == nil {
+		return fmt.Errorf("activation function '%s' is nil", name)
+	}
+	activationMu.Lock()
+	defer activationMu.Unlock()
+	if _, exists := ActivationFunctions[name]; exists {
+		return fmt.Errorf("activation function '%s' is already registered", name)
+	}
+	ActivationFunctions[name] = fn
+	return nil
+}
+
+// validateFunctionName checks that name can be written in a space-delimited config option list.
+func validateFunctionName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name is empty")
+	}
+	if strings.ContainsAny(name, " \t\r\n") {
+		return fmt.Errorf("name '%s' contains whitespace", name)
+	}
+	switch strings.ToLower(name) {
+	case "random", "none":
+		return fmt.Errorf("name '%s' is reserved", name)
+	}
+	return nil
 }
 
 // --- Standard Activation Function Implementations ---
@@ -49,13 +101,30 @@ func Sigmoid(x float64, params ...float64) float64 {
 	// It seems the response attribute scales the *input* to the activation function during network activation.
 	// Let's stick to the standard sigmoid definition for the function itself.
 	// The 'response' parameter from NodeGene will be applied *before* calling this.
-	k := 4.9 // Default steepness, make configurable if needed?
+	// An optional parameter scales the default steepness (see NodeGene.ActivationParam).
+	k := 4.9 * activationParam(params)
 	return 1.0 / (1.0 + math.Exp(-k*x))
 }
 
-// Tanh activation function.
+// Tanh activation function. An optional parameter scales its steepness.
 func Tanh(x float64, params ...float64) float64 {
-	return math.Tanh(x)
+	return math.Tanh(activationParam(params) * x)
+}
+
+// ELU (Exponential Linear Unit) activation function. An optional parameter sets alpha (default 1).
+func ELU(x float64, params ...float64) float64 {
+	if x >= 0 {
+		return x
+	}
+	return activationParam(params) * (math.Exp(clamp(x, -60.0, 0.0)) - 1.0)
+}
+
+// activationParam returns the first activation parameter, or 1.0 (the standard shape) if none is given.
+func activationParam(params []float64) float64 {
+	if len(params) == 0 {
+		return 1.0
+	}
+	return params[0]
 }
 
 // ReLU (Rectified Linear Unit) activation function.
@@ -73,9 +142,10 @@ func Clamped(x float64, params ...float64) float64 {
 	return clamp(x, -1.0, 1.0) // Use the helper from math_util
 }
 
-// Gaussian activation function.
+// Gaussian activation function. An optional parameter scales its width.
 func Gaussian(x float64, params ...float64) float64 {
-	return math.Exp(-x * x / 2.0)
+	w := activationParam(params)
+	return math.Exp(-x * x / (2.0 * w * w))
 }
 
 // Absolute value activation function.
@@ -134,3 +204,42 @@ func Square(x float64, params ...float64) float64 {
 func Cube(x float64, params ...float64) float64 {
 	return x * x * x
 }
+
+// Softplus activation function, a smooth approximation of ReLU (scaled as in neat-python).
+func Softplus(x float64, params ...float64) float64 {
+	z := clamp(5.0*x, -60.0, 60.0)
+	return 0.2 * math.Log1p(math.Exp(z))
+}
+
+// LeakyReLU activation function with a leak of 0.005 for negative inputs (as in neat-python).
+// An optional parameter scales the leak.
+func LeakyReLU(x float64, params ...float64) float64 {
+	if x > 0 {
+		return x
+	}
+	return 0.005 * activationParam(params) * x
+}
+
+// SELU (Scaled Exponential Linear Unit) activation function.
+func SELU(x float64, params ...float64) float64 {
+	const lambda = 1.0507009873554804934193349852946
+	const alpha = 1.6732632423543772848170429916717
+	if x > 0 {
+		return lambda * x
+	}
+	return lambda * alpha * (math.Exp(clamp(x, -60.0, 0.0)) - 1.0)
+}
+
+// Swish (SiLU) activation function, x * sigmoid(beta * x). An optional parameter sets beta (default 1).
+func Swish(x float64, params ...float64) float64 {
+	z := clamp(activationParam(params)*x, -60.0, 60.0)
+	return x / (1.0 + math.Exp(-z))
+}
+
+// Step activation function (binary step): 1 for positive inputs, 0 otherwise.
+func Step(x float64, params ...float64) float64 {
+	if x > 0 {
+		return 1.0
+	}
+	return 0.0
+}
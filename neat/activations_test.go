@@ -0,0 +1,57 @@
+package neat
+
+import (
+	"math"
+	"testing"
+)
+
+func TestActivationsLargeInputs(t *testing.T) {
+	const lambda = 1.0507009873554804934193349852946
+	const alpha = 1.6732632423543772848170429916717
+	tests := []struct {
+		name string
+		x    float64
+		want float64
+	}{
+		{"softplus", 0, 0.2 * math.Ln2},
+		{"softplus", 1e3, 12}, // 0.2 * 60, the clamped argument
+		{"softplus", -1e3, 0},
+		{"elu", 1e3, 1e3},
+		{"elu", -1e3, -1},
+		{"lelu", 1e3, 1e3},
+		{"lelu", -1e3, -5},
+		{"selu", 1e3, lambda * 1e3},
+		{"selu", -1e3, -lambda * alpha},
+		{"swish", 1e3, 1e3},
+		{"swish", -1e3, 0},
+		{"silu", 1e3, 1e3},
+		{"step", 1e3, 1},
+		{"step", 0, 0},
+		{"step", -1e3, 0},
+	}
+	for _, tt := range tests {
+		fn, err := GetActivation(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := fn(tt.x)
+		if math.Abs(got-tt.want) > 1e-9*math.Max(1, math.Abs(tt.want)) {
+			t.Errorf("%s(%g) = %g, want %g", tt.name, tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestActivationsFinite(t *testing.T) {
+	inputs := []float64{1e3, -1e3, 1e300, -1e300}
+	for _, name := range []string{"softplus", "elu", "lelu", "selu", "swish", "silu", "step"} {
+		fn, err := GetActivation(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, x := range inputs {
+			if got := fn(x); math.IsNaN(got) || math.IsInf(got, 0) {
+				t.Errorf("%s(%g) = %g, want a finite value", name, x, got)
+			}
+		}
+	}
+}
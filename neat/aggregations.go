@@ -3,12 +3,15 @@ package neat
 import (
 	"fmt"
 	"math"
+	"sort"
+	"sync"
 )
 
 // AggregationType defines the type for aggregation functions.
 type AggregationType func(inputs []float64) float64
 
 // AggregationFunctions maps function names to the actual aggregation functions.
+// Use RegisterAggregation to add custom functions.
 var AggregationFunctions = map[string]AggregationType{
 	"sum":     AggregateSum,
 	"product": AggregateProduct,
@@ -16,16 +19,42 @@ var AggregationFunctions = map[string]AggregationType{
 	"max":     AggregateMax,
 	"mean":    AggregateMean,
 	"median":  AggregateMedian,
+	"maxabs":  AggregateMaxAbs,
+	"meanabs": AggregateMeanAbs,
 	// Add aliases or other functions if needed
 	"average": AggregateMean, // Alias for mean
 }
 
+// aggregationMu guards AggregationFunctions.
+var aggregationMu sync.RWMutex
+
 // GetAggregation retrieves an aggregation function by name.
 func GetAggregation(name string) (AggregationType, error) {
+	aggregationMu.RLock()
+	defer aggregationMu.RUnlock()
 	if fn, ok := AggregationFunctions[name]; ok {
 		return fn, nil
 	}
-	return nil, fmt.Errorf("unknown aggregation function: %s", name)
+	return nil, fmt.Errorf("%w: %s", ErrUnknownAggregation, name)
+}
+
+// RegisterAggregation adds a custom aggregation function under name, making it usable in
+// aggregation_default and aggregation_options. Register functions before loading the config
+// that refers to them. Existing names cannot be replaced.
+func RegisterAggregation(name string, fn AggregationType) error {
+	if err := validateFunctionName(name); err != nil {
+		return fmt.Errorf("invalid aggregation name: %w", err)
+	}
+	if fn == nil {
+		return fmt.Errorf("aggregation function '%s' is nil", name)
+	}
+	aggregationMu.Lock()
+	defer aggregationMu.Unlock()
+	if _, exists := AggregationFunctions[name]; exists {
+		return fmt.Errorf("aggregation function '%s' is already registered", name)
+	}
+	AggregationFunctions[name] = fn
+	return nil
 }
 
 // --- Standard Aggregation Function Implementations ---
@@ -36,14 +65,22 @@ func AggregateSum(inputs []float64) float64 {
 	return Sum(inputs)
 }
 
-// AggregateProduct calculates the product of the inputs.
+// productLimit bounds the running product in AggregateProduct so deep chains of
+// product nodes saturate instead of overflowing to Inf (and then NaN).
+const productLimit = 1e20
+
+// AggregateProduct calculates the product of the inputs, clamped to ±productLimit after every
+// multiplication. NaN inputs are skipped.
 func AggregateProduct(inputs []float64) float64 {
 	if len(inputs) == 0 {
 		return 0.0 // Or 1.0? Python returns 1.0
 	}
 	product := 1.0
 	for _, v := range inputs {
-		product *= v
+		if math.IsNaN(v) {
+			continue
+		}
+		product = clamp(product*v, -productLimit, productLimit)
 	}
 	return product
 }
@@ -66,29 +103,39 @@ func AggregateMean(inputs []float64) float64 {
 	return Mean(inputs)
 }
 
-// AggregateMedian calculates the median of the inputs.
+// AggregateMedian returns the median of the inputs as neat-python computes it: for an even
+// number of inputs, the upper of the two middle values rather than their average.
 func AggregateMedian(inputs []float64) float64 {
-	// Use the Median function from math_util
-	return Median(inputs)
+	if len(inputs) == 0 {
+		return 0.0
+	}
+	sorted := append([]float64(nil), inputs...)
+	sort.Float64s(sorted)
+	return sorted[len(sorted)/2]
 }
 
-// --- Specific Aggregations from neat-python (might be less common) ---
-
-// NOTE: The `maxabs`, `median`, `meanabs` functions in neat-python's aggregations.py
-// seem slightly different from the standard stats functions. Re-implement if exact
-// behavior is crucial. For now, the standard Mean/Median/Max cover the main cases.
-
-// Example: MaxAbs (if needed)
+// AggregateMaxAbs returns the input with the largest absolute value, keeping its sign (neat-python's maxabs).
 func AggregateMaxAbs(inputs []float64) float64 {
 	if len(inputs) == 0 {
 		return 0.0
 	}
-	maxAbsVal := math.Abs(inputs[0])
-	for i := 1; i < len(inputs); i++ {
-		absVal := math.Abs(inputs[i])
-		if absVal > maxAbsVal {
-			maxAbsVal = absVal
+	maxAbsVal := inputs[0]
+	for _, v := range inputs[1:] {
+		if math.Abs(v) > math.Abs(maxAbsVal) {
+			maxAbsVal = v
 		}
 	}
 	return maxAbsVal
 }
+
+// AggregateMeanAbs returns the mean of the absolute values of the inputs (neat-python's meanabs).
+func AggregateMeanAbs(inputs []float64) float64 {
+	if len(inputs) == 0 {
+		return 0.0
+	}
+	sum := 0.0
+	for _, v := range inputs {
+		sum += math.Abs(v)
+	}
+	return sum / float64(len(inputs))
+}
@@ -0,0 +1,268 @@
+// Package benchmark provides standard NEAT tasks (XOR, N-bit parity and pole balancing) and a
+// harness that runs each task repeatedly and checks its success rate and generation budget, so
+// changes to the algorithm's operators can be tested for regressions:
+//
+//	func TestBenchmarks(t *testing.T) {
+//		benchmark.Check(t, benchmark.Standard()...)
+//	}
+//
+// The cost of the individual operations is measured by the Benchmark functions of package neat.
+package benchmark
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/baldhumanity/neat-go/neat"
+	"github.com/baldhumanity/neat-go/neat/eval"
+)
+
+//go:embed configs/task.ini
+var configTemplate string
+
+// Task is a benchmark problem with the results NEAT is expected to achieve on it.
+type Task struct {
+	Name        string
+	Config      string                  // Config file contents
+	NewFitness  func() neat.FitnessFunc // Creates the fitness function of one run
+	Runs        int                     // Independent runs
+	Generations int                     // Generation budget per run
+
+	MinSuccessRate     float64 // Fraction of runs expected to reach the fitness threshold within the budget
+	MaxMeanGenerations float64 // Expected bound on the mean generations of successful runs (0 = unchecked)
+}
+
+// taskConfig fills in the shared config template.
+func taskConfig(inputs, popSize int, threshold float64) string {
+	var b bytes.Buffer
+	err := template.Must(template.New("config").Parse(configTemplate)).Execute(&b, struct {
+		Inputs, PopSize int
+		Threshold       float64
+	}{inputs, popSize, threshold})
+	if err != nil {
+		panic(err) // The template is embedded, so this is a programming error
+	}
+	return b.String()
+}
+
+// XOR is the two-input exclusive-or problem. Fitness is 4 minus the summed squared error over
+// the four cases, and the task is solved at 3.9.
+func XOR() Task {
+	return Task{
+		Name:               "xor",
+		Config:             taskConfig(2, 150, 3.9),
+		NewFitness:         parityFitness(2),
+		Runs:               20,
+		Generations:        300,
+		MinSuccessRate:     0.8,
+		MaxMeanGenerations: 120,
+	}
+}
+
+// Parity is the n-bit parity problem, the generalization of XOR to n inputs: the output should
+// be 1 when an odd number of inputs is 1. Fitness is 2^n minus the summed squared error over all
+// 2^n cases, and the task is solved at 95% of the maximum. Parity gets hard quickly: the
+// expected success rate for n = 3 is low, so it mainly catches operators that break it entirely.
+func Parity(n int) Task {
+	cases := float64(int(1) << n)
+	return Task{
+		Name:           fmt.Sprintf("parity-%d", n),
+		Config:         taskConfig(n, 300, 0.95*cases),
+		NewFitness:     parityFitness(n),
+		Runs:           20,
+		Generations:    400,
+		MinSuccessRate: 0.15,
+	}
+}
+
+// parityFitness returns a constructor of the n-bit parity fitness function.
+func parityFitness(n int) func() neat.FitnessFunc {
+	cases := 1 << n
+	inputs := make([][]float64, cases)
+	targets := make([][]float64, cases)
+	for c := range inputs {
+		inputs[c] = make([]float64, n)
+		ones := 0
+		for bit := 0; bit < n; bit++ {
+			if c&(1<<bit) != 0 {
+				inputs[c][bit] = 1
+				ones++
+			}
+		}
+		targets[c] = []float64{float64(ones % 2)}
+	}
+	return func() neat.FitnessFunc {
+		s := eval.NewSupervised(inputs, targets, eval.MSE)
+		s.Fitness = func(loss float64) float64 { return float64(cases) * (1 - loss) }
+		return s.FitnessFunc()
+	}
+}
+
+// PoleBalancing is the single pole cart-pole task with velocities given: fitness is the mean
+// number of steps, out of 500, the pole stays up over three episodes with random initial states.
+func PoleBalancing() Task {
+	const maxSteps = 500
+	return Task{
+		Name:   "pole-balancing",
+		Config: taskConfig(4, 150, maxSteps),
+		NewFitness: func() neat.FitnessFunc {
+			newEnv := func() (neat.Environment, error) { return &CartPole{}, nil }
+			ee := eval.NewEpisodeEvaluator(&CartPole{}, newEnv, []int64{1, 2, 3}, maxSteps)
+			return ee.FitnessFunc()
+		},
+		Runs:               10,
+		Generations:        100,
+		MinSuccessRate:     0.9,
+		MaxMeanGenerations: 30,
+	}
+}
+
+// Standard returns the standard suite: XOR, 3-bit parity and pole balancing.
+func Standard() []Task {
+	return []Task{XOR(), Parity(3), PoleBalancing()}
+}
+
+// Result is the outcome of running a task.
+type Result struct {
+	Task     Task
+	Report   *neat.ExperimentReport
+	Failures []string // Unmet expectations (empty if the task passed)
+}
+
+// Passed reports whether every expectation of the task was met.
+func (r *Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// writeConfig writes config file contents to a temporary file and returns its path.
+func writeConfig(contents string) (string, error) {
+	f, err := os.CreateTemp("", "neat-benchmark-*.ini")
+	if err != nil {
+		return "", fmt.Errorf("failed to write benchmark config: %w", err)
+	}
+	if _, err := f.WriteString(contents); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write benchmark config: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write benchmark config: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// Run runs the task's experiment with up to workers runs at once (0 = runtime.NumCPU()) and
+// compares the outcome with the task's expectations.
+func Run(task Task, workers int) (*Result, error) {
+	path, err := writeConfig(task.Config)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(path)
+
+	experiment := neat.NewExperiment(path, nil, task.Runs, task.Generations)
+	experiment.Name = task.Name
+	experiment.NewFitness = task.NewFitness
+	experiment.Workers = workers
+	report, err := experiment.Run()
+	if err != nil {
+		return nil, fmt.Errorf("benchmark %s failed: %w", task.Name, err)
+	}
+
+	result := &Result{Task: task, Report: report}
+	if report.SuccessRate < task.MinSuccessRate {
+		result.Failures = append(result.Failures, fmt.Sprintf("success rate %.0f%% below the expected %.0f%%",
+			100*report.SuccessRate, 100*task.MinSuccessRate))
+	}
+	if task.MaxMeanGenerations > 0 && report.SuccessRate > 0 && report.MeanGenerationsToSolve > task.MaxMeanGenerations {
+		result.Failures = append(result.Failures, fmt.Sprintf("mean generations to solve %.1f above the expected %.1f",
+			report.MeanGenerationsToSolve, task.MaxMeanGenerations))
+	}
+	return result, nil
+}
+
+// Check runs the tasks and reports every unmet expectation as a test error, with a comparison
+// table of all tasks in the log. The tasks are slow, so they are skipped with go test -short.
+func Check(tb testing.TB, tasks ...Task) {
+	tb.Helper()
+	if testing.Short() {
+		tb.Skip("benchmark tasks skipped in short mode")
+	}
+	reports := make([]*neat.ExperimentReport, 0, len(tasks))
+	for _, task := range tasks {
+		result, err := Run(task, 0)
+		if err != nil {
+			tb.Error(err)
+			continue
+		}
+		reports = append(reports, result.Report)
+		if !result.Passed() {
+			tb.Errorf("benchmark %s: %s", task.Name, strings.Join(result.Failures, "; "))
+		}
+	}
+	tb.Log("\n" + neat.CompareExperiments(reports...))
+}
+
+// Cart-pole constants from Barto, Sutton & Anderson (1983), as used by Gym's CartPole.
+const (
+	gravity        = 9.8
+	cartMass       = 1.0
+	poleMass       = 0.1
+	totalMass      = cartMass + poleMass
+	poleHalfLength = 0.5
+	poleMassLength = poleMass * poleHalfLength
+	forceMagnitude = 10.0
+	timeStep       = 0.02 // Seconds per step (Euler integration)
+
+	positionLimit = 2.4                  // Cart position (m) at which the episode fails
+	angleLimit    = 12 * math.Pi / 180.0 // Pole angle (rad) at which the episode fails
+)
+
+// CartPole is the classic pole balancing task as a neat.Environment. The agent pushes the cart
+// left or right every step and earns a reward of 1 for each step the pole stays up.
+type CartPole struct {
+	x, xDot, theta, thetaDot float64
+}
+
+// Reset implements neat.Environment: the state starts with small random values drawn from seed.
+func (c *CartPole) Reset(seed int64) ([]float64, error) {
+	rng := rand.New(rand.NewSource(seed))
+	c.x = rng.Float64()*0.1 - 0.05
+	c.xDot = rng.Float64()*0.1 - 0.05
+	c.theta = rng.Float64()*0.1 - 0.05
+	c.thetaDot = rng.Float64()*0.1 - 0.05
+	return c.observation(), nil
+}
+
+// Step implements neat.Environment. The cart is pushed right if action[0] > 0.5, otherwise left.
+func (c *CartPole) Step(action []float64) ([]float64, float64, bool, error) {
+	force := -forceMagnitude
+	if action[0] > 0.5 {
+		force = forceMagnitude
+	}
+	sin, cos := math.Sin(c.theta), math.Cos(c.theta)
+	temp := (force + poleMassLength*c.thetaDot*c.thetaDot*sin) / totalMass
+	thetaAcc := (gravity*sin - cos*temp) / (poleHalfLength * (4.0/3.0 - poleMass*cos*cos/totalMass))
+	xAcc := temp - poleMassLength*thetaAcc*cos/totalMass
+
+	c.x += timeStep * c.xDot
+	c.xDot += timeStep * xAcc
+	c.theta += timeStep * c.thetaDot
+	c.thetaDot += timeStep * thetaAcc
+
+	failed := math.Abs(c.x) > positionLimit || math.Abs(c.theta) > angleLimit
+	return c.observation(), 1.0, failed, nil
+}
+
+// observation scales the state to roughly [-1, 1].
+func (c *CartPole) observation() []float64 {
+	return []float64{c.x / positionLimit, c.xDot / 2.0, c.theta / angleLimit, c.thetaDot / 2.0}
+}
@@ -0,0 +1,8 @@
+package benchmark
+
+import "testing"
+
+// TestBenchmarks checks the standard tasks for regressions. It is skipped with go test -short.
+func TestBenchmarks(t *testing.T) {
+	Check(t, Standard()...)
+}
@@ -0,0 +1,153 @@
+// Package boltstore implements neat.Store on a bbolt key/value file, so a run's champions,
+// species statistics and optionally full populations can be queried after the run:
+//
+//	store, err := boltstore.Open("run.db")
+//	...
+//	defer store.Close()
+//	pop.AddReporter(neat.NewStoreReporter(store))
+//
+//	record, err := store.LoadGeneration(42) // record.Champion is the champion of generation 42
+//
+// It is a separate package so the neat package doesn't depend on bbolt.
+package boltstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"github.com/baldhumanity/neat-go/neat"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	generationsBucket = []byte("generations") // Generation -> gob-encoded neat.GenerationRecord
+	genomesBucket     = []byte("genomes")     // Generation -> gob-encoded []*neat.CompactGenome
+)
+
+// Store is a neat.Store backed by one bbolt file. A file can only be open in one process at a
+// time; the Store itself is safe for concurrent use.
+type Store struct {
+	db *bolt.DB
+}
+
+var _ neat.Store = (*Store)(nil)
+
+// Open opens the store at path, creating the file if needed. It fails if another process holds
+// the file for more than a second.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store '%s': %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{generationsBucket, genomesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize store '%s': %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// generationKey encodes a generation so keys sort in generation order.
+func generationKey(generation int) ([]byte, error) {
+	if generation < 0 {
+		return nil, fmt.Errorf("invalid generation %d", generation)
+	}
+	return binary.BigEndian.AppendUint64(nil, uint64(generation)), nil
+}
+
+// SaveGeneration implements neat.Store. The record and genomes are written in one transaction;
+// saving without genomes removes genomes stored earlier for the generation.
+func (s *Store) SaveGeneration(record *neat.GenerationRecord, genomes []*neat.CompactGenome) error {
+	key, err := generationKey(record.Generation)
+	if err != nil {
+		return err
+	}
+	var recordData, genomeData bytes.Buffer
+	if err := gob.NewEncoder(&recordData).Encode(record); err != nil {
+		return fmt.Errorf("failed to encode generation %d: %w", record.Generation, err)
+	}
+	if genomes != nil {
+		if err := gob.NewEncoder(&genomeData).Encode(genomes); err != nil {
+			return fmt.Errorf("failed to encode genomes of generation %d: %w", record.Generation, err)
+		}
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(generationsBucket).Put(key, recordData.Bytes()); err != nil {
+			return err
+		}
+		if genomes == nil {
+			return tx.Bucket(genomesBucket).Delete(key)
+		}
+		return tx.Bucket(genomesBucket).Put(key, genomeData.Bytes())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store generation %d: %w", record.Generation, err)
+	}
+	return nil
+}
+
+// load decodes the value stored for generation in bucket into v.
+func (s *Store) load(bucket []byte, generation int, v any) error {
+	key, err := generationKey(generation)
+	if err != nil {
+		return err
+	}
+	return s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get(key)
+		if data == nil {
+			return fmt.Errorf("generation %d: %w", generation, neat.ErrGenerationNotStored)
+		}
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(v); err != nil {
+			return fmt.Errorf("failed to decode %s of generation %d: %w", bucket, generation, err)
+		}
+		return nil
+	})
+}
+
+// LoadGeneration implements neat.Store.
+func (s *Store) LoadGeneration(generation int) (*neat.GenerationRecord, error) {
+	record := &neat.GenerationRecord{}
+	if err := s.load(generationsBucket, generation, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// LoadGenomes implements neat.Store.
+func (s *Store) LoadGenomes(generation int) ([]*neat.CompactGenome, error) {
+	var genomes []*neat.CompactGenome
+	if err := s.load(genomesBucket, generation, &genomes); err != nil {
+		return nil, err
+	}
+	return genomes, nil
+}
+
+// Generations implements neat.Store.
+func (s *Store) Generations() ([]int, error) {
+	var generations []int
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(generationsBucket).ForEach(func(k, _ []byte) error {
+			generations = append(generations, int(binary.BigEndian.Uint64(k)))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stored generations: %w", err)
+	}
+	return generations, nil
+}
+
+// Close implements neat.Store.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
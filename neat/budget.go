@@ -0,0 +1,103 @@
+package neat
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// budgetedEvaluationSet selects which genomes of the current population are evaluated when
+// an evaluation budget is configured (evaluation_budget > 0).
+//
+// Genomes are grouped by the species of their parents (from the previous generation's speciation)
+// and the budget is handed out round-robin across those groups, so every species gets a fair share
+// regardless of its size. Genomes that are not selected inherit the mean fitness of their parents,
+// returned in the inherited map. Genomes whose parents cannot be traced are always evaluated.
+//
+// If no budget is configured, or the budget covers the whole population, the full population is
+// returned and inherited is nil.
+func (p *Population) budgetedEvaluationSet() (evaluate map[int]*Genome, inherited map[int]float64) {
+	budget := p.Config.Neat.EvaluationBudget
+	if budget <= 0 || budget >= len(p.Population) || p.SpeciesSet == nil || len(p.SpeciesSet.Species) == 0 {
+		return p.Population, nil
+	}
+
+	evaluate = make(map[int]*Genome, budget)
+	parentFitness := make(map[int]float64)
+	groups := make(map[int][]*Genome) // Map parent species key -> candidate genomes
+
+	// Sort keys for deterministic grouping before shuffling.
+	keys := make([]int, 0, len(p.Population))
+	for k := range p.Population {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	for _, key := range keys {
+		g := p.Population[key]
+		speciesKey := -1
+		fitnesses := []float64{}
+		parentKeys := p.Reproduction.Ancestors[key]
+		if _, survived := p.SpeciesSet.GenomeToSpecies[key]; survived {
+			parentKeys = []int{key} // Elites inherit their own fitness from last generation
+		}
+		for _, parentKey := range parentKeys {
+			sp, ok := p.SpeciesSet.GetSpecies(parentKey)
+			if !ok {
+				continue
+			}
+			parent, ok := sp.Members[parentKey]
+			if !ok {
+				continue
+			}
+			if speciesKey == -1 {
+				speciesKey = sp.Key
+			}
+			fitnesses = append(fitnesses, parent.Fitness)
+		}
+		if speciesKey == -1 {
+			// No parental fitness to inherit, so this genome must be evaluated.
+			evaluate[key] = g
+			continue
+		}
+		parentFitness[key] = Mean(fitnesses)
+		groups[speciesKey] = append(groups[speciesKey], g)
+	}
+
+	speciesKeys := make([]int, 0, len(groups))
+	for sid, members := range groups {
+		rand.Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+		speciesKeys = append(speciesKeys, sid)
+	}
+	sort.Ints(speciesKeys)
+
+	// Hand out the remaining budget one genome at a time, cycling through the species.
+	remaining := budget - len(evaluate)
+	next := make(map[int]int, len(speciesKeys))
+	for remaining > 0 {
+		assigned := false
+		for _, sid := range speciesKeys {
+			if remaining == 0 {
+				break
+			}
+			if next[sid] >= len(groups[sid]) {
+				continue // Every member of this species is already selected.
+			}
+			g := groups[sid][next[sid]]
+			next[sid]++
+			evaluate[g.Key] = g
+			remaining--
+			assigned = true
+		}
+		if !assigned {
+			break // Budget exceeds the number of candidates.
+		}
+	}
+
+	inherited = make(map[int]float64, len(p.Population)-len(evaluate))
+	for key, f := range parentFitness {
+		if _, ok := evaluate[key]; !ok {
+			inherited[key] = f
+		}
+	}
+	return evaluate, inherited
+}
@@ -0,0 +1,190 @@
+package neat
+
+import "fmt"
+
+// GenomeBuilder assembles a genome by hand, for tests, seeding (see NewPopulationFromGenomes) and
+// baseline topologies. Output nodes exist from the start; hidden nodes and connections are added
+// explicitly. Nodes get bias 0, response 1 and the configured default activation and aggregation
+// (the first option if the default is random); connections are enabled with the given weight.
+// Methods chain, and the first error is reported by Build:
+//
+//	g, err := neat.NewGenomeBuilder(&config.Genome).
+//		AddHidden(2).
+//		Connect(-1, 2, 1.0).Connect(-2, 2, 1.0).Connect(2, 0, -2.0).
+//		Bias(2, -1.5).
+//		Build()
+type GenomeBuilder struct {
+	config *GenomeConfig
+	genome *Genome
+	err    error
+}
+
+// NewGenomeBuilder starts a genome with key 0 containing only the config's output nodes.
+func NewGenomeBuilder(config *GenomeConfig) *GenomeBuilder {
+	b := &GenomeBuilder{config: config, genome: NewGenome(0, config)}
+	for _, key := range config.OutputKeys {
+		b.genome.Nodes[key] = b.newNode(key)
+	}
+	return b
+}
+
+func (b *GenomeBuilder) newNode(key int) *NodeGene {
+	ng := allocNodeGene()
+	*ng = NodeGene{
+		Key:             key,
+		Response:        1.0,
+		Activation:      defaultOption(b.config.ActivationDefault, b.config.ActivationOptions),
+		Aggregation:     defaultOption(b.config.AggregationDefault, b.config.AggregationOptions),
+		ActivationParam: 1.0,
+	}
+	if b.config.hasFrozenOutputs() && b.config.IsOutputKey(key) {
+		ng.Activation = b.config.OutputActivation
+	}
+	return ng
+}
+
+// defaultOption returns def if it is one of options, otherwise the first option.
+func defaultOption(def string, options []string) string {
+	for _, opt := range options {
+		if opt == def {
+			return def
+		}
+	}
+	if len(options) > 0 {
+		return options[0]
+	}
+	return def
+}
+
+func (b *GenomeBuilder) fail(format string, args ...any) *GenomeBuilder {
+	if b.err == nil {
+		b.err = fmt.Errorf("genome builder: "+format, args...)
+	}
+	return b
+}
+
+// node returns the node with the given key, recording an error if there is none.
+func (b *GenomeBuilder) node(key int) *NodeGene {
+	ng, ok := b.genome.Nodes[key]
+	if !ok {
+		b.fail("node %d does not exist", key)
+	}
+	return ng
+}
+
+// Key sets the genome key.
+func (b *GenomeBuilder) Key(key int) *GenomeBuilder {
+	b.genome.Key = key
+	return b
+}
+
+// AddHidden adds hidden nodes with the given keys, which must not be input or output keys.
+// Build moves the config's node key counter past them, so mutations never reuse them.
+func (b *GenomeBuilder) AddHidden(keys ...int) *GenomeBuilder {
+	for _, key := range keys {
+		if key < 0 || b.config.IsOutputKey(key) {
+			return b.fail("hidden node key %d collides with an input or output key", key)
+		}
+		if _, exists := b.genome.Nodes[key]; exists {
+			return b.fail("node %d already exists", key)
+		}
+		b.genome.Nodes[key] = b.newNode(key)
+	}
+	return b
+}
+
+// Connect adds an enabled connection from in (an input key or node) to out (a node).
+func (b *GenomeBuilder) Connect(in, out int, weight float64) *GenomeBuilder {
+	isInput := b.config.IsInputKey(in)
+	if _, ok := b.genome.Nodes[in]; !ok && !isInput {
+		return b.fail("connection source %d is neither an input nor a node", in)
+	}
+	if _, ok := b.genome.Nodes[out]; !ok {
+		return b.fail("connection target %d is not a node", out)
+	}
+	key := ConnectionKey{InNodeID: in, OutNodeID: out}
+	if _, exists := b.genome.Connections[key]; exists {
+		return b.fail("connection %d->%d already exists", in, out)
+	}
+	cg := allocConnectionGene()
+	*cg = ConnectionGene{Key: key, Weight: weight, Enabled: true, Expression: 1.0}
+	b.genome.Connections[key] = cg
+	return b
+}
+
+// Disable disables an existing connection.
+func (b *GenomeBuilder) Disable(in, out int) *GenomeBuilder {
+	cg, ok := b.genome.Connections[ConnectionKey{InNodeID: in, OutNodeID: out}]
+	if !ok {
+		return b.fail("connection %d->%d does not exist", in, out)
+	}
+	cg.Enabled = false
+	return b
+}
+
+// Bias sets the bias of a node. With bias_input, connect the bias input instead.
+func (b *GenomeBuilder) Bias(key int, bias float64) *GenomeBuilder {
+	if b.config.BiasInput {
+		return b.fail("node %d: node biases are disabled by bias_input; connect the bias input instead", key)
+	}
+	if ng := b.node(key); ng != nil {
+		ng.Bias = bias
+	}
+	return b
+}
+
+// Response sets the response of a node.
+func (b *GenomeBuilder) Response(key int, response float64) *GenomeBuilder {
+	if ng := b.node(key); ng != nil {
+		ng.Response = response
+	}
+	return b
+}
+
+// Activation sets the activation function of a node.
+func (b *GenomeBuilder) Activation(key int, name string) *GenomeBuilder {
+	if _, err := GetActivation(name); err != nil {
+		return b.fail("node %d: %w", key, err)
+	}
+	if ng := b.node(key); ng != nil {
+		ng.Activation = name
+	}
+	return b
+}
+
+// Aggregation sets the aggregation function of a node.
+func (b *GenomeBuilder) Aggregation(key int, name string) *GenomeBuilder {
+	if _, err := GetAggregation(name); err != nil {
+		return b.fail("node %d: %w", key, err)
+	}
+	if ng := b.node(key); ng != nil {
+		ng.Aggregation = name
+	}
+	return b
+}
+
+// Build returns the genome, or the first error made while building it. With feed_forward, a
+// genome whose enabled connections form a cycle is rejected. The builder must not be used after
+// a successful Build.
+func (b *GenomeBuilder) Build() (*Genome, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.config.FeedForward {
+		for key, cg := range b.genome.Connections {
+			if !cg.Enabled {
+				continue
+			}
+			cg.Enabled = false
+			cyclic := createsCycle(b.genome, key.InNodeID, key.OutNodeID)
+			cg.Enabled = true
+			if cyclic {
+				return nil, fmt.Errorf("genome builder: connection %d->%d closes a cycle in a feed-forward genome: %w", key.InNodeID, key.OutNodeID, ErrCycleDetected)
+			}
+		}
+	}
+	b.config.Innovations().ReserveGenome(b.genome)
+	g := b.genome
+	b.genome = nil
+	return g, nil
+}
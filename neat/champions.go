@@ -0,0 +1,162 @@
+package neat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// genomeFile is the JSON layout used when exporting genomes.
+type genomeFile struct {
+	Key         int              `json:"key"`
+	Species     int              `json:"species"`
+	Generation  int              `json:"generation"`
+	Fitness     float64          `json:"fitness"`
+	Nodes       []nodeFile       `json:"nodes"`
+	Connections []connectionFile `json:"connections"`
+}
+
+type nodeFile struct {
+	Key             int     `json:"key"`
+	Bias            float64 `json:"bias"`
+	Response        float64 `json:"response"`
+	Activation      string  `json:"activation"`
+	Aggregation     string  `json:"aggregation"`
+	ActivationParam float64 `json:"activation_param"`
+}
+
+type connectionFile struct {
+	In      int     `json:"in"`
+	Out     int     `json:"out"`
+	Weight  float64 `json:"weight"`
+	Enabled bool    `json:"enabled"`
+}
+
+// GenomeJSON encodes a genome as indented JSON, tagged with the species and generation it came from.
+func GenomeJSON(g *Genome, speciesKey, generation int) ([]byte, error) {
+	gf := genomeFile{Key: g.Key, Species: speciesKey, Generation: generation, Fitness: g.Fitness}
+	for _, k := range sortedNodeKeys(g) {
+		ng := g.Nodes[k]
+		gf.Nodes = append(gf.Nodes, nodeFile{
+			Key: k, Bias: ng.Bias, Response: ng.Response, Activation: ng.Activation,
+			Aggregation: ng.Aggregation, ActivationParam: ng.ActivationParam,
+		})
+	}
+	for _, ck := range sortedConnectionKeys(g) {
+		cg := g.Connections[ck]
+		gf.Connections = append(gf.Connections, connectionFile{In: ck.InNodeID, Out: ck.OutNodeID, Weight: cg.Weight, Enabled: cg.Enabled})
+	}
+	return json.MarshalIndent(gf, "", "  ")
+}
+
+// GenomeDOT renders a genome as a Graphviz digraph. Inputs are boxes, outputs double circles,
+// negative weights red and disabled connections dashed.
+func GenomeDOT(g *Genome) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph genome_%d {\n", g.Key)
+	b.WriteString("  rankdir=LR;\n")
+	inputs := []string{}
+	for _, k := range g.Config.InputKeys {
+		fmt.Fprintf(&b, "  \"%d\" [shape=box];\n", k)
+		inputs = append(inputs, fmt.Sprintf("\"%d\"", k))
+	}
+	outputs := make(map[int]bool, len(g.Config.OutputKeys))
+	for _, k := range g.Config.OutputKeys {
+		outputs[k] = true
+	}
+	for _, k := range sortedNodeKeys(g) {
+		ng := g.Nodes[k]
+		shape := "circle"
+		if outputs[k] {
+			shape = "doublecircle"
+		}
+		fmt.Fprintf(&b, "  \"%d\" [shape=%s, tooltip=\"%s/%s bias=%.3f\"];\n", k, shape, ng.Activation, ng.Aggregation, ng.Bias)
+	}
+	if len(inputs) > 0 {
+		fmt.Fprintf(&b, "  { rank=same; %s }\n", strings.Join(inputs, "; "))
+	}
+	for _, ck := range sortedConnectionKeys(g) {
+		cg := g.Connections[ck]
+		color := "green"
+		if cg.Weight < 0 {
+			color = "red"
+		}
+		style := "solid"
+		if !cg.Enabled {
+			style = "dashed"
+		}
+		fmt.Fprintf(&b, "  \"%d\" -> \"%d\" [label=\"%.3f\", color=%s, style=%s];\n", ck.InNodeID, ck.OutNodeID, cg.Weight, color, style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// --------------------------- ChampionReporter ---------------------------
+
+// ChampionReporter writes every species' champion to Dir at the end of each generation, as
+// gen-<generation>/species-<key>.json and/or .dot. It lets users follow how each species
+// approaches the problem over time. Write failures are logged rather than aborting the run.
+type ChampionReporter struct {
+	BaseReporter
+	Dir    string
+	JSON   bool   // Write species-<key>.json files
+	DOT    bool   // Write species-<key>.dot files
+	Logger Logger // Destination for write failures (nil = DefaultLogger)
+
+	generation int
+}
+
+// NewChampionReporter creates a reporter writing JSON and DOT champion files under dir.
+func NewChampionReporter(dir string) *ChampionReporter {
+	return &ChampionReporter{Dir: dir, JSON: true, DOT: true}
+}
+
+func (r *ChampionReporter) StartGeneration(generation int) {
+	r.generation = generation
+}
+
+func (r *ChampionReporter) EndGeneration(config *Config, population map[int]*Genome, speciesSet *SpeciesSet) {
+	if speciesSet == nil {
+		return
+	}
+	if err := r.WriteChampions(r.generation, speciesSet); err != nil {
+		loggerOrDefault(r.Logger).Warn("Failed to write species champions", "generation", r.generation, "error", err)
+	}
+}
+
+// WriteChampions writes the champions of speciesSet for the given generation.
+func (r *ChampionReporter) WriteChampions(generation int, speciesSet *SpeciesSet) error {
+	dir := filepath.Join(r.Dir, fmt.Sprintf("gen-%d", generation))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create champion directory '%s': %w", dir, err)
+	}
+	champions := speciesSet.Champions()
+	speciesKeys := make([]int, 0, len(champions))
+	for sid := range champions {
+		speciesKeys = append(speciesKeys, sid)
+	}
+	sort.Ints(speciesKeys)
+
+	for _, sid := range speciesKeys {
+		g := champions[sid]
+		base := filepath.Join(dir, fmt.Sprintf("species-%d", sid))
+		if r.JSON {
+			data, err := GenomeJSON(g, sid, generation)
+			if err != nil {
+				return fmt.Errorf("failed to encode champion of species %d: %w", sid, err)
+			}
+			if err := os.WriteFile(base+".json", data, 0644); err != nil {
+				return fmt.Errorf("failed to write champion of species %d: %w", sid, err)
+			}
+		}
+		if r.DOT {
+			if err := os.WriteFile(base+".dot", []byte(GenomeDOT(g)), 0644); err != nil {
+				return fmt.Errorf("failed to write champion graph of species %d: %w", sid, err)
+			}
+		}
+	}
+	return nil
+}
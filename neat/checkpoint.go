@@ -3,34 +3,60 @@ package neat
 import (
 	"compress/gzip"
 	"encoding/gob"
+	"errors"
 	"fmt" // Needed for Gob encoding/decoding of math/rand state
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 // PopulationSaveData is a helper struct to hold only the parts of Population needed for saving.
 // We don't save the full Config, as it's reloaded from the original file.
 // We also need to explicitly save the random number generator state.
 type PopulationSaveData struct {
-	Population   map[int]*Genome
-	SpeciesSet   *SpeciesSet
-	Reproduction *Reproduction // Includes NextGenomeKey and Ancestors
-	Generation   int
-	BestGenome   *Genome
+	Population    map[int]*Genome
+	SpeciesSet    *SpeciesSet
+	Reproduction  *Reproduction // Includes NextGenomeKey and Ancestors
+	Generation    int
+	BestGenome    *Genome
+	HallOfFame    []*Genome
+	Curriculum    *CurriculumState // Curriculum progress (nil if no curriculum was set)
+	Ratings       *Ratings         // Competitive ratings (nil if none were attached)
+	ConfigChanges []ConfigChange   // Changes made with UpdateConfig, replayed onto the reloaded config
+	Statistics    *Statistics      // Statistics recorded so far (nil if none were attached)
+	Version       int              // checkpointVersion of the writer (0 = before NodeGene.ActivationParam)
 	// RandState    []byte // Marshaled state of the default math/rand source (REMOVED for simplicity)
 }
 
+// checkpointVersion is the version of the PopulationSaveData written by SaveCheckpoint, so data
+// from older checkpoints can be told apart from legitimate zero values:
+//   - 1: NodeGene.ActivationParam is saved.
+const checkpointVersion = 1
+
+// CheckpointOptions controls how SaveCheckpoint writes a checkpoint.
+type CheckpointOptions struct {
+	// Format is "gob" or "binary" (empty = the config's checkpoint_format). Gob mirrors the Go
+	// structs, so renaming or retyping a field can make old checkpoints unreadable; the binary
+	// format tags every field explicitly, encodes the same state to the same bytes and tolerates
+	// fields added later (see checkpointbin.go). LoadCheckpoint reads either format.
+	Format string
+}
+
 // SaveCheckpoint saves the current state of the Population to a file.
-// Uses gzip compression for smaller file size.
-func (p *Population) SaveCheckpoint(filePath string) error {
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create checkpoint file '%s': %w", filePath, err)
+// Uses gzip compression for smaller file size. The file is replaced atomically, so a crash while
+// saving leaves the previous checkpoint intact; with checkpoint_backup it is also kept as filePath.bak.
+// At most one CheckpointOptions may be given.
+func (p *Population) SaveCheckpoint(filePath string, opts ...CheckpointOptions) error {
+	start := time.Now()
+	format := p.Config.Neat.CheckpointFormat
+	if len(opts) > 1 {
+		return fmt.Errorf("SaveCheckpoint accepts at most one CheckpointOptions")
+	}
+	if len(opts) == 1 && opts[0].Format != "" {
+		format = opts[0].Format
 	}
-	defer file.Close()
-
-	// Use gzip for compression
-	gzWriter := gzip.NewWriter(file)
-	defer gzWriter.Close()
 
 	// --- Prepare data for saving ---
 	/* // Removed Rand state saving
@@ -42,76 +68,91 @@ func (p *Population) SaveCheckpoint(filePath string) error {
 	    return fmt.Errorf("failed to marshal random state: %w", err)
 	}
 	*/
+	// The stagnation scheme is an interface and is rebuilt from the config on load, so don't encode it.
+	// Reporters are likewise runtime-only.
+	reproduction := *p.Reproduction
+	reproduction.Stagnation = nil
+	reproduction.Reporters = nil
 	saveData := PopulationSaveData{
-		Population:   p.Population,
-		SpeciesSet:   p.SpeciesSet,
-		Reproduction: p.Reproduction, // Includes NextGenomeKey
-		Generation:   p.Generation,
-		BestGenome:   p.BestGenome, // Might be nil
+		Population:    p.Population,
+		SpeciesSet:    p.SpeciesSet,
+		Reproduction:  &reproduction, // Includes NextGenomeKey
+		Generation:    p.Generation,
+		BestGenome:    p.BestGenome, // Might be nil
+		HallOfFame:    p.HallOfFame,
+		Ratings:       p.Ratings,
+		ConfigChanges: p.configChanges,
+		Statistics:    p.Statistics,
+		Version:       checkpointVersion,
 		// RandState:    randBytes, // Removed
 	}
-
-	// --- Register types needed for Gob encoding ---
-	// Gob needs to know about the concrete types being encoded, especially for interfaces
-	// or structs containing unexported fields (though ours should be okay here).
-	// Explicitly registering is good practice.
-	gob.Register(map[int]*Genome{})
-	gob.Register(map[ConnectionKey]*ConnectionGene{})
-	gob.Register(map[int]*NodeGene{})
-	gob.Register(map[int]*Species{})
-	gob.Register(map[int]int{})
-	gob.Register([]int{})
-	// Add other complex types used within Population, SpeciesSet, Reproduction if needed
+	if p.Curriculum != nil {
+		state := p.Curriculum.State
+		saveData.Curriculum = &state
+	}
 
 	// --- Encode the data ---
-	encoder := gob.NewEncoder(gzWriter)
-	err = encoder.Encode(saveData)
+	encode, err := checkpointEncoder(&saveData, format)
 	if err != nil {
-		return fmt.Errorf("failed to encode population data: %w", err)
+		return err
+	}
+	if err := writeCheckpointFile(filePath, encode, p.Config.Neat.CheckpointBackup); err != nil {
+		return err
 	}
 
-	fmt.Printf("Checkpoint saved to %s\n", filePath)
+	elapsed := time.Since(start)
+	p.recordPhase(PhaseCheckpoint, elapsed)
+	p.log().Info("Checkpoint saved", "path", filePath, "format", format, "duration", elapsed)
 	return nil
 }
 
+// checkpointEncoder returns a function writing data in the given checkpoint format.
+func checkpointEncoder(data *PopulationSaveData, format string) (func(w io.Writer) error, error) {
+	switch format {
+	case "", "gob":
+		// --- Register types needed for Gob encoding ---
+		// Gob needs to know about the concrete types being encoded, especially for interfaces
+		// or structs containing unexported fields (though ours should be okay here).
+		// Explicitly registering is good practice.
+		gob.Register(map[int]*Genome{})
+		gob.Register(map[ConnectionKey]*ConnectionGene{})
+		gob.Register(map[int]*NodeGene{})
+		gob.Register(map[int]*Species{})
+		gob.Register(map[int]int{})
+		gob.Register([]int{})
+		// Add other complex types used within Population, SpeciesSet, Reproduction if needed
+		return gobEncoder(data), nil
+	case "binary":
+		return func(w io.Writer) error { return writeBinaryCheckpoint(w, data) }, nil
+	default:
+		return nil, fmt.Errorf("invalid checkpoint format '%s', must be 'gob' or 'binary'", format)
+	}
+}
+
+// gobEncoder returns a function gob-encoding data.
+func gobEncoder(data any) func(w io.Writer) error {
+	return func(w io.Writer) error { return gob.NewEncoder(w).Encode(data) }
+}
+
 // LoadCheckpoint loads a Population state from a checkpoint file.
 // It requires the original configuration file path to reconstruct the Config object.
+// Statistics recorded before the checkpoint was saved are restored in Population.Statistics.
 func LoadCheckpoint(checkpointPath string, configPath string) (*Population, error) {
 	// 1. Load the configuration first.
 	config, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config '%s' for checkpoint: %w", configPath, err)
 	}
+	return loadCheckpoint(checkpointPath, config)
+}
 
-	// 2. Open the checkpoint file.
-	file, err := os.Open(checkpointPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open checkpoint file '%s': %w", checkpointPath, err)
-	}
-	defer file.Close()
-
-	// Use gzip for decompression
-	gzReader, err := gzip.NewReader(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create gzip reader for checkpoint: %w", err)
-	}
-	defer gzReader.Close()
-
-	// 3. Decode the saved data.
-	saveData := PopulationSaveData{}
-	decoder := gob.NewDecoder(gzReader)
-
-	// Register types for decoding (must match encoding)
-	gob.Register(map[int]*Genome{})
-	gob.Register(map[ConnectionKey]*ConnectionGene{})
-	gob.Register(map[int]*NodeGene{})
-	gob.Register(map[int]*Species{})
-	gob.Register(map[int]int{})
-	gob.Register([]int{})
-
-	err = decoder.Decode(&saveData)
+// loadCheckpoint rebuilds a Population from a checkpoint file around config, which must be freshly
+// loaded from the run's config file: the checkpoint's config changes are applied to it.
+func loadCheckpoint(checkpointPath string, config *Config) (*Population, error) {
+	// 2. Decode the saved data, in whichever format it was written.
+	saveData, _, err := readCheckpointData(checkpointPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode population data from checkpoint: %w", err)
+		return nil, err
 	}
 
 	/* // Removed Rand state loading
@@ -123,6 +164,11 @@ func LoadCheckpoint(checkpointPath string, configPath string) (*Population, erro
 	}
 	*/
 
+	// Parameters changed during the run override the values from the config file.
+	if err := applyConfigChanges(config, saveData.ConfigChanges); err != nil {
+		return nil, fmt.Errorf("failed to restore config changes from checkpoint: %w", err)
+	}
+
 	// 5. Reconstruct the Population object.
 	// Need to re-initialize Stagnation based on the loaded config.
 	stagnation, err := NewStagnation(&config.Stagnation)
@@ -130,9 +176,27 @@ func LoadCheckpoint(checkpointPath string, configPath string) (*Population, erro
 		return nil, fmt.Errorf("failed to re-initialize stagnation from loaded config: %w", err)
 	}
 
+	reporters := NewReporterSet()
+	stagnation.Reporters = reporters
+
 	// Set the stagnation reference in the loaded Reproduction object
 	if saveData.Reproduction != nil {
+		saveData.Reproduction.Config = &config.Reproduction
 		saveData.Reproduction.Stagnation = stagnation
+		saveData.Reproduction.Reporters = reporters
+		if saveData.Reproduction.Ancestors == nil {
+			saveData.Reproduction.Ancestors = make(map[int][]int) // Dropped by CompactCheckpoint
+		}
+		if saveData.Reproduction.AncestorBorn == nil {
+			saveData.Reproduction.AncestorBorn = make(map[int]int)
+		}
+	}
+
+	// Checkpoints written before NodeGene.ActivationParam existed decode it as zero.
+	restore := func(g *Genome) {
+		if saveData.Version < 1 {
+			restoreActivationParams(g)
+		}
 	}
 
 	// Assign loaded config to genomes (Gob doesn't save/restore unexported or complex fields like pointers well by default)
@@ -140,27 +204,162 @@ func LoadCheckpoint(checkpointPath string, configPath string) (*Population, erro
 	if saveData.Population != nil {
 		for _, genome := range saveData.Population {
 			genome.Config = &config.Genome // Re-link the GenomeConfig part
+			restore(genome)
+			config.Genome.Innovations().ReserveGenome(genome) // New nodes must not reuse loaded keys
 		}
 	}
 	if saveData.BestGenome != nil {
 		saveData.BestGenome.Config = &config.Genome // Re-link config for best genome too
+		restore(saveData.BestGenome)
+		config.Genome.Innovations().ReserveGenome(saveData.BestGenome)
+	}
+	for _, genome := range saveData.HallOfFame {
+		genome.Config = &config.Genome
+		restore(genome)
+		config.Genome.Innovations().ReserveGenome(genome)
 	}
 	// Also need to re-link GenomeConfig to the DistanceCache within SpeciesSet if it was saved
 	if saveData.SpeciesSet != nil {
+		saveData.SpeciesSet.Config = &config.SpeciesSet
 		// SpeciesSet wasn't part of PopulationSaveData initially, let's assume it's loaded correctly for now
 		// If distance cache needs config, it should be re-initialized or re-linked here.
+		// Checkpoints written before BestFitness existed decode it as zero; recover it from the history.
+		for _, sp := range saveData.SpeciesSet.Species {
+			if sp.BestFitness == 0 && len(sp.FitnessHistory) > 0 {
+				sp.BestFitness = MaxFloat(sp.FitnessHistory)
+			}
+			// Representatives are compared with new genomes during speciation. They and the members
+			// may hold nodes no longer in the population, whose keys must not be reused either.
+			if sp.Representative != nil {
+				sp.Representative.Config = &config.Genome
+				restore(sp.Representative)
+				config.Genome.Innovations().ReserveGenome(sp.Representative)
+			}
+			for _, genome := range sp.Members {
+				genome.Config = &config.Genome
+				restore(genome)
+				config.Genome.Innovations().ReserveGenome(genome)
+			}
+			if sp.Champion != nil {
+				sp.Champion.Config = &config.Genome
+				restore(sp.Champion)
+				config.Genome.Innovations().ReserveGenome(sp.Champion)
+			}
+		}
 	}
 
 	p := &Population{
-		Config:       config, // Use the newly loaded config
-		Population:   saveData.Population,
-		SpeciesSet:   saveData.SpeciesSet,
-		Reproduction: saveData.Reproduction,
-		Stagnation:   stagnation, // Use the re-initialized stagnation manager
-		Generation:   saveData.Generation,
-		BestGenome:   saveData.BestGenome,
+		Config:             config, // Use the newly loaded config
+		Population:         saveData.Population,
+		SpeciesSet:         saveData.SpeciesSet,
+		Reproduction:       saveData.Reproduction,
+		Stagnation:         stagnation, // Use the re-initialized stagnation manager
+		Generation:         saveData.Generation,
+		BestGenome:         saveData.BestGenome,
+		HallOfFame:         saveData.HallOfFame,
+		Ratings:            saveData.Ratings,
+		Statistics:         saveData.Statistics,
+		Reporters:          reporters,
+		restoredCurriculum: saveData.Curriculum,
+		configChanges:      saveData.ConfigChanges,
 	}
 
-	fmt.Printf("Checkpoint loaded from %s (Generation %d)\n", checkpointPath, p.Generation)
+	if config.Neat.EvaluationCacheSize > 0 {
+		p.EvaluationCache = NewEvaluationCache(config.Neat.EvaluationCacheSize)
+	}
+	if config.Neat.PhenotypeCache {
+		p.PhenotypeCache = NewPhenotypeCache()
+	}
+
+	p.log().Info("Checkpoint loaded", "path", checkpointPath, "generation", p.Generation)
 	return p, nil
 }
+
+// restoreActivationParams sets the standard activation parameter on the nodes of a genome from a
+// checkpoint written before NodeGene.ActivationParam existed, which decode it as zero.
+func restoreActivationParams(g *Genome) {
+	for _, ng := range g.Nodes {
+		if ng.ActivationParam == 0 {
+			ng.ActivationParam = 1.0
+		}
+	}
+}
+
+// writeCheckpointFile writes a gzip-compressed file at path with encode without ever leaving a
+// partially written checkpoint there: the data is streamed to a temporary file in the same
+// directory, synced to disk and then renamed over path. With backup set, the checkpoint being
+// replaced is kept as path.bak.
+func writeCheckpointFile(path string, encode func(w io.Writer) error, backup bool) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file '%s': %w", path, err)
+	}
+	tmpPath := tmp.Name()
+	fail := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	gzWriter := gzip.NewWriter(tmp)
+	if err := encode(gzWriter); err != nil {
+		return fail(fmt.Errorf("failed to encode checkpoint data: %w", err))
+	}
+	if err := gzWriter.Close(); err != nil {
+		return fail(fmt.Errorf("failed to write checkpoint file '%s': %w", path, err))
+	}
+	if err := tmp.Chmod(0644); err != nil { // CreateTemp uses 0600
+		return fail(fmt.Errorf("failed to write checkpoint file '%s': %w", path, err))
+	}
+	if err := tmp.Sync(); err != nil {
+		return fail(fmt.Errorf("failed to sync checkpoint file '%s': %w", path, err))
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write checkpoint file '%s': %w", path, err)
+	}
+
+	if backup {
+		if err := backupCheckpoint(path); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace checkpoint file '%s': %w", path, err)
+	}
+	syncDir(filepath.Dir(path))
+	return nil
+}
+
+// backupCheckpoint preserves the current checkpoint at path as path.bak. A hard link keeps path in
+// place until the new checkpoint is renamed over it; filesystems without hard links fall back to a
+// rename, which leaves only the backup if the process dies before the new file is in place.
+func backupCheckpoint(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	backupPath := path + ".bak"
+	if err := os.Remove(backupPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove old checkpoint backup '%s': %w", backupPath, err)
+	}
+	if err := os.Link(path, backupPath); err == nil {
+		return nil
+	}
+	if err := os.Rename(path, backupPath); err != nil {
+		return fmt.Errorf("failed to back up checkpoint '%s': %w", path, err)
+	}
+	return nil
+}
+
+// syncDir flushes a directory entry change (such as a rename) to disk. Errors are ignored: not every
+// platform supports syncing directories, and the rename itself has already succeeded.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
@@ -0,0 +1,63 @@
+package neat
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestCheckpointActivationParams checks that an evolved activation parameter of 0 survives a
+// checkpoint, while checkpoints written before the parameter existed get the standard 1.0.
+func TestCheckpointActivationParams(t *testing.T) {
+	config := loadTestConfig(t)
+	config.Neat.PopSize = 10
+	p, err := NewPopulation(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetLogger(NopLogger{})
+	key := sortedKeys(p.Population)[0]
+	p.Population[key].Nodes[0].ActivationParam = 0
+	member := grownGenome(t, config, p.Reproduction.NextKey(), 0) // Only a species member
+	member.Nodes[0].ActivationParam = 0
+	sp := NewSpecies(1, 0)
+	sp.Representative = p.Population[key]
+	sp.Members = map[int]*Genome{key: p.Population[key], member.Key: member}
+	p.SpeciesSet.Species[1] = sp
+
+	for _, format := range []string{"gob", "binary"} {
+		path := filepath.Join(t.TempDir(), "checkpoint.gz")
+		if err := p.SaveCheckpoint(path, CheckpointOptions{Format: format}); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadCheckpoint(path, "testdata/config.ini")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := loaded.Population[key].Nodes[0].ActivationParam; got != 0 {
+			t.Errorf("%s: population activation parameter 0 loaded as %v", format, got)
+		}
+		if got := loaded.SpeciesSet.Species[1].Members[member.Key].Nodes[0].ActivationParam; got != 0 {
+			t.Errorf("%s: member activation parameter 0 loaded as %v", format, got)
+		}
+	}
+
+	// A gob checkpoint without a version predates the parameter.
+	path := filepath.Join(t.TempDir(), "old.gz")
+	reproduction := *p.Reproduction
+	reproduction.Stagnation = nil
+	reproduction.Reporters = nil
+	old := PopulationSaveData{Population: p.Population, SpeciesSet: p.SpeciesSet, Reproduction: &reproduction}
+	if err := writeCheckpointFile(path, gobEncoder(&old), false); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCheckpoint(path, "testdata/config.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Population[key].Nodes[0].ActivationParam; got != 1 {
+		t.Errorf("old checkpoint: population activation parameter loaded as %v, want 1", got)
+	}
+	if got := loaded.SpeciesSet.Species[1].Members[member.Key].Nodes[0].ActivationParam; got != 1 {
+		t.Errorf("old checkpoint: member activation parameter loaded as %v, want 1", got)
+	}
+}
@@ -0,0 +1,974 @@
+package neat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// Binary checkpoint format
+//
+// SaveCheckpoint with format "binary" writes a gzip stream holding binaryCheckpointMagic, a format
+// version byte and one message encoding the PopulationSaveData. A message is a sequence of fields,
+// each a uvarint key (tag<<3 | wire type) followed by its value:
+//
+//	wireVarint:  a varint; ints are zigzag-encoded, bools are 1
+//	wireFixed64: 8 little-endian bytes holding a float64
+//	wireBytes:   a uvarint length and that many bytes: a string, packed list or nested message
+//
+// Fields with zero values are omitted and map entries are written in key order, so the same state
+// always encodes to the same bytes. Readers skip fields with unknown tags, so fields can be added
+// without breaking older checkpoints or readers; a tag is never reused for a different field. The
+// tags of every message are listed with its encode function below.
+const (
+	binaryCheckpointMagic   = "NEATCKPT"
+	binaryCheckpointVersion = 1
+
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// errCorruptCheckpoint is returned for binary checkpoints that cannot be parsed.
+var errCorruptCheckpoint = fmt.Errorf("corrupt binary checkpoint")
+
+// binEncoder appends the fields of one message to buf.
+type binEncoder struct {
+	buf []byte
+}
+
+func (e *binEncoder) putKey(tag, wire int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(tag)<<3|uint64(wire))
+}
+
+func (e *binEncoder) putInt(tag, v int) {
+	if v != 0 {
+		e.putKey(tag, wireVarint)
+		e.buf = binary.AppendVarint(e.buf, int64(v))
+	}
+}
+
+func (e *binEncoder) putFloat(tag int, v float64) {
+	if bits := math.Float64bits(v); bits != 0 {
+		e.putKey(tag, wireFixed64)
+		e.buf = binary.LittleEndian.AppendUint64(e.buf, bits)
+	}
+}
+
+func (e *binEncoder) putBool(tag int, v bool) {
+	if v {
+		e.putKey(tag, wireVarint)
+		e.buf = append(e.buf, 1)
+	}
+}
+
+func (e *binEncoder) putString(tag int, v string) {
+	if v != "" {
+		e.putKey(tag, wireBytes)
+		e.buf = binary.AppendUvarint(e.buf, uint64(len(v)))
+		e.buf = append(e.buf, v...)
+	}
+}
+
+func (e *binEncoder) putFloats(tag int, v []float64) {
+	if len(v) > 0 {
+		e.putKey(tag, wireBytes)
+		e.buf = binary.AppendUvarint(e.buf, uint64(8*len(v)))
+		for _, f := range v {
+			e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(f))
+		}
+	}
+}
+
+func (e *binEncoder) putInts(tag int, v []int) {
+	if len(v) > 0 {
+		var packed []byte
+		for _, n := range v {
+			packed = binary.AppendVarint(packed, int64(n))
+		}
+		e.putKey(tag, wireBytes)
+		e.buf = binary.AppendUvarint(e.buf, uint64(len(packed)))
+		e.buf = append(e.buf, packed...)
+	}
+}
+
+// putStrings writes one field per string, including empty ones, so positions are kept.
+func (e *binEncoder) putStrings(tag int, v []string) {
+	for _, str := range v {
+		e.putKey(tag, wireBytes)
+		e.buf = binary.AppendUvarint(e.buf, uint64(len(str)))
+		e.buf = append(e.buf, str...)
+	}
+}
+
+// putMessage writes a nested message, even if it is empty: its presence is meaningful.
+func (e *binEncoder) putMessage(tag int, encode func(e *binEncoder)) {
+	var sub binEncoder
+	encode(&sub)
+	e.putKey(tag, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(sub.buf)))
+	e.buf = append(e.buf, sub.buf...)
+}
+
+// binField is one decoded field of a message.
+type binField struct {
+	tag  int
+	wire int
+	n    uint64 // Value of a varint or fixed64 field
+	data []byte // Payload of a bytes field
+	err  *error // Set when the field is read as the wrong type
+}
+
+func (f binField) expect(wire int) bool {
+	if f.wire != wire {
+		if *f.err == nil {
+			*f.err = fmt.Errorf("%w: field %d has wire type %d, expected %d", errCorruptCheckpoint, f.tag, f.wire, wire)
+		}
+		return false
+	}
+	return true
+}
+
+func (f binField) int() int {
+	if !f.expect(wireVarint) {
+		return 0
+	}
+	return int(int64(f.n>>1) ^ -int64(f.n&1))
+}
+
+func (f binField) float() float64 {
+	if !f.expect(wireFixed64) {
+		return 0
+	}
+	return math.Float64frombits(f.n)
+}
+
+func (f binField) bool() bool {
+	return f.expect(wireVarint) && f.n != 0
+}
+
+func (f binField) string() string {
+	if !f.expect(wireBytes) {
+		return ""
+	}
+	return string(f.data)
+}
+
+func (f binField) floats() []float64 {
+	if !f.expect(wireBytes) {
+		return nil
+	}
+	if len(f.data)%8 != 0 {
+		*f.err = fmt.Errorf("%w: packed floats of field %d have %d bytes", errCorruptCheckpoint, f.tag, len(f.data))
+		return nil
+	}
+	v := make([]float64, len(f.data)/8)
+	for i := range v {
+		v[i] = math.Float64frombits(binary.LittleEndian.Uint64(f.data[8*i:]))
+	}
+	return v
+}
+
+func (f binField) ints() []int {
+	if !f.expect(wireBytes) {
+		return nil
+	}
+	var v []int
+	for data := f.data; len(data) > 0; {
+		n, size := binary.Varint(data)
+		if size <= 0 {
+			*f.err = fmt.Errorf("%w: bad packed ints in field %d", errCorruptCheckpoint, f.tag)
+			return nil
+		}
+		v = append(v, int(n))
+		data = data[size:]
+	}
+	return v
+}
+
+// message decodes the field as a nested message.
+func (f binField) message(decode func(f binField)) {
+	if f.expect(wireBytes) {
+		if err := decodeMessage(f.data, decode); err != nil && *f.err == nil {
+			*f.err = err
+		}
+	}
+}
+
+// decodeMessage calls decode for every field of the message in data, in order. Fields whose tag
+// decode does not handle are skipped.
+func decodeMessage(data []byte, decode func(f binField)) error {
+	var err error
+	for len(data) > 0 && err == nil {
+		key, size := binary.Uvarint(data)
+		if size <= 0 {
+			return fmt.Errorf("%w: bad field key", errCorruptCheckpoint)
+		}
+		data = data[size:]
+		f := binField{tag: int(key >> 3), wire: int(key & 7), err: &err}
+		switch f.wire {
+		case wireVarint:
+			if f.n, size = binary.Uvarint(data); size <= 0 {
+				return fmt.Errorf("%w: bad varint in field %d", errCorruptCheckpoint, f.tag)
+			}
+			data = data[size:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("%w: truncated field %d", errCorruptCheckpoint, f.tag)
+			}
+			f.n = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case wireBytes:
+			length, size := binary.Uvarint(data)
+			if size <= 0 || length > uint64(len(data)-size) {
+				return fmt.Errorf("%w: truncated field %d", errCorruptCheckpoint, f.tag)
+			}
+			f.data = data[size : size+int(length)]
+			data = data[size+int(length):]
+		default:
+			return fmt.Errorf("%w: unknown wire type %d in field %d", errCorruptCheckpoint, f.wire, f.tag)
+		}
+		decode(f)
+	}
+	return err
+}
+
+// sortedKeys returns the keys of m in increasing order.
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// writeBinaryCheckpoint writes data in the binary checkpoint format to w. The format has no place
+// for Genome.Extension, so it fails rather than drop the extension of a genome.
+func writeBinaryCheckpoint(w io.Writer, data *PopulationSaveData) error {
+	if g := extendedGenome(data); g != nil {
+		return fmt.Errorf("genome %d has an extension, which binary checkpoints can't save; use the gob format and gob.Register the extension type", g.Key)
+	}
+	var e binEncoder
+	e.buf = append(e.buf, binaryCheckpointMagic...)
+	e.buf = append(e.buf, binaryCheckpointVersion)
+	encodeSaveData(&e, data)
+	_, err := w.Write(e.buf)
+	return err
+}
+
+// extendedGenome returns a genome of data with an extension, or nil if there is none.
+func extendedGenome(data *PopulationSaveData) *Genome {
+	genomes := make([]*Genome, 0, len(data.Population)+len(data.HallOfFame)+1)
+	for _, g := range data.Population {
+		genomes = append(genomes, g)
+	}
+	genomes = append(genomes, data.BestGenome)
+	genomes = append(genomes, data.HallOfFame...)
+	if data.SpeciesSet != nil {
+		for _, sp := range data.SpeciesSet.Species {
+			genomes = append(genomes, sp.Representative, sp.Champion)
+			for _, g := range sp.Members {
+				genomes = append(genomes, g)
+			}
+		}
+	}
+	for _, g := range genomes {
+		if g != nil && g.Extension != nil {
+			return g
+		}
+	}
+	return nil
+}
+
+// readBinaryCheckpoint decodes a binary checkpoint, starting with its magic bytes, from data.
+func readBinaryCheckpoint(data []byte) (*PopulationSaveData, error) {
+	header := len(binaryCheckpointMagic) + 1
+	if len(data) < header || string(data[:len(binaryCheckpointMagic)]) != binaryCheckpointMagic {
+		return nil, fmt.Errorf("%w: missing header", errCorruptCheckpoint)
+	}
+	if version := data[header-1]; version > binaryCheckpointVersion {
+		return nil, fmt.Errorf("binary checkpoint format version %d is newer than the supported version %d", version, binaryCheckpointVersion)
+	}
+	return decodeSaveData(data[header:])
+}
+
+// encodeSaveData writes the checkpoint message. Tags: 1 Generation, 2 Population (one Genome per
+// field), 3 SpeciesSet, 4 Reproduction, 5 BestGenome, 6 HallOfFame, 7 Curriculum, 8 Ratings,
+// 9 ConfigChanges (one per field), 10 Statistics, 11 Version. Every binary checkpoint saves
+// NodeGene.ActivationParam, so one without a Version is of version 1.
+func encodeSaveData(e *binEncoder, d *PopulationSaveData) {
+	e.putInt(1, d.Generation)
+	for _, key := range sortedKeys(d.Population) {
+		g := d.Population[key]
+		e.putMessage(2, func(e *binEncoder) { encodeGenome(e, g) })
+	}
+	if d.SpeciesSet != nil {
+		e.putMessage(3, func(e *binEncoder) { encodeSpeciesSet(e, d.SpeciesSet, d.Population) })
+	}
+	if d.Reproduction != nil {
+		e.putMessage(4, func(e *binEncoder) { encodeReproduction(e, d.Reproduction) })
+	}
+	if d.BestGenome != nil {
+		e.putMessage(5, func(e *binEncoder) { encodeGenome(e, d.BestGenome) })
+	}
+	for _, g := range d.HallOfFame {
+		e.putMessage(6, func(e *binEncoder) { encodeGenome(e, g) })
+	}
+	if d.Curriculum != nil {
+		e.putMessage(7, func(e *binEncoder) { encodeCurriculum(e, d.Curriculum) })
+	}
+	if d.Ratings != nil {
+		e.putMessage(8, func(e *binEncoder) { encodeRatings(e, d.Ratings) })
+	}
+	for _, c := range d.ConfigChanges {
+		e.putMessage(9, func(e *binEncoder) { encodeConfigChange(e, c) })
+	}
+	if d.Statistics != nil {
+		e.putMessage(10, func(e *binEncoder) { encodeStatistics(e, d.Statistics) })
+	}
+	e.putInt(11, d.Version)
+}
+
+// decodeSaveData reads the checkpoint message. Genome and species keys restore the maps they are
+// stored in, and species members stored by key are resolved against the population.
+func decodeSaveData(data []byte) (*PopulationSaveData, error) {
+	d := &PopulationSaveData{Population: make(map[int]*Genome), Version: 1}
+	memberKeys := make(map[*Species][]int)
+	err := decodeMessage(data, func(f binField) {
+		switch f.tag {
+		case 1:
+			d.Generation = f.int()
+		case 2:
+			g := decodeGenome(f)
+			d.Population[g.Key] = g
+		case 3:
+			d.SpeciesSet = decodeSpeciesSet(f, memberKeys)
+		case 4:
+			d.Reproduction = decodeReproduction(f)
+		case 5:
+			d.BestGenome = decodeGenome(f)
+		case 6:
+			d.HallOfFame = append(d.HallOfFame, decodeGenome(f))
+		case 7:
+			d.Curriculum = decodeCurriculum(f)
+		case 8:
+			d.Ratings = decodeRatings(f)
+		case 9:
+			d.ConfigChanges = append(d.ConfigChanges, decodeConfigChange(f))
+		case 10:
+			d.Statistics = decodeStatistics(f)
+		case 11:
+			d.Version = f.int()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for sp, keys := range memberKeys {
+		for _, key := range keys {
+			g, ok := d.Population[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %d of species %d is not in the population", errCorruptCheckpoint, key, sp.Key)
+			}
+			sp.Members[key] = g
+		}
+	}
+	return d, nil
+}
+
+// encodeGenome writes a genome. Tags: 1 Key, 2 Fitness, 3 Fitnesses, 4 Behavior, 5 Nodes (one per
+// field: 1 Key, 2 Bias, 3 Response, 4 Activation, 5 Aggregation, 6 ActivationParam),
+// 6 Connections (one per field: 1 InNodeID, 2 OutNodeID, 3 Weight, 4 Enabled, 5 Expression,
+// 6 DisabledFor).
+func encodeGenome(e *binEncoder, g *Genome) {
+	e.putInt(1, g.Key)
+	e.putFloat(2, g.Fitness)
+	e.putFloats(3, g.Fitnesses)
+	e.putFloats(4, g.Behavior)
+	for _, key := range sortedNodeKeys(g) {
+		n := g.Nodes[key]
+		e.putMessage(5, func(e *binEncoder) {
+			e.putInt(1, n.Key)
+			e.putFloat(2, n.Bias)
+			e.putFloat(3, n.Response)
+			e.putString(4, n.Activation)
+			e.putString(5, n.Aggregation)
+			e.putFloat(6, n.ActivationParam)
+		})
+	}
+	for _, key := range sortedConnectionKeys(g) {
+		c := g.Connections[key]
+		e.putMessage(6, func(e *binEncoder) {
+			e.putInt(1, c.Key.InNodeID)
+			e.putInt(2, c.Key.OutNodeID)
+			e.putFloat(3, c.Weight)
+			e.putBool(4, c.Enabled)
+			e.putFloat(5, c.Expression)
+			e.putInt(6, c.DisabledFor)
+		})
+	}
+}
+
+// decodeGenome reads a genome written by encodeGenome. Its Config is left for the caller to link.
+func decodeGenome(f binField) *Genome {
+	g := NewGenome(0, nil)
+	f.message(func(f binField) {
+		switch f.tag {
+		case 1:
+			g.Key = f.int()
+		case 2:
+			g.Fitness = f.float()
+		case 3:
+			g.Fitnesses = f.floats()
+		case 4:
+			g.Behavior = f.floats()
+		case 5:
+			n := &NodeGene{}
+			f.message(func(f binField) {
+				switch f.tag {
+				case 1:
+					n.Key = f.int()
+				case 2:
+					n.Bias = f.float()
+				case 3:
+					n.Response = f.float()
+				case 4:
+					n.Activation = f.string()
+				case 5:
+					n.Aggregation = f.string()
+				case 6:
+					n.ActivationParam = f.float()
+				}
+			})
+			g.Nodes[n.Key] = n
+		case 6:
+			c := &ConnectionGene{}
+			f.message(func(f binField) {
+				switch f.tag {
+				case 1:
+					c.Key.InNodeID = f.int()
+				case 2:
+					c.Key.OutNodeID = f.int()
+				case 3:
+					c.Weight = f.float()
+				case 4:
+					c.Enabled = f.bool()
+				case 5:
+					c.Expression = f.float()
+				case 6:
+					c.DisabledFor = f.int()
+				}
+			})
+			g.Connections[c.Key] = c
+		}
+	})
+	return g
+}
+
+// encodeSpeciesSet writes the species set. Tags: 1 Indexer, 2 Species (one per field),
+// 3 GenomeToSpecies (one entry per field: 1 genome key, 2 species key).
+//
+// Species tags: 1 Key, 2 Created, 3 LastImproved, 4 Representative, 5 keys of the members that
+// are in the population, 6 Fitness, 7 AdjustedFitness, 8 FitnessHistory, 9 BestFitness, 10 members
+// that are not in the population (one Genome per field), 11 Champion, 12 ChampionGeneration.
+func encodeSpeciesSet(e *binEncoder, ss *SpeciesSet, population map[int]*Genome) {
+	e.putInt(1, ss.Indexer)
+	for _, key := range sortedKeys(ss.Species) {
+		sp := ss.Species[key]
+		e.putMessage(2, func(e *binEncoder) {
+			e.putInt(1, sp.Key)
+			e.putInt(2, sp.Created)
+			e.putInt(3, sp.LastImproved)
+			if sp.Representative != nil {
+				e.putMessage(4, func(e *binEncoder) { encodeGenome(e, sp.Representative) })
+			}
+			var shared []int
+			var own []*Genome
+			for _, key := range sortedKeys(sp.Members) {
+				if g := sp.Members[key]; population[key] == g {
+					shared = append(shared, key)
+				} else {
+					own = append(own, g)
+				}
+			}
+			e.putInts(5, shared)
+			e.putFloat(6, sp.Fitness)
+			e.putFloat(7, sp.AdjustedFitness)
+			e.putFloats(8, sp.FitnessHistory)
+			e.putFloat(9, sp.BestFitness)
+			for _, g := range own {
+				e.putMessage(10, func(e *binEncoder) { encodeGenome(e, g) })
+			}
+			if sp.Champion != nil {
+				e.putMessage(11, func(e *binEncoder) { encodeGenome(e, sp.Champion) })
+				e.putInt(12, sp.ChampionGeneration)
+			}
+		})
+	}
+	for _, key := range sortedKeys(ss.GenomeToSpecies) {
+		e.putMessage(3, func(e *binEncoder) {
+			e.putInt(1, key)
+			e.putInt(2, ss.GenomeToSpecies[key])
+		})
+	}
+}
+
+// decodeSpeciesSet reads a species set written by encodeSpeciesSet. Member keys are collected in
+// memberKeys for resolution once the population is known. Its Config is left for the caller.
+func decodeSpeciesSet(f binField, memberKeys map[*Species][]int) *SpeciesSet {
+	ss := &SpeciesSet{Species: make(map[int]*Species), GenomeToSpecies: make(map[int]int)}
+	f.message(func(f binField) {
+		switch f.tag {
+		case 1:
+			ss.Indexer = f.int()
+		case 2:
+			sp := &Species{Members: make(map[int]*Genome)}
+			f.message(func(f binField) {
+				switch f.tag {
+				case 1:
+					sp.Key = f.int()
+				case 2:
+					sp.Created = f.int()
+				case 3:
+					sp.LastImproved = f.int()
+				case 4:
+					sp.Representative = decodeGenome(f)
+				case 5:
+					memberKeys[sp] = append(memberKeys[sp], f.ints()...)
+				case 6:
+					sp.Fitness = f.float()
+				case 7:
+					sp.AdjustedFitness = f.float()
+				case 8:
+					sp.FitnessHistory = f.floats()
+				case 9:
+					sp.BestFitness = f.float()
+				case 10:
+					g := decodeGenome(f)
+					sp.Members[g.Key] = g
+				case 11:
+					sp.Champion = decodeGenome(f)
+				case 12:
+					sp.ChampionGeneration = f.int()
+				}
+			})
+			ss.Species[sp.Key] = sp
+		case 3:
+			var genome, species int
+			f.message(func(f binField) {
+				switch f.tag {
+				case 1:
+					genome = f.int()
+				case 2:
+					species = f.int()
+				}
+			})
+			ss.GenomeToSpecies[genome] = species
+		}
+	})
+	return ss
+}
+
+// encodeReproduction writes the reproduction state. Tags: 1 NextGenomeKey, 2 Ancestors (one entry
+// per field: 1 genome key, 2 parent keys), 3 AncestorBorn (one entry per field: 1 genome key,
+// 2 generation).
+func encodeReproduction(e *binEncoder, r *Reproduction) {
+	e.putInt(1, r.NextGenomeKey)
+	for _, key := range sortedKeys(r.Ancestors) {
+		e.putMessage(2, func(e *binEncoder) {
+			e.putInt(1, key)
+			e.putInts(2, r.Ancestors[key])
+		})
+	}
+	for _, key := range sortedKeys(r.AncestorBorn) {
+		e.putMessage(3, func(e *binEncoder) {
+			e.putInt(1, key)
+			e.putInt(2, r.AncestorBorn[key])
+		})
+	}
+}
+
+// decodeReproduction reads the reproduction state; its Config, Stagnation and Reporters are left
+// for the caller.
+func decodeReproduction(f binField) *Reproduction {
+	r := &Reproduction{Ancestors: make(map[int][]int), AncestorBorn: make(map[int]int)}
+	f.message(func(f binField) {
+		switch f.tag {
+		case 1:
+			r.NextGenomeKey = f.int()
+		case 2:
+			var key int
+			var parents []int
+			f.message(func(f binField) {
+				switch f.tag {
+				case 1:
+					key = f.int()
+				case 2:
+					parents = f.ints()
+				}
+			})
+			r.Ancestors[key] = parents
+		case 3:
+			var key, born int
+			f.message(func(f binField) {
+				switch f.tag {
+				case 1:
+					key = f.int()
+				case 2:
+					born = f.int()
+				}
+			})
+			r.AncestorBorn[key] = born
+		}
+	})
+	return r
+}
+
+// encodeConfigChange writes a config change. Tags: 1 Generation, 2 Key, 3 Old, 4 New.
+func encodeConfigChange(e *binEncoder, c ConfigChange) {
+	e.putInt(1, c.Generation)
+	e.putString(2, c.Key)
+	e.putFloat(3, c.Old)
+	e.putFloat(4, c.New)
+}
+
+// decodeConfigChange reads a config change written by encodeConfigChange.
+func decodeConfigChange(f binField) ConfigChange {
+	var c ConfigChange
+	f.message(func(f binField) {
+		switch f.tag {
+		case 1:
+			c.Generation = f.int()
+		case 2:
+			c.Key = f.string()
+		case 3:
+			c.Old = f.float()
+		case 4:
+			c.New = f.float()
+		}
+	})
+	return c
+}
+
+// encodeStageTransition writes a curriculum promotion. Tags: 1 Generation, 2 From, 3 To.
+func encodeStageTransition(e *binEncoder, t StageTransition) {
+	e.putInt(1, t.Generation)
+	e.putInt(2, t.From)
+	e.putInt(3, t.To)
+}
+
+// decodeStageTransition reads a promotion written by encodeStageTransition.
+func decodeStageTransition(f binField) StageTransition {
+	var t StageTransition
+	f.message(func(f binField) {
+		switch f.tag {
+		case 1:
+			t.Generation = f.int()
+		case 2:
+			t.From = f.int()
+		case 3:
+			t.To = f.int()
+		}
+	})
+	return t
+}
+
+// encodeCurriculum writes the curriculum state. Tags: 1 Stage, 2 MeanFitness, 3 BestFitness,
+// 4 Transitions (one per field).
+func encodeCurriculum(e *binEncoder, c *CurriculumState) {
+	e.putInt(1, c.Stage)
+	e.putFloats(2, c.MeanFitness)
+	e.putFloats(3, c.BestFitness)
+	for _, t := range c.Transitions {
+		e.putMessage(4, func(e *binEncoder) { encodeStageTransition(e, t) })
+	}
+}
+
+// decodeCurriculum reads a curriculum state written by encodeCurriculum.
+func decodeCurriculum(f binField) *CurriculumState {
+	c := &CurriculumState{}
+	f.message(func(f binField) {
+		switch f.tag {
+		case 1:
+			c.Stage = f.int()
+		case 2:
+			c.MeanFitness = f.floats()
+		case 3:
+			c.BestFitness = f.floats()
+		case 4:
+			c.Transitions = append(c.Transitions, decodeStageTransition(f))
+		}
+	})
+	return c
+}
+
+// encodeRatings writes competitive ratings. Tags: 1 System, 2 Initial, 3 EloK, 4 Beta, 5 Tau,
+// 6 DrawProbability, 7 Values (one entry per field: 1 genome key, 2 Rating). Rating tags: 1 Mu,
+// 2 Sigma, 3 Games.
+func encodeRatings(e *binEncoder, r *Ratings) {
+	rating := func(e *binEncoder, v Rating) {
+		e.putFloat(1, v.Mu)
+		e.putFloat(2, v.Sigma)
+		e.putInt(3, v.Games)
+	}
+	e.putString(1, r.System)
+	e.putMessage(2, func(e *binEncoder) { rating(e, r.Initial) })
+	e.putFloat(3, r.EloK)
+	e.putFloat(4, r.Beta)
+	e.putFloat(5, r.Tau)
+	e.putFloat(6, r.DrawProbability)
+	for _, key := range sortedKeys(r.Values) {
+		e.putMessage(7, func(e *binEncoder) {
+			e.putInt(1, key)
+			e.putMessage(2, func(e *binEncoder) { rating(e, r.Values[key]) })
+		})
+	}
+}
+
+// decodeRatings reads ratings written by encodeRatings.
+func decodeRatings(f binField) *Ratings {
+	rating := func(f binField) Rating {
+		var v Rating
+		f.message(func(f binField) {
+			switch f.tag {
+			case 1:
+				v.Mu = f.float()
+			case 2:
+				v.Sigma = f.float()
+			case 3:
+				v.Games = f.int()
+			}
+		})
+		return v
+	}
+	r := &Ratings{Values: make(map[int]Rating)}
+	f.message(func(f binField) {
+		switch f.tag {
+		case 1:
+			r.System = f.string()
+		case 2:
+			r.Initial = rating(f)
+		case 3:
+			r.EloK = f.float()
+		case 4:
+			r.Beta = f.float()
+		case 5:
+			r.Tau = f.float()
+		case 6:
+			r.DrawProbability = f.float()
+		case 7:
+			var key int
+			var v Rating
+			f.message(func(f binField) {
+				switch f.tag {
+				case 1:
+					key = f.int()
+				case 2:
+					v = rating(f)
+				}
+			})
+			r.Values[key] = v
+		}
+	})
+	return r
+}
+
+// encodeStatistics writes run statistics. Tags: 1 Generations (one per field), 2 Tables (one per
+// field: 1 Title, 2 Header cells, 3 Rows, each with 1 cells), 3 StageTransitions, 4 ConfigChanges,
+// 5 SpeciesReindexings (one per field: 1 Generation, 2 Keys, one entry per field: 1 old, 2 new).
+//
+// Generation tags: 1 Generation, 2 PopulationSize, 3 BestFitness, 4 MeanFitness, 5 StdevFitness,
+// 6 BestGenomeKey, 7 BestComplexity, 8 MeanComplexity, 9 SpeciesSizes (one entry per field:
+// 1 species key, 2 size), 10 Timings (1 Evaluation, 2 Speciation, 3 Reproduction, 4 Checkpoint,
+// 5 Total, in nanoseconds), 11 Sizes (1 Nodes, 2 EnabledConnections, 3 DisabledConnections, each
+// with 1 Min, 2 Mean, 3 Max; 4 Bytes).
+func encodeStatistics(e *binEncoder, s *Statistics) {
+	for _, gs := range s.Generations {
+		e.putMessage(1, func(e *binEncoder) {
+			e.putInt(1, gs.Generation)
+			e.putInt(2, gs.PopulationSize)
+			e.putFloat(3, gs.BestFitness)
+			e.putFloat(4, gs.MeanFitness)
+			e.putFloat(5, gs.StdevFitness)
+			e.putInt(6, gs.BestGenomeKey)
+			e.putInt(7, gs.BestComplexity)
+			e.putFloat(8, gs.MeanComplexity)
+			for _, key := range sortedKeys(gs.SpeciesSizes) {
+				e.putMessage(9, func(e *binEncoder) {
+					e.putInt(1, key)
+					e.putInt(2, gs.SpeciesSizes[key])
+				})
+			}
+			e.putMessage(10, func(e *binEncoder) {
+				t := gs.Timings
+				e.putInt(1, int(t.Evaluation))
+				e.putInt(2, int(t.Speciation))
+				e.putInt(3, int(t.Reproduction))
+				e.putInt(4, int(t.Checkpoint))
+				e.putInt(5, int(t.Total))
+			})
+			e.putMessage(11, func(e *binEncoder) {
+				for i, r := range []SizeRange{gs.Sizes.Nodes, gs.Sizes.EnabledConnections, gs.Sizes.DisabledConnections} {
+					e.putMessage(i+1, func(e *binEncoder) {
+						e.putInt(1, r.Min)
+						e.putFloat(2, r.Mean)
+						e.putInt(3, r.Max)
+					})
+				}
+				e.putInt(4, int(gs.Sizes.Bytes))
+			})
+		})
+	}
+	for _, t := range s.Tables {
+		e.putMessage(2, func(e *binEncoder) {
+			e.putString(1, t.Title)
+			e.putStrings(2, t.Header)
+			for _, row := range t.Rows {
+				e.putMessage(3, func(e *binEncoder) { e.putStrings(1, row) })
+			}
+		})
+	}
+	for _, t := range s.StageTransitions {
+		e.putMessage(3, func(e *binEncoder) { encodeStageTransition(e, t) })
+	}
+	for _, c := range s.ConfigChanges {
+		e.putMessage(4, func(e *binEncoder) { encodeConfigChange(e, c) })
+	}
+	for _, r := range s.SpeciesReindexings {
+		e.putMessage(5, func(e *binEncoder) {
+			e.putInt(1, r.Generation)
+			for _, key := range sortedKeys(r.Keys) {
+				e.putMessage(2, func(e *binEncoder) {
+					e.putInt(1, key)
+					e.putInt(2, r.Keys[key])
+				})
+			}
+		})
+	}
+}
+
+// decodeStatistics reads statistics written by encodeStatistics.
+func decodeStatistics(f binField) *Statistics {
+	s := NewStatistics()
+	f.message(func(f binField) {
+		switch f.tag {
+		case 1:
+			gs := GenerationStats{SpeciesSizes: make(map[int]int)}
+			f.message(func(f binField) {
+				switch f.tag {
+				case 1:
+					gs.Generation = f.int()
+				case 2:
+					gs.PopulationSize = f.int()
+				case 3:
+					gs.BestFitness = f.float()
+				case 4:
+					gs.MeanFitness = f.float()
+				case 5:
+					gs.StdevFitness = f.float()
+				case 6:
+					gs.BestGenomeKey = f.int()
+				case 7:
+					gs.BestComplexity = f.int()
+				case 8:
+					gs.MeanComplexity = f.float()
+				case 9:
+					var key, size int
+					f.message(func(f binField) {
+						switch f.tag {
+						case 1:
+							key = f.int()
+						case 2:
+							size = f.int()
+						}
+					})
+					gs.SpeciesSizes[key] = size
+				case 10:
+					f.message(func(f binField) {
+						switch f.tag {
+						case 1:
+							gs.Timings.Evaluation = time.Duration(f.int())
+						case 2:
+							gs.Timings.Speciation = time.Duration(f.int())
+						case 3:
+							gs.Timings.Reproduction = time.Duration(f.int())
+						case 4:
+							gs.Timings.Checkpoint = time.Duration(f.int())
+						case 5:
+							gs.Timings.Total = time.Duration(f.int())
+						}
+					})
+				case 11:
+					ranges := []*SizeRange{&gs.Sizes.Nodes, &gs.Sizes.EnabledConnections, &gs.Sizes.DisabledConnections}
+					f.message(func(f binField) {
+						switch f.tag {
+						case 1, 2, 3:
+							r := ranges[f.tag-1]
+							f.message(func(f binField) {
+								switch f.tag {
+								case 1:
+									r.Min = f.int()
+								case 2:
+									r.Mean = f.float()
+								case 3:
+									r.Max = f.int()
+								}
+							})
+						case 4:
+							gs.Sizes.Bytes = int64(f.int())
+						}
+					})
+				}
+			})
+			s.Generations = append(s.Generations, gs)
+		case 2:
+			var t ReportTable
+			f.message(func(f binField) {
+				switch f.tag {
+				case 1:
+					t.Title = f.string()
+				case 2:
+					t.Header = append(t.Header, f.string())
+				case 3:
+					var row []string
+					f.message(func(f binField) {
+						if f.tag == 1 {
+							row = append(row, f.string())
+						}
+					})
+					t.Rows = append(t.Rows, row)
+				}
+			})
+			s.Tables = append(s.Tables, t)
+		case 3:
+			s.StageTransitions = append(s.StageTransitions, decodeStageTransition(f))
+		case 4:
+			s.ConfigChanges = append(s.ConfigChanges, decodeConfigChange(f))
+		case 5:
+			r := SpeciesReindex{Keys: make(map[int]int)}
+			f.message(func(f binField) {
+				switch f.tag {
+				case 1:
+					r.Generation = f.int()
+				case 2:
+					var oldKey, newKey int
+					f.message(func(f binField) {
+						switch f.tag {
+						case 1:
+							oldKey = f.int()
+						case 2:
+							newKey = f.int()
+						}
+					})
+					r.Keys[oldKey] = newKey
+				}
+			})
+			s.SpeciesReindexings = append(s.SpeciesReindexings, r)
+		}
+	})
+	return s
+}
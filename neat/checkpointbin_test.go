@@ -0,0 +1,232 @@
+package neat
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenSaveData returns checkpoint data built by hand, without randomness, touching every part of
+// the binary format.
+func goldenSaveData(t *testing.T, config *Config) *PopulationSaveData {
+	t.Helper()
+	build := func(key int, b *GenomeBuilder) *Genome {
+		g, err := b.Key(key).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.Fitness = float64(key) / 4
+		return g
+	}
+	g1 := build(1, NewGenomeBuilder(&config.Genome).
+		Connect(-1, 0, 0.5).Connect(-2, 0, -1.25).Bias(0, 0.125))
+	g2 := build(2, NewGenomeBuilder(&config.Genome).
+		AddHidden(9).Connect(-1, 9, 2).Connect(9, 0, -0.75).Connect(-3, 0, 1).Disable(-3, 0).
+		Activation(9, "tanh").Aggregation(9, "max").Response(9, 0.5))
+	g2.Connections[ConnectionKey{-3, 0}].DisabledFor = 2
+	g2.Fitnesses = []float64{0.5, 1.5}
+	g2.Behavior = []float64{1, -1}
+	g3 := build(3, NewGenomeBuilder(&config.Genome).Connect(-8, 0, 3))
+	gone := build(0, NewGenomeBuilder(&config.Genome).Connect(-4, 0, -2))
+
+	sp := NewSpecies(1, 0)
+	sp.Representative = gone
+	sp.Members = map[int]*Genome{0: gone, 1: g1, 2: g2}
+	sp.Fitness = 0.375
+	sp.AdjustedFitness = 0.25
+	sp.FitnessHistory = []float64{0.125, 0.375}
+	sp.BestFitness = 0.375
+	sp.Champion = g2.Copy()
+	sp.ChampionGeneration = 1
+	sp2 := NewSpecies(2, 1)
+	sp2.Representative = g3
+	sp2.Members = map[int]*Genome{3: g3}
+	speciesSet := NewSpeciesSet(&config.SpeciesSet)
+	speciesSet.Species = map[int]*Species{1: sp, 2: sp2}
+	speciesSet.GenomeToSpecies = map[int]int{1: 1, 2: 1, 3: 2}
+	speciesSet.Indexer = 3
+
+	reproduction := NewReproduction(&config.Reproduction, nil)
+	reproduction.NextGenomeKey = 4
+	reproduction.Ancestors = map[int][]int{1: {}, 2: {0, 1}, 3: {0}}
+	reproduction.AncestorBorn = map[int]int{0: 0, 1: 0, 2: 1, 3: 1}
+	reproduction.Stagnation = nil
+	reproduction.Reporters = nil
+
+	change := ConfigChange{Generation: 1, Key: "weight_mutate_power", Old: 0.5, New: 0.25}
+	stats := NewStatistics()
+	stats.Generations = []GenerationStats{{
+		Generation:     1,
+		PopulationSize: 3,
+		BestFitness:    0.75,
+		MeanFitness:    0.5,
+		StdevFitness:   0.25,
+		BestGenomeKey:  3,
+		BestComplexity: 2,
+		MeanComplexity: 3.5,
+		SpeciesSizes:   map[int]int{1: 2, 2: 1},
+		Timings:        PhaseTimings{Evaluation: time.Millisecond, Speciation: 2 * time.Microsecond, Total: 2 * time.Millisecond},
+		Sizes: GenomeSizeStats{
+			Nodes:               SizeRange{Min: 1, Mean: 1.5, Max: 2},
+			EnabledConnections:  SizeRange{Min: 1, Mean: 1.75, Max: 2},
+			DisabledConnections: SizeRange{Max: 1, Mean: 0.25},
+			Bytes:               1024,
+		},
+	}}
+	stats.Tables = []ReportTable{{Title: "truth", Header: []string{"in", "out"}, Rows: [][]string{{"0", "1"}}}}
+	stats.ConfigChanges = []ConfigChange{change}
+
+	return &PopulationSaveData{
+		Population:    map[int]*Genome{1: g1, 2: g2, 3: g3},
+		SpeciesSet:    speciesSet,
+		Reproduction:  reproduction,
+		Generation:    2,
+		BestGenome:    g3.Copy(),
+		HallOfFame:    []*Genome{g3.Copy(), g2.Copy()},
+		ConfigChanges: []ConfigChange{change},
+		Statistics:    stats,
+		Version:       checkpointVersion,
+	}
+}
+
+// TestBinaryCheckpointGolden checks that the binary format encodes known data to the same bytes
+// as testdata/checkpoint.golden, so changes to the format are deliberate, and that decoding the
+// golden file and encoding it again loses nothing. Rewrite the file with go test -update.
+func TestBinaryCheckpointGolden(t *testing.T) {
+	golden := filepath.Join("testdata", "checkpoint.golden")
+	var buf bytes.Buffer
+	if err := writeBinaryCheckpoint(&buf, goldenSaveData(t, loadTestConfig(t))); err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("binary checkpoint encoding changed: got %d bytes, golden file has %d; run go test -update if the change is intended", buf.Len(), len(want))
+	}
+
+	data, err := readBinaryCheckpoint(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := writeBinaryCheckpoint(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Error("re-encoding the decoded golden checkpoint gave different bytes")
+	}
+	if sp := data.SpeciesSet.Species[1]; sp.Members[2] != data.Population[2] {
+		t.Error("species member 2 is not shared with the population after decoding")
+	} else if g := sp.Members[0]; g == nil || len(g.Connections) != 1 {
+		t.Errorf("species member 0, outside the population, decoded as %v", g)
+	}
+}
+
+// TestBinaryCheckpointRoundTrip saves an evolved population in the binary format, loads it and
+// checks the loaded population matches and saves to the same file again.
+func TestBinaryCheckpointRoundTrip(t *testing.T) {
+	config := loadTestConfig(t)
+	config.Neat.PopSize = 20
+	config.Neat.NoFitnessTermination = true
+	p, err := NewPopulation(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetLogger(NopLogger{})
+	p.Statistics = NewStatistics()
+	fitness := func(genomes map[int]*Genome) error {
+		for _, g := range genomes {
+			_, enabled := g.Size()
+			g.Fitness = float64(enabled)
+		}
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := p.RunGeneration(fitness); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.gz"), filepath.Join(dir, "second.gz")
+	opts := CheckpointOptions{Format: "binary"}
+	// Saving records its own duration in the last generation's statistics, after writing them.
+	stats := *p.Statistics
+	stats.Generations = append([]GenerationStats(nil), stats.Generations...)
+	if err := p.SaveCheckpoint(first, opts); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCheckpoint(first, "testdata/config.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded.SetLogger(NopLogger{})
+
+	if loaded.Generation != p.Generation {
+		t.Errorf("Generation = %d, want %d", loaded.Generation, p.Generation)
+	}
+	if loaded.Reproduction.NextGenomeKey != p.Reproduction.NextGenomeKey {
+		t.Errorf("NextGenomeKey = %d, want %d", loaded.Reproduction.NextGenomeKey, p.Reproduction.NextGenomeKey)
+	}
+	if !reflect.DeepEqual(loaded.Reproduction.Ancestors, p.Reproduction.Ancestors) {
+		t.Error("ancestors differ after loading")
+	}
+	if !reflect.DeepEqual(loaded.SpeciesSet.GenomeToSpecies, p.SpeciesSet.GenomeToSpecies) {
+		t.Error("species assignments differ after loading")
+	}
+	if !reflect.DeepEqual(*loaded.Statistics, stats) {
+		t.Error("statistics differ after loading")
+	}
+	if len(loaded.Population) != len(p.Population) {
+		t.Fatalf("loaded %d genomes, want %d", len(loaded.Population), len(p.Population))
+	}
+	for key, want := range p.Population {
+		got := loaded.Population[key]
+		if got == nil {
+			t.Errorf("genome %d missing after loading", key)
+			continue
+		}
+		if got.Fitness != want.Fitness || len(got.Nodes) != len(want.Nodes) || len(got.Connections) != len(want.Connections) {
+			t.Errorf("genome %d differs after loading", key)
+			continue
+		}
+		for k, ng := range want.Nodes {
+			if got.Nodes[k] == nil || *got.Nodes[k] != *ng {
+				t.Errorf("genome %d node %d = %+v, want %+v", key, k, got.Nodes[k], ng)
+			}
+		}
+		for k, cg := range want.Connections {
+			if got.Connections[k] == nil || *got.Connections[k] != *cg {
+				t.Errorf("genome %d connection %v = %+v, want %+v", key, k, got.Connections[k], cg)
+			}
+		}
+	}
+
+	if err := loaded.SaveCheckpoint(second, opts); err != nil {
+		t.Fatal(err)
+	}
+	a, err := os.ReadFile(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Error("saving the loaded population gave a different checkpoint")
+	}
+}
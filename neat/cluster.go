@@ -0,0 +1,152 @@
+package neat
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// GenomeCluster is a group of genetically similar genomes found by ClusterGenomes.
+type GenomeCluster struct {
+	Medoid       *Genome   // Member with the smallest total distance to the rest of the cluster
+	Members      []*Genome // All members (including the medoid), sorted by key
+	MeanDistance float64   // Mean distance from the members to the medoid
+}
+
+// ClusterGenomes partitions genomes into at most k clusters by genetic distance using k-medoids,
+// and returns the clusters sorted by size (largest first). Each medoid is an actual genome and can be
+// presented as the archetype of a distinct solution family.
+// The result is deterministic for a given set of genomes.
+func ClusterGenomes(genomes map[int]*Genome, k int) ([]GenomeCluster, error) {
+	if k <= 0 {
+		return nil, fmt.Errorf("cluster count must be positive, got %d", k)
+	}
+	if len(genomes) == 0 {
+		return []GenomeCluster{}, nil
+	}
+
+	// Sort by key so the result doesn't depend on map iteration order.
+	items := make([]*Genome, 0, len(genomes))
+	for _, g := range genomes {
+		items = append(items, g)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	n := len(items)
+	k = min(k, n)
+
+	// Pairwise distance matrix.
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d := items[i].Distance(items[j])
+			dist[i][j] = d
+			dist[j][i] = d
+		}
+	}
+
+	// Initialize: the most central genome first, then repeatedly the genome farthest from all chosen medoids.
+	medoids := []int{mostCentral(dist, allIndices(n))}
+	for len(medoids) < k {
+		farthest, farthestDist := -1, -1.0
+		for i := 0; i < n; i++ {
+			d := math.Inf(1)
+			for _, m := range medoids {
+				d = math.Min(d, dist[i][m])
+			}
+			if d > farthestDist {
+				farthest, farthestDist = i, d
+			}
+		}
+		if farthestDist <= 0 {
+			break // Remaining genomes are identical to existing medoids.
+		}
+		medoids = append(medoids, farthest)
+	}
+
+	// Alternate between assigning genomes to the nearest medoid and recentering each cluster.
+	var assignment []int
+	for iter := 0; iter < 100; iter++ {
+		assignment = assignToMedoids(dist, medoids)
+		changed := false
+		for c := range medoids {
+			members := []int{}
+			for i, a := range assignment {
+				if a == c {
+					members = append(members, i)
+				}
+			}
+			if len(members) == 0 {
+				continue // Medoid tied with another; it keeps its position.
+			}
+			if center := mostCentral(dist, members); center != medoids[c] {
+				medoids[c] = center
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	clusters := make([]GenomeCluster, len(medoids))
+	for c, m := range medoids {
+		clusters[c].Medoid = items[m]
+	}
+	for i, c := range assignment {
+		clusters[c].Members = append(clusters[c].Members, items[i])
+		clusters[c].MeanDistance += dist[i][medoids[c]]
+	}
+	nonEmpty := clusters[:0]
+	for _, cluster := range clusters {
+		if len(cluster.Members) > 0 {
+			cluster.MeanDistance /= float64(len(cluster.Members))
+			nonEmpty = append(nonEmpty, cluster)
+		}
+	}
+	clusters = nonEmpty
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].Members) > len(clusters[j].Members)
+	})
+	return clusters, nil
+}
+
+// assignToMedoids returns, for each item, the index of its nearest medoid.
+func assignToMedoids(dist [][]float64, medoids []int) []int {
+	assignment := make([]int, len(dist))
+	for i := range dist {
+		best := math.Inf(1)
+		for c, m := range medoids {
+			if dist[i][m] < best {
+				best = dist[i][m]
+				assignment[i] = c
+			}
+		}
+	}
+	return assignment
+}
+
+// mostCentral returns the member with the smallest total distance to the other members.
+func mostCentral(dist [][]float64, members []int) int {
+	best, bestSum := members[0], math.Inf(1)
+	for _, i := range members {
+		sum := 0.0
+		for _, j := range members {
+			sum += dist[i][j]
+		}
+		if sum < bestSum {
+			best, bestSum = i, sum
+		}
+	}
+	return best
+}
+
+func allIndices(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
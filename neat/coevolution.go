@@ -0,0 +1,184 @@
+package neat
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// MatchFunc plays a game between g and opponent and returns g's score. It is called concurrently,
+// so each call must build its own networks and game state.
+type MatchFunc func(g, opponent *Genome) (float64, error)
+
+// CoevolutionManager evaluates genomes competitively, for games where fitness is only defined
+// relative to other players. Each genome plays Opponents opponents sampled from the opponent
+// generation plus ArchiveOpponents sampled from an archive of earlier champions, and its fitness
+// is the aggregate of its match scores. The archive keeps strategies that beat earlier opponents
+// from being forgotten when the current opponents drift away from them.
+//
+// FitnessFunc plays genomes against their own generation. HostParasite evaluates two populations
+// against each other (hosts against parasites and vice versa, each side with its own archive);
+// run their generations alternately from one goroutine.
+type CoevolutionManager struct {
+	Match            MatchFunc
+	Opponents        int        // Opponents sampled from the opponent generation per genome (capped by its size)
+	ArchiveOpponents int        // Opponents sampled from the archive per genome (capped by its size)
+	ArchiveSize      int        // Champions kept per archive, oldest dropped first (0 = no archive)
+	Aggregation      string     // Name of the StatFunctions entry used to combine match scores (default "mean")
+	Workers          int        // Max matches played at once (0 = runtime.NumCPU())
+	Rand             *rand.Rand // Source for opponent sampling (nil = the math/rand top-level functions)
+	Matches          int        // Total number of matches played
+
+	archives map[*Population][]*Genome // Champions of each evaluated side (nil key = self-play), oldest first
+}
+
+// NewCoevolutionManager creates a manager sampling the given number of opponents per genome, with
+// an archive of 10 champions supplying 2 more opponents.
+func NewCoevolutionManager(match MatchFunc, opponents int) *CoevolutionManager {
+	return &CoevolutionManager{
+		Match:            match,
+		Opponents:        opponents,
+		ArchiveOpponents: 2,
+		ArchiveSize:      10,
+		Aggregation:      "mean",
+		Workers:          runtime.NumCPU(),
+	}
+}
+
+// Archive returns the champions archived for side (nil for self-play), oldest first.
+func (cm *CoevolutionManager) Archive(side *Population) []*Genome {
+	return append([]*Genome(nil), cm.archives[side]...)
+}
+
+// FitnessFunc returns a FitnessFunc that plays every genome against others of its generation
+// and against the archive of the generation's earlier champions.
+func (cm *CoevolutionManager) FitnessFunc() FitnessFunc {
+	return func(genomes map[int]*Genome) error {
+		return cm.evaluate(genomes, genomes, nil, nil)
+	}
+}
+
+// HostParasite returns the fitness functions of two competing populations: hosts play the
+// current parasites and the archived parasite champions, and parasites play the hosts likewise.
+// Match is always called with the genome being evaluated first.
+func (cm *CoevolutionManager) HostParasite(hosts, parasites *Population) (hostFitness, parasiteFitness FitnessFunc) {
+	hostFitness = func(genomes map[int]*Genome) error {
+		return cm.evaluate(genomes, parasites.Population, hosts, parasites)
+	}
+	parasiteFitness = func(genomes map[int]*Genome) error {
+		return cm.evaluate(genomes, hosts.Population, parasites, hosts)
+	}
+	return hostFitness, parasiteFitness
+}
+
+// evaluate sets the fitness of genomes from matches against opponents sampled from pool and from
+// the archive of opponentSide, then archives the champion of genomes under side.
+func (cm *CoevolutionManager) evaluate(genomes, pool map[int]*Genome, side, opponentSide *Population) error {
+	if cm.Match == nil {
+		return fmt.Errorf("coevolution requires a match function")
+	}
+	aggregation := cm.Aggregation
+	if aggregation == "" {
+		aggregation = "mean"
+	}
+	aggregate, ok := StatFunctions[aggregation]
+	if !ok {
+		return fmt.Errorf("unknown match aggregation function: %s", aggregation)
+	}
+	keys := sortedGenomeKeys(genomes)
+	poolKeys := sortedGenomeKeys(pool)
+	archive := cm.archives[opponentSide]
+
+	// Sample every genome's opponents up front, so the draws don't depend on match scheduling.
+	opponents := make([][]*Genome, len(keys))
+	for i, k := range keys {
+		candidates := make([]*Genome, 0, len(poolKeys))
+		for _, pk := range poolKeys {
+			if pool[pk] != genomes[k] {
+				candidates = append(candidates, pool[pk])
+			}
+		}
+		opponents[i] = append(cm.sample(candidates, cm.Opponents), cm.sample(archive, cm.ArchiveOpponents)...)
+		if len(opponents[i]) == 0 {
+			return fmt.Errorf("no opponents available for genome %d", k)
+		}
+	}
+
+	workers := cm.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	scores := make([][]float64, len(keys))
+	errs := make([][]error, len(keys))
+	semaphore := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		scores[i] = make([]float64, len(opponents[i]))
+		errs[i] = make([]error, len(opponents[i]))
+		for j, opponent := range opponents[i] {
+			semaphore <- struct{}{}
+			wg.Add(1)
+			go func(i, j int, g, opponent *Genome) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+				scores[i][j], errs[i][j] = cm.Match(g, opponent)
+			}(i, j, genomes[k], opponent)
+			cm.Matches++
+		}
+	}
+	wg.Wait()
+
+	var champion *Genome
+	for i, k := range keys {
+		for j, err := range errs[i] {
+			if err != nil {
+				return fmt.Errorf("match of genome %d against genome %d failed: %w", k, opponents[i][j].Key, err)
+			}
+		}
+		g := genomes[k]
+		g.Fitness = aggregate(scores[i])
+		if champion == nil || g.Fitness > champion.Fitness {
+			champion = g
+		}
+	}
+	if cm.ArchiveSize > 0 && champion != nil {
+		if cm.archives == nil {
+			cm.archives = make(map[*Population][]*Genome)
+		}
+		archived := append(cm.archives[side], champion.Copy())
+		if len(archived) > cm.ArchiveSize {
+			archived = archived[len(archived)-cm.ArchiveSize:]
+		}
+		cm.archives[side] = archived
+	}
+	return nil
+}
+
+// sample returns up to n distinct genomes drawn at random from candidates.
+func (cm *CoevolutionManager) sample(candidates []*Genome, n int) []*Genome {
+	n = min(n, len(candidates))
+	if n <= 0 {
+		return nil
+	}
+	perm := rand.Perm
+	if cm.Rand != nil {
+		perm = cm.Rand.Perm
+	}
+	picked := make([]*Genome, n)
+	for i, idx := range perm(len(candidates))[:n] {
+		picked[i] = candidates[idx]
+	}
+	return picked
+}
+
+// sortedGenomeKeys returns the keys of genomes in increasing order.
+func sortedGenomeKeys(genomes map[int]*Genome) []int {
+	keys := make([]int, 0, len(genomes))
+	for k := range genomes {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,113 @@
+package neat
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+)
+
+// CompactOptions controls what CompactCheckpoint keeps.
+type CompactOptions struct {
+	KeepAncestors  bool // Keep Reproduction.Ancestors (lineage of the current population)
+	HistoryLength  int  // Fitness history entries kept per species (0 = only the most recent)
+	KeepMembers    bool // Keep species member lists (rebuilt by the next speciation anyway)
+	KeepHallOfFame bool // Keep the hall of fame used for reseeding after extinction
+}
+
+// CompactionResult reports the file sizes before and after compaction.
+type CompactionResult struct {
+	BytesBefore int64
+	BytesAfter  int64
+}
+
+// CompactCheckpoint rewrites the checkpoint at inPath to outPath (which may equal inPath) keeping
+// only the state needed to resume: the current population, species representatives and stagnation
+// data, the best genome and the key counters. Ancestry, species member lists and old fitness
+// history are dropped unless opts asks to keep them. Each species' BestFitness is preserved, so
+// stagnation detection continues unchanged; only a species' recent fitness history is shortened.
+func CompactCheckpoint(inPath, outPath string, opts CompactOptions) (CompactionResult, error) {
+	result := CompactionResult{}
+	info, err := os.Stat(inPath)
+	if err != nil {
+		return result, fmt.Errorf("failed to read checkpoint file '%s': %w", inPath, err)
+	}
+	result.BytesBefore = info.Size()
+
+	saveData, format, err := readCheckpointData(inPath)
+	if err != nil {
+		return result, err
+	}
+
+	if !opts.KeepAncestors && saveData.Reproduction != nil {
+		saveData.Reproduction.Ancestors = nil
+		saveData.Reproduction.AncestorBorn = nil
+	}
+	if !opts.KeepHallOfFame {
+		saveData.HallOfFame = nil
+	}
+	if saveData.SpeciesSet != nil {
+		keep := max(opts.HistoryLength, 1)
+		for _, sp := range saveData.SpeciesSet.Species {
+			if sp.BestFitness == 0 && len(sp.FitnessHistory) > 0 {
+				sp.BestFitness = MaxFloat(sp.FitnessHistory)
+			}
+			if len(sp.FitnessHistory) > keep {
+				sp.FitnessHistory = append([]float64(nil), sp.FitnessHistory[len(sp.FitnessHistory)-keep:]...)
+			}
+			if !opts.KeepMembers {
+				sp.Members = nil
+			}
+		}
+	}
+
+	encode, err := checkpointEncoder(saveData, format)
+	if err != nil {
+		return result, err
+	}
+	if err := writeCheckpointFile(outPath, encode, false); err != nil {
+		return result, err
+	}
+	info, err = os.Stat(outPath)
+	if err != nil {
+		return result, fmt.Errorf("failed to read compacted checkpoint '%s': %w", outPath, err)
+	}
+	result.BytesAfter = info.Size()
+	return result, nil
+}
+
+// readCheckpointData decodes the raw save data of a checkpoint without rebuilding a Population,
+// and reports its format ("gob" or "binary").
+func readCheckpointData(path string) (*PopulationSaveData, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open checkpoint file '%s': %w", path, err)
+	}
+	defer file.Close()
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create gzip reader for checkpoint: %w", err)
+	}
+	defer gzReader.Close()
+
+	reader := bufio.NewReader(gzReader)
+	if magic, _ := reader.Peek(len(binaryCheckpointMagic)); string(magic) == binaryCheckpointMagic {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read checkpoint file '%s': %w", path, err)
+		}
+		saveData, err := readBinaryCheckpoint(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to decode population data from checkpoint: %w", err)
+		}
+		return saveData, "binary", nil
+	}
+
+	saveData := &PopulationSaveData{}
+	if err := gob.NewDecoder(reader).Decode(saveData); err != nil {
+		return nil, "", fmt.Errorf("failed to decode population data from checkpoint: %w", err)
+	}
+	return saveData, "gob", nil
+}
@@ -0,0 +1,83 @@
+package neat
+
+import "sort"
+
+// CompactGenome is a slice-backed representation of a genome: its genes are stored by value in
+// two contiguous slices sorted by key instead of one heap object per gene behind a map. It takes
+// a fraction of the memory and almost no GC work, which makes it the better form for genomes
+// that are kept but rarely changed, such as archives and long hall-of-fame lists; StoreReporter
+// records genomes in this form. It is a storage form only: evolution always works on Genome, so
+// Expand it into a Genome to mutate, cross over or build a network from it.
+type CompactGenome struct {
+	Key         int
+	Nodes       []NodeGene       // Sorted by key
+	Connections []ConnectionGene // Sorted by (in, out) node
+	Fitness     float64
+	Fitnesses   []float64
+	Behavior    []float64
+	Extension   GenomeExtension // Copy of Genome.Extension, if any
+}
+
+// Compact returns the slice-backed form of g. The result shares nothing with g.
+func (g *Genome) Compact() *CompactGenome {
+	c := &CompactGenome{
+		Key:         g.Key,
+		Nodes:       make([]NodeGene, 0, len(g.Nodes)),
+		Connections: make([]ConnectionGene, 0, len(g.Connections)),
+		Fitness:     g.Fitness,
+		Fitnesses:   append([]float64(nil), g.Fitnesses...),
+		Behavior:    append([]float64(nil), g.Behavior...),
+	}
+	if g.Extension != nil {
+		c.Extension = g.Extension.Copy()
+	}
+	for _, key := range sortedNodeKeys(g) {
+		c.Nodes = append(c.Nodes, *g.Nodes[key])
+	}
+	for _, key := range sortedConnectionKeys(g) {
+		c.Connections = append(c.Connections, *g.Connections[key])
+	}
+	return c
+}
+
+// Expand rebuilds a Genome from its compact form, linked to config. All genes of the result are
+// allocated as two blocks rather than one object per gene; the result shares nothing with c.
+func (c *CompactGenome) Expand(config *GenomeConfig) *Genome {
+	g := NewGenome(c.Key, config)
+	g.Fitness = c.Fitness
+	g.Fitnesses = append([]float64(nil), c.Fitnesses...)
+	g.Behavior = append([]float64(nil), c.Behavior...)
+	if c.Extension != nil {
+		g.Extension = c.Extension.Copy()
+	}
+	nodes := append([]NodeGene(nil), c.Nodes...)
+	for i := range nodes {
+		g.Nodes[nodes[i].Key] = &nodes[i]
+	}
+	conns := append([]ConnectionGene(nil), c.Connections...)
+	for i := range conns {
+		g.Connections[conns[i].Key] = &conns[i]
+	}
+	return g
+}
+
+// Node returns the node gene with the given key, or nil.
+func (c *CompactGenome) Node(key int) *NodeGene {
+	i := sort.Search(len(c.Nodes), func(i int) bool { return c.Nodes[i].Key >= key })
+	if i < len(c.Nodes) && c.Nodes[i].Key == key {
+		return &c.Nodes[i]
+	}
+	return nil
+}
+
+// Connection returns the connection gene with the given key, or nil.
+func (c *CompactGenome) Connection(key ConnectionKey) *ConnectionGene {
+	i := sort.Search(len(c.Connections), func(i int) bool {
+		k := c.Connections[i].Key
+		return k.InNodeID > key.InNodeID || (k.InNodeID == key.InNodeID && k.OutNodeID >= key.OutNodeID)
+	})
+	if i < len(c.Connections) && c.Connections[i].Key == key {
+		return &c.Connections[i]
+	}
+	return nil
+}
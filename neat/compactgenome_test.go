@@ -0,0 +1,53 @@
+package neat
+
+import "testing"
+
+func TestCompactGenomeRoundTrip(t *testing.T) {
+	config := loadTestConfig(t)
+	g := grownGenome(t, config, 7, 40)
+	g.Fitness = 1.5
+	g.Fitnesses = []float64{1.5, -2}
+	g.Behavior = []float64{0.25}
+	c := g.Compact()
+	for i := 1; i < len(c.Connections); i++ {
+		if !connectionKeyLess(c.Connections[i-1].Key, c.Connections[i].Key) {
+			t.Fatalf("compact connections not sorted at %d: %v, %v", i, c.Connections[i-1].Key, c.Connections[i].Key)
+		}
+	}
+
+	e := c.Expand(&config.Genome)
+	if e.Key != g.Key || e.Fitness != g.Fitness || len(e.Nodes) != len(g.Nodes) || len(e.Connections) != len(g.Connections) {
+		t.Fatalf("expanded genome %d (fitness %v, %d nodes, %d connections), want %d (%v, %d, %d)",
+			e.Key, e.Fitness, len(e.Nodes), len(e.Connections), g.Key, g.Fitness, len(g.Nodes), len(g.Connections))
+	}
+	for key, ng := range g.Nodes {
+		if got := c.Node(key); got == nil || *got != *ng {
+			t.Errorf("compact node %d = %+v, want %+v", key, got, ng)
+		}
+		if got := e.Nodes[key]; got == nil || *got != *ng {
+			t.Errorf("expanded node %d = %+v, want %+v", key, got, ng)
+		}
+	}
+	for key, cg := range g.Connections {
+		if got := c.Connection(key); got == nil || *got != *cg {
+			t.Errorf("compact connection %v = %+v, want %+v", key, got, cg)
+		}
+		if got := e.Connections[key]; got == nil || *got != *cg {
+			t.Errorf("expanded connection %v = %+v, want %+v", key, got, cg)
+		}
+	}
+
+	// The forms share nothing.
+	for _, cg := range e.Connections {
+		cg.Weight += 1
+	}
+	e.Fitnesses[0] = 0
+	for key, cg := range g.Connections {
+		if c.Connection(key).Weight != cg.Weight {
+			t.Fatalf("changing the expanded genome changed compact connection %v", key)
+		}
+	}
+	if c.Fitnesses[0] != 1.5 {
+		t.Error("changing the expanded genome's fitnesses changed the compact genome")
+	}
+}
@@ -0,0 +1,43 @@
+package neat
+
+// Size returns the number of nodes and enabled connections in the genome,
+// matching neat-python's genome.size().
+func (g *Genome) Size() (int, int) {
+	numEnabled := 0
+	for _, cg := range g.Connections {
+		if cg.Enabled {
+			numEnabled++
+		}
+	}
+	return len(g.Nodes), numEnabled
+}
+
+// Complexity returns the genome size as a single number (nodes + enabled connections).
+func (g *Genome) Complexity() int {
+	nodes, conns := g.Size()
+	return nodes + conns
+}
+
+// applyComplexityPressure penalizes freshly evaluated genomes for their size.
+// With complexity_penalty > 0, Fitness is reduced by penalty * complexity.
+// With complexity_objective enabled, the negated complexity is appended to Fitnesses
+// (or to the scalar fitness if Fitnesses is empty) so NSGA-II can trade it off against performance.
+// With input_penalty > 0, Fitness is also reduced by penalty * the number of connected inputs.
+func applyComplexityPressure(config *NeatConfig, genomes map[int]*Genome) {
+	if config.ComplexityPenalty == 0 && !config.ComplexityObjective && config.InputPenalty == 0 {
+		return
+	}
+	for _, g := range genomes {
+		if config.InputPenalty != 0 {
+			g.Fitness -= config.InputPenalty * float64(len(g.ConnectedInputs()))
+		}
+		complexity := float64(g.Complexity())
+		if config.ComplexityObjective {
+			if len(g.Fitnesses) == 0 {
+				g.Fitnesses = []float64{g.Fitness}
+			}
+			g.Fitnesses = append(g.Fitnesses, -complexity)
+		}
+		g.Fitness -= config.ComplexityPenalty * complexity
+	}
+}
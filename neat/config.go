@@ -3,6 +3,7 @@ package neat
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"gopkg.in/ini.v1"
 )
@@ -14,6 +15,8 @@ type Config struct {
 	Reproduction ReproductionConfig
 	SpeciesSet   SpeciesSetConfig
 	Stagnation   StagnationConfig
+	Experimental ExperimentalConfig
+	Schedules    []Schedule // Parameter schedules from the [Schedules] section, applied at the start of each generation
 }
 
 // NeatConfig holds parameters specific to the NEAT algorithm itself.
@@ -23,6 +26,21 @@ type NeatConfig struct {
 	FitnessThreshold     float64 `ini:"fitness_threshold"`
 	ResetOnExtinction    bool    `ini:"reset_on_extinction"`
 	NoFitnessTermination bool    `ini:"no_fitness_termination"`
+	EvaluationBudget     int     `ini:"evaluation_budget"`    // Max genomes evaluated per generation, shared round-robin across species (0 = evaluate all)
+	ComplexityPenalty    float64 `ini:"complexity_penalty"`   // Fitness penalty per node and enabled connection (0 = disabled)
+	ComplexityObjective  bool    `ini:"complexity_objective"` // Append negated complexity to Fitnesses as an extra NSGA-II objective
+	InputPenalty         float64 `ini:"input_penalty"`        // Fitness penalty per input connected to an output, rewarding input parsimony (0 = disabled)
+	Seed                 int64   `ini:"seed"`                 // Master seed for per-species random streams (0 = unseeded)
+	KeyOffset            int     `ini:"key_offset"`           // Added to every genome and species key, e.g. run_index * 1000000000, so runs and islands never collide
+
+	HallOfFameSize           int     `ini:"hall_of_fame_size"`          // Number of all-time fittest genomes kept in Population.HallOfFame (0 = disabled)
+	ExtinctionReseedFraction float64 `ini:"extinction_reseed_fraction"` // Fraction of a reset population bred from the hall of fame and best genome (0 = reset entirely from scratch)
+	EvaluationCacheSize      int     `ini:"evaluation_cache_size"`      // Number of genome evaluations cached to skip re-evaluating identical genomes (0 = disabled)
+	CheckpointBackup         bool    `ini:"checkpoint_backup"`          // Keep the checkpoint replaced by SaveCheckpoint as <path>.bak
+	CheckpointFormat         string  `ini:"checkpoint_format"`          // Encoding written by SaveCheckpoint: "gob" (default) or "binary"
+	PhenotypeCache           bool    `ini:"phenotype_cache"`            // Create Population.PhenotypeCache so unchanged genomes reuse their network
+	GenePooling              bool    `ini:"gene_pooling"`               // Recycle the genes of discarded generations; copy any genome kept beyond its generation
+	ProfileLabels            bool    `ini:"profile_labels"`             // Label RunGeneration's phases for pprof (see Population.RunGeneration)
 }
 
 // GenomeConfig holds parameters specific to the structure and mutation of genomes.
@@ -31,7 +49,11 @@ type GenomeConfig struct {
 	NumInputs                        int     `ini:"num_inputs"`
 	NumOutputs                       int     `ini:"num_outputs"`
 	NumHidden                        int     `ini:"num_hidden"`
-	FeedForward                      bool    `ini:"feed_forward"` // If true, recurrent connections are disallowed
+	BiasInput                        bool    `ini:"bias_input"`                  // Model bias as an extra always-on input (key -(num_inputs+1)) instead of per-node bias attributes
+	FeedForward                      bool    `ini:"feed_forward"`                // If true, recurrent connections are disallowed
+	AllowSelfConnections             bool    `ini:"allow_self_connections"`      // Without feed_forward, whether add-connection may connect a node to itself (default true)
+	AllowRecurrentConnections        bool    `ini:"allow_recurrent_connections"` // Without feed_forward, whether add-connection may close cycles other than self-loops (default true)
+	RecurrentConnProb                float64 `ini:"recurrent_conn_prob"`         // Without feed_forward, chance an added connection is recurrent (negative = no bias, the default)
 	CompatibilityDisjointCoefficient float64 `ini:"compatibility_disjoint_coefficient"`
 	CompatibilityWeightCoefficient   float64 `ini:"compatibility_weight_coefficient"`
 	ConnAddProb                      float64 `ini:"conn_add_prob"`
@@ -39,7 +61,7 @@ type GenomeConfig struct {
 	NodeAddProb                      float64 `ini:"node_add_prob"`
 	NodeDeleteProb                   float64 `ini:"node_delete_prob"`
 	SingleStructuralMutation         bool    `ini:"single_structural_mutation"` // Python default: false
-	StructuralMutationSurer          string  `ini:"structural_mutation_surer"`  // Python default: 'default'
+	StructuralMutationSurer          string  `ini:"structural_mutation_surer"`  // Python default: 'default' (= single_structural_mutation)
 	InitialConnection                string  `ini:"initial_connection"`         // Python default: 'unconnected'
 
 	// --- Node Gene parameters ---
@@ -65,6 +87,18 @@ type GenomeConfig struct {
 	ActivationOptions    []string `ini:"activation_options" delim:" "` // Space-separated list
 	ActivationMutateRate float64  `ini:"activation_mutate_rate"`
 
+	EvolveActivationParam      bool    `ini:"evolve_activation_param"`    // Adds an evolvable per-node activation parameter scaling the function's shape (e.g. sigmoid steepness, ELU alpha)
+	ActivationParamInitMean    float64 `ini:"activation_param_init_mean"` // Default: 1.0 (the function's standard shape)
+	ActivationParamInitStdev   float64 `ini:"activation_param_init_stdev"`
+	ActivationParamInitType    string  `ini:"activation_param_init_type"` // Default: 'gaussian'
+	ActivationParamReplaceRate float64 `ini:"activation_param_replace_rate"`
+	ActivationParamMutateRate  float64 `ini:"activation_param_mutate_rate"`
+	ActivationParamMutatePower float64 `ini:"activation_param_mutate_power"`
+	ActivationParamMaxValue    float64 `ini:"activation_param_max_value"` // Default: 5.0
+	ActivationParamMinValue    float64 `ini:"activation_param_min_value"` // Default: 0.1
+
+	OutputActivation string `ini:"output_activation"` // If set (e.g. 'identity'), output nodes use this activation with a fixed response of 1.0
+
 	AggregationDefault    string   `ini:"aggregation_default"`           // Default: 'random'
 	AggregationOptions    []string `ini:"aggregation_options" delim:" "` // Space-separated list
 	AggregationMutateRate float64  `ini:"aggregation_mutate_rate"`
@@ -79,27 +113,83 @@ type GenomeConfig struct {
 	WeightMaxValue    float64 `ini:"weight_max_value"`
 	WeightMinValue    float64 `ini:"weight_min_value"`
 
+	WeightAgnostic bool `ini:"weight_agnostic"` // Tie all weights to one shared value chosen at evaluation (see SweepSharedWeights); weights are not evolved
+
+	WeightDecayType      string  `ini:"weight_decay_type"`      // "none" (default), "multiplicative" (w *= 1-rate) or "l1" (shrink |w| by rate)
+	WeightDecayRate      float64 `ini:"weight_decay_rate"`      // Decay strength applied once per generation to every genome, elites included
+	WeightPruneThreshold float64 `ini:"weight_prune_threshold"` // Connections with |w| below this are removed after decay (0 = no pruning)
+
+	ConnectionExpression  bool    `ini:"connection_expression"` // Enables the evolvable per-connection expression probability
+	ExpressionMode        string  `ini:"expression_mode"`       // "sample" (drop unexpressed connections per network build) or "expectation" (scale weights)
+	ExpressionInitMean    float64 `ini:"expression_init_mean"`  // Default: 1.0
+	ExpressionInitStdev   float64 `ini:"expression_init_stdev"`
+	ExpressionInitType    string  `ini:"expression_init_type"` // Default: 'gaussian'
+	ExpressionReplaceRate float64 `ini:"expression_replace_rate"`
+	ExpressionMutateRate  float64 `ini:"expression_mutate_rate"`
+	ExpressionMutatePower float64 `ini:"expression_mutate_power"`
+	ExpressionMaxValue    float64 `ini:"expression_max_value"` // Default: 1.0
+	ExpressionMinValue    float64 `ini:"expression_min_value"` // Default: 0.0
+
 	EnabledDefault        string  `ini:"enabled_default"` // Default: 'True'
 	EnabledMutateRate     float64 `ini:"enabled_mutate_rate"`
 	EnabledRateToTrueAdd  float64 `ini:"enabled_rate_to_true_add"`  // Python default: 0.0
 	EnabledRateToFalseAdd float64 `ini:"enabled_rate_to_false_add"` // Python default: 0.0
+	AllowCyclicEnable     bool    `ini:"allow_cyclic_enable"`       // Allow re-enabling connections that close a cycle (default: true unless feed_forward)
+	CrossoverEnabledMode  string  `ini:"crossover_enabled_mode"`    // How crossover sets Enabled on matching genes: "neat-python" (either parent at random, default) or "paper"
+	CrossoverDisableProb  float64 `ini:"crossover_disable_prob"`    // With "paper" mode, chance a gene disabled in either parent stays disabled (default 0.75)
+
+	// Connections disabled for this many consecutive generations are removed (0 = kept forever),
+	// each generation with probability disabled_purge_rate (default 1.0). Elites age too.
+	DisabledPurgeGenerations int     `ini:"disabled_purge_generations"`
+	DisabledPurgeRate        float64 `ini:"disabled_purge_rate"`
 
 	// --- Calculated/Derived ---
-	InputKeys    []int // Derived
-	OutputKeys   []int // Derived
-	NodeKeyIndex int   // Derived, used for assigning new node keys
+	InputKeys  []int // Derived
+	OutputKeys []int // Derived
+
+	innovations *InnovationTracker // Assigns new node keys; see Innovations
+	logger      Logger             // Destination for diagnostic output (nil = DefaultLogger), set by Population.SetLogger
+	factory     GenomeFactory      // Builds and varies genomes (nil = DefaultGenomeFactory); see SetGenomeFactory
+	seed        int64              // Master seed of the run (NeatConfig.Seed), for Genome.SampleRand
 }
 
 // ReproductionConfig holds parameters related to reproduction.
 type ReproductionConfig struct {
 	Elitism           int     `ini:"elitism"`            // Python default: 0
 	SurvivalThreshold float64 `ini:"survival_threshold"` // Python default: 0.2
+	MinSurvivors      int     `ini:"min_survivors"`      // Lower bound on the parent pool of a species (default 2)
+	MaxSurvivors      int     `ini:"max_survivors"`      // Upper bound on the parent pool of a species (0 = unbounded)
 	MinSpeciesSize    int     `ini:"min_species_size"`   // Python default: 1
+	Strategy          string  `ini:"strategy"`           // "default" (scalar fitness) or "nsga2" (multi-objective via Genome.Fitnesses; see NSGA2Reproduction)
+
+	FitnessScaling       string  `ini:"fitness_scaling"`       // Transform applied to member fitness before spawn allocation: "none" (default), "rank", "sigma" or "boltzmann"
+	BoltzmannTemperature float64 `ini:"boltzmann_temperature"` // Temperature of Boltzmann scaling (default 1.0); lower values sharpen selection
+	SpawnFitnessFunc     string  `ini:"spawn_fitness_func"`    // Statistic of member fitness used for spawn allocation: "mean", "max", "min" or "median" (empty = species_fitness_func)
+
+	NoAncestry          bool `ini:"no_ancestry"`          // Disable lineage tracking in Reproduction.Ancestors entirely
+	AncestryGenerations int  `ini:"ancestry_generations"` // Generations of lineage kept in Reproduction.Ancestors (0 or 1 = parents of the current population only)
 }
 
 // SpeciesSetConfig holds parameters related to speciation.
 type SpeciesSetConfig struct {
 	CompatibilityThreshold float64 `ini:"compatibility_threshold"`
+	IncrementalSpeciation  bool    `ini:"incremental_speciation"` // Genomes that survived unchanged (elites) keep their species; only offspring are assigned by distance
+	// RepresentativeSelection chooses each species' representative for the next speciation among
+	// its members: "closest" to the previous representative (default), the fittest ("champion"),
+	// a "random" member, or the "medoid" (least total distance to the other members).
+	RepresentativeSelection string `ini:"representative_selection"`
+	ReindexInterval         int    `ini:"species_reindex_interval"` // Renumber the species from 1 every this many generations (0 = never: keys grow and are never reused)
+	// SignatureSize enables approximate speciation for large populations: genomes get MinHash
+	// signatures of this many entries (e.g. 32) from which the disjoint part of their distance is
+	// estimated, and exact distances are only computed where that estimate, less SignatureMargin
+	// (default 0.5), doesn't already rule the species out. 0 computes every distance.
+	SignatureSize   int     `ini:"speciation_signature_size"`
+	SignatureMargin float64 `ini:"speciation_signature_margin"`
+	// SpeciationMethod selects the Speciator: "threshold" (default), "cluster" into
+	// SpeciationClusters species by k-medoids, or "behavior" by Genome.Behavior (see
+	// ThresholdSpeciator, ClusterSpeciator and BehaviorSpeciator).
+	SpeciationMethod   string `ini:"speciation_method"`
+	SpeciationClusters int    `ini:"speciation_clusters"`
 }
 
 // StagnationConfig holds parameters related to species stagnation.
@@ -107,6 +197,14 @@ type StagnationConfig struct {
 	SpeciesFitnessFunc string `ini:"species_fitness_func"` // Python default: 'mean'
 	MaxStagnation      int    `ini:"max_stagnation"`       // Python default: 15
 	SpeciesElitism     int    `ini:"species_elitism"`      // Python default: 0
+	Policy             string `ini:"stagnation_policy"`    // "rank-elitism" (default), "absolute", "none", "max-age" or "improvement-rate"
+
+	MaxSpeciesAge      int     `ini:"max_species_age"`      // Age in generations at which species stagnate with stagnation_policy "max-age"
+	MinImprovementRate float64 `ini:"min_improvement_rate"` // Fitness gain per generation below which species stagnate with "improvement-rate"
+	ImprovementWindow  int     `ini:"improvement_window"`   // Generations the improvement rate is measured over (0 = max_stagnation)
+
+	FitnessHistoryLength      int  `ini:"fitness_history_length"`       // Max entries kept in Species.FitnessHistory (0 = max_stagnation, -1 = unbounded)
+	ResetHistoryOnImprovement bool `ini:"reset_history_on_improvement"` // Restart the history window when a species improves
 }
 
 // LoadConfig loads configuration parameters from an INI file.
@@ -118,7 +216,11 @@ func LoadConfig(filePath string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config file '%s': %w", filePath, err)
 	}
+	return configFromIni(cfg)
+}
 
+// configFromIni reads, completes with defaults and validates the config in a loaded INI file.
+func configFromIni(cfg *ini.File) (*Config, error) {
 	config := &Config{}
 
 	// Map sections to structs
@@ -139,6 +241,13 @@ func LoadConfig(filePath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to map [DefaultStagnation] section: %w", err)
 	}
 
+	if err := loadExperimentalConfig(cfg.Section("Experimental"), &config.Experimental); err != nil {
+		return nil, fmt.Errorf("failed to read [Experimental] section: %w", err)
+	}
+	if err := loadScheduleConfig(cfg.Section("Schedules"), config); err != nil {
+		return nil, fmt.Errorf("failed to read [Schedules] section: %w", err)
+	}
+
 	// --- Manually reload potentially problematic bool/float values ---
 	// This is a workaround in case MapTo has issues with comments or specific formats
 	neatSection := cfg.Section("NEAT")
@@ -154,28 +263,140 @@ func LoadConfig(filePath string) (*Config, error) {
 	if err == nil {
 		config.Neat.ResetOnExtinction, _ = ffKey.Bool()
 	}
+	ffKey, err = neatSection.GetKey("complexity_objective")
+	if err == nil {
+		config.Neat.ComplexityObjective, _ = ffKey.Bool()
+	}
+	ffKey, err = neatSection.GetKey("checkpoint_backup")
+	if err == nil {
+		config.Neat.CheckpointBackup, _ = ffKey.Bool()
+	}
+	ffKey, err = neatSection.GetKey("phenotype_cache")
+	if err == nil {
+		config.Neat.PhenotypeCache, _ = ffKey.Bool()
+	}
+	ffKey, err = neatSection.GetKey("gene_pooling")
+	if err == nil {
+		config.Neat.GenePooling, _ = ffKey.Bool()
+	}
+	ffKey, err = neatSection.GetKey("profile_labels")
+	if err == nil {
+		config.Neat.ProfileLabels, _ = ffKey.Bool()
+	}
+
+	ffKey, err = cfg.Section("DefaultReproduction").GetKey("no_ancestry")
+	if err == nil {
+		config.Reproduction.NoAncestry, _ = ffKey.Bool()
+	}
+
+	ffKey, err = cfg.Section("DefaultSpeciesSet").GetKey("incremental_speciation")
+	if err == nil {
+		config.SpeciesSet.IncrementalSpeciation, _ = ffKey.Bool()
+	}
+
+	stagnationSection := cfg.Section("DefaultStagnation")
+	ffKey, err = stagnationSection.GetKey("reset_history_on_improvement")
+	if err == nil {
+		config.Stagnation.ResetHistoryOnImprovement, _ = ffKey.Bool()
+	}
 
 	genomeSection := cfg.Section("DefaultGenome")
 	ffKey, err = genomeSection.GetKey("feed_forward")
 	if err == nil {
 		config.Genome.FeedForward, _ = ffKey.Bool()
 	}
+	// Recurrent genomes may add self-loops and cycles unless disabled individually.
+	config.Genome.AllowSelfConnections = true
+	ffKey, err = genomeSection.GetKey("allow_self_connections")
+	if err == nil {
+		config.Genome.AllowSelfConnections, _ = ffKey.Bool()
+	}
+	config.Genome.AllowRecurrentConnections = true
+	ffKey, err = genomeSection.GetKey("allow_recurrent_connections")
+	if err == nil {
+		config.Genome.AllowRecurrentConnections, _ = ffKey.Bool()
+	}
+	if !genomeSection.HasKey("recurrent_conn_prob") {
+		config.Genome.RecurrentConnProb = -1
+	}
+	// Re-enabling a connection may close a cycle; by default only recurrent genomes allow that.
+	config.Genome.AllowCyclicEnable = !config.Genome.FeedForward && config.Genome.AllowRecurrentConnections
+	ffKey, err = genomeSection.GetKey("allow_cyclic_enable")
+	if err == nil {
+		config.Genome.AllowCyclicEnable, _ = ffKey.Bool()
+	}
 	ffKey, err = genomeSection.GetKey("single_structural_mutation")
 	if err == nil {
 		config.Genome.SingleStructuralMutation, _ = ffKey.Bool()
 	}
+	ffKey, err = genomeSection.GetKey("bias_input")
+	if err == nil {
+		config.Genome.BiasInput, _ = ffKey.Bool()
+	}
+	ffKey, err = genomeSection.GetKey("weight_agnostic")
+	if err == nil {
+		config.Genome.WeightAgnostic, _ = ffKey.Bool()
+	}
+	ffKey, err = genomeSection.GetKey("evolve_activation_param")
+	if err == nil {
+		config.Genome.EvolveActivationParam, _ = ffKey.Bool()
+	}
+	// Activation parameters default to each function's standard shape.
+	if !genomeSection.HasKey("activation_param_init_mean") {
+		config.Genome.ActivationParamInitMean = 1.0
+	}
+	if !genomeSection.HasKey("activation_param_max_value") {
+		config.Genome.ActivationParamMaxValue = 5.0
+	}
+	if !genomeSection.HasKey("activation_param_min_value") {
+		config.Genome.ActivationParamMinValue = 0.1
+	}
+	ffKey, err = genomeSection.GetKey("connection_expression")
+	if err == nil {
+		config.Genome.ConnectionExpression, _ = ffKey.Bool()
+	}
+	// Expression probabilities default to fully expressed connections when not specified.
+	// The original NEAT paper keeps genes disabled in either parent disabled 75% of the time.
+	if !genomeSection.HasKey("crossover_disable_prob") {
+		config.Genome.CrossoverDisableProb = 0.75
+	}
+	if !genomeSection.HasKey("expression_init_mean") {
+		config.Genome.ExpressionInitMean = 1.0
+	}
+	if !genomeSection.HasKey("expression_max_value") {
+		config.Genome.ExpressionMaxValue = 1.0
+	}
+	if !cfg.Section("DefaultSpeciesSet").HasKey("speciation_signature_margin") {
+		config.SpeciesSet.SignatureMargin = 0.5
+	}
+	if !genomeSection.HasKey("disabled_purge_rate") {
+		config.Genome.DisabledPurgeRate = 1.0
+	}
 
 	// --- Explicitly clean potentially problematic string values ---
 	config.Genome.BiasInitType = cleanIniString(config.Genome.BiasInitType)
 	config.Genome.ResponseInitType = cleanIniString(config.Genome.ResponseInitType)
 	config.Genome.ActivationDefault = cleanIniString(config.Genome.ActivationDefault)
 	config.Genome.AggregationDefault = cleanIniString(config.Genome.AggregationDefault)
+	config.Genome.OutputActivation = cleanIniString(config.Genome.OutputActivation)
 	config.Genome.WeightInitType = cleanIniString(config.Genome.WeightInitType)
 	config.Genome.EnabledDefault = cleanIniString(config.Genome.EnabledDefault)
 	config.Genome.InitialConnection = cleanIniString(config.Genome.InitialConnection)
-	config.Genome.StructuralMutationSurer = cleanIniString(config.Genome.StructuralMutationSurer)
+	config.Genome.WeightDecayType = strings.ToLower(cleanIniString(config.Genome.WeightDecayType))
+	config.Genome.ExpressionMode = strings.ToLower(cleanIniString(config.Genome.ExpressionMode))
+	config.Genome.CrossoverEnabledMode = strings.ToLower(cleanIniString(config.Genome.CrossoverEnabledMode))
+	config.Genome.ExpressionInitType = cleanIniString(config.Genome.ExpressionInitType)
+	config.Genome.ActivationParamInitType = cleanIniString(config.Genome.ActivationParamInitType)
+	config.Genome.StructuralMutationSurer = strings.ToLower(cleanIniString(config.Genome.StructuralMutationSurer))
 	config.Neat.FitnessCriterion = cleanIniString(config.Neat.FitnessCriterion)
+	config.Neat.CheckpointFormat = strings.ToLower(cleanIniString(config.Neat.CheckpointFormat))
 	config.Stagnation.SpeciesFitnessFunc = cleanIniString(config.Stagnation.SpeciesFitnessFunc)
+	config.Stagnation.Policy = strings.ToLower(cleanIniString(config.Stagnation.Policy))
+	config.SpeciesSet.RepresentativeSelection = strings.ToLower(cleanIniString(config.SpeciesSet.RepresentativeSelection))
+	config.SpeciesSet.SpeciationMethod = strings.ToLower(cleanIniString(config.SpeciesSet.SpeciationMethod))
+	config.Reproduction.Strategy = strings.ToLower(cleanIniString(config.Reproduction.Strategy))
+	config.Reproduction.FitnessScaling = strings.ToLower(cleanIniString(config.Reproduction.FitnessScaling))
+	config.Reproduction.SpawnFitnessFunc = strings.ToLower(cleanIniString(config.Reproduction.SpawnFitnessFunc))
 	// Clean list options (trim spaces from each element)
 	for i, opt := range config.Genome.ActivationOptions {
 		config.Genome.ActivationOptions[i] = strings.TrimSpace(opt)
@@ -202,19 +423,60 @@ func LoadConfig(filePath string) (*Config, error) {
 	if config.Genome.WeightInitType == "" {
 		config.Genome.WeightInitType = "gaussian"
 	}
+	if config.Genome.CrossoverEnabledMode == "" {
+		config.Genome.CrossoverEnabledMode = "neat-python"
+	}
+	if config.Genome.ExpressionMode == "" {
+		config.Genome.ExpressionMode = "sample"
+	}
+	if config.Genome.ExpressionInitType == "" {
+		config.Genome.ExpressionInitType = "gaussian"
+	}
+	if config.Genome.WeightDecayType == "" {
+		config.Genome.WeightDecayType = "none"
+	}
 	if config.Genome.EnabledDefault == "" {
 		config.Genome.EnabledDefault = "True"
 	} // Python bool attribute parses this
-	// single_structural_mutation, structural_mutation_surer have Python defaults handled by tag/parsing logic
+	if config.Genome.InitialConnection == "" {
+		config.Genome.InitialConnection = "unconnected"
+	} // Default from Python Class
+	if config.Genome.StructuralMutationSurer == "" {
+		config.Genome.StructuralMutationSurer = "default"
+	} // Default from Python Class
 	if config.Reproduction.MinSpeciesSize == 0 {
 		config.Reproduction.MinSpeciesSize = 1
 	} // Default from Python Class
 	if config.Reproduction.SurvivalThreshold == 0 {
 		config.Reproduction.SurvivalThreshold = 0.2
 	} // Default from Python Class
+	if config.Reproduction.Strategy == "" {
+		config.Reproduction.Strategy = "default"
+	}
+	if config.Neat.CheckpointFormat == "" {
+		config.Neat.CheckpointFormat = "gob"
+	}
+	if !cfg.Section("DefaultReproduction").HasKey("min_survivors") {
+		config.Reproduction.MinSurvivors = 2
+	}
+	if config.Reproduction.FitnessScaling == "" {
+		config.Reproduction.FitnessScaling = "none"
+	}
+	if !cfg.Section("DefaultReproduction").HasKey("boltzmann_temperature") {
+		config.Reproduction.BoltzmannTemperature = 1.0
+	}
 	if config.Stagnation.SpeciesFitnessFunc == "" {
 		config.Stagnation.SpeciesFitnessFunc = "mean"
 	} // Default from Python Class
+	if config.Stagnation.Policy == "" {
+		config.Stagnation.Policy = "rank-elitism"
+	}
+	if config.SpeciesSet.RepresentativeSelection == "" {
+		config.SpeciesSet.RepresentativeSelection = "closest"
+	}
+	if config.SpeciesSet.SpeciationMethod == "" {
+		config.SpeciesSet.SpeciationMethod = "threshold"
+	}
 	if config.Stagnation.MaxStagnation == 0 {
 		config.Stagnation.MaxStagnation = 15
 	} // Default from Python Class
@@ -226,74 +488,214 @@ func LoadConfig(filePath string) (*Config, error) {
 	for i := 0; i < config.Genome.NumInputs; i++ {
 		config.Genome.InputKeys[i] = -(i + 1)
 	}
+	if key, ok := config.Genome.BiasInputKey(); ok {
+		config.Genome.InputKeys = append(config.Genome.InputKeys, key) // Connected and mutated like any input
+	}
 	config.Genome.OutputKeys = make([]int, config.Genome.NumOutputs)
 	for i := 0; i < config.Genome.NumOutputs; i++ {
 		config.Genome.OutputKeys[i] = i
 	}
-	// Initialize NodeKeyIndex (used for creating hidden nodes)
-	// Start indexing after output nodes (0..NumOutputs-1)
-	config.Genome.NodeKeyIndex = config.Genome.NumOutputs
+	// Hidden node keys start after the output nodes (0..NumOutputs-1)
+	config.Genome.innovations = NewInnovationTracker(config.Genome.NumOutputs)
+	config.Genome.seed = config.Neat.Seed
 
 	// Validate activation/aggregation options
 	if len(config.Genome.ActivationOptions) == 0 {
-		return nil, fmt.Errorf("config error: activation_options must be specified")
+		return nil, configErrorf("activation_options", "activation_options must be specified")
 	}
 	if len(config.Genome.AggregationOptions) == 0 {
-		return nil, fmt.Errorf("config error: aggregation_options must be specified")
+		return nil, configErrorf("aggregation_options", "aggregation_options must be specified")
+	}
+	for _, name := range config.Genome.ActivationOptions {
+		if _, err := GetActivation(name); err != nil {
+			return nil, configErrorf("activation_options", "invalid activation_options: %w", err)
+		}
+	}
+	for _, name := range config.Genome.AggregationOptions {
+		if _, err := GetAggregation(name); err != nil {
+			return nil, configErrorf("aggregation_options", "invalid aggregation_options: %w", err)
+		}
+	}
+	if config.Genome.OutputActivation != "" {
+		if _, err := GetActivation(config.Genome.OutputActivation); err != nil {
+			return nil, configErrorf("output_activation", "invalid output_activation: %w", err)
+		}
 	}
 
 	// Basic value validation (could be more extensive)
 	if config.Genome.NumInputs <= 0 {
-		return nil, fmt.Errorf("config error: num_inputs must be positive")
+		return nil, configErrorf("num_inputs", "num_inputs must be positive")
 	}
 	if config.Genome.NumOutputs <= 0 {
-		return nil, fmt.Errorf("config error: num_outputs must be positive")
+		return nil, configErrorf("num_outputs", "num_outputs must be positive")
 	}
 	if config.Genome.CompatibilityDisjointCoefficient < 0 {
-		return nil, fmt.Errorf("config error: compatibility_disjoint_coefficient cannot be negative")
+		return nil, configErrorf("compatibility_disjoint_coefficient", "compatibility_disjoint_coefficient cannot be negative")
 	}
 	if config.Genome.CompatibilityWeightCoefficient < 0 {
-		return nil, fmt.Errorf("config error: compatibility_weight_coefficient cannot be negative")
+		return nil, configErrorf("compatibility_weight_coefficient", "compatibility_weight_coefficient cannot be negative")
 	}
 	if config.Genome.ConnAddProb < 0 || config.Genome.ConnAddProb > 1 {
-		return nil, fmt.Errorf("config error: conn_add_prob must be between 0 and 1")
+		return nil, configErrorf("conn_add_prob", "conn_add_prob must be between 0 and 1")
 	}
 	if config.Genome.ConnDeleteProb < 0 || config.Genome.ConnDeleteProb > 1 {
-		return nil, fmt.Errorf("config error: conn_delete_prob must be between 0 and 1")
+		return nil, configErrorf("conn_delete_prob", "conn_delete_prob must be between 0 and 1")
 	}
 	if config.Genome.NodeAddProb < 0 || config.Genome.NodeAddProb > 1 {
-		return nil, fmt.Errorf("config error: node_add_prob must be between 0 and 1")
+		return nil, configErrorf("node_add_prob", "node_add_prob must be between 0 and 1")
 	}
 	if config.Genome.NodeDeleteProb < 0 || config.Genome.NodeDeleteProb > 1 {
-		return nil, fmt.Errorf("config error: node_delete_prob must be between 0 and 1")
+		return nil, configErrorf("node_delete_prob", "node_delete_prob must be between 0 and 1")
 	}
 	// Check min/max values
 	if config.Genome.BiasMaxValue < config.Genome.BiasMinValue {
-		return nil, fmt.Errorf("config error: bias_max_value cannot be less than bias_min_value")
+		return nil, configErrorf("bias_max_value", "bias_max_value cannot be less than bias_min_value")
 	}
 	if config.Genome.ResponseMaxValue < config.Genome.ResponseMinValue {
-		return nil, fmt.Errorf("config error: response_max_value cannot be less than response_min_value")
+		return nil, configErrorf("response_max_value", "response_max_value cannot be less than response_min_value")
 	}
 	if config.Genome.WeightMaxValue < config.Genome.WeightMinValue {
-		return nil, fmt.Errorf("config error: weight_max_value cannot be less than weight_min_value")
+		return nil, configErrorf("weight_max_value", "weight_max_value cannot be less than weight_min_value")
+	}
+	for _, it := range []struct{ Name, Value string }{
+		{"bias_init_type", config.Genome.BiasInitType},
+		{"response_init_type", config.Genome.ResponseInitType},
+		{"weight_init_type", config.Genome.WeightInitType},
+		{"expression_init_type", config.Genome.ExpressionInitType},
+		{"activation_param_init_type", config.Genome.ActivationParamInitType},
+	} {
+		switch strings.ToLower(it.Value) {
+		case "", "gaussian", "normal", "uniform":
+		default:
+			return nil, configErrorf(it.Name, "invalid %s '%s', must be one of 'gaussian', 'normal', 'uniform'", it.Name, it.Value)
+		}
+	}
+	if config.Genome.ActivationParamMaxValue < config.Genome.ActivationParamMinValue {
+		return nil, configErrorf("activation_param_max_value", "activation_param_max_value cannot be less than activation_param_min_value")
+	}
+	if config.Genome.ExpressionMaxValue < config.Genome.ExpressionMinValue {
+		return nil, configErrorf("expression_max_value", "expression_max_value cannot be less than expression_min_value")
+	}
+	if config.Genome.ExpressionMinValue < 0 || config.Genome.ExpressionMaxValue > 1 {
+		return nil, configErrorf("expression_min_value", "expression_min_value and expression_max_value must be between 0 and 1")
+	}
+	if config.Genome.CrossoverEnabledMode != "neat-python" && config.Genome.CrossoverEnabledMode != "paper" {
+		return nil, configErrorf("crossover_enabled_mode", "invalid crossover_enabled_mode '%s', must be one of 'neat-python', 'paper'", config.Genome.CrossoverEnabledMode)
+	}
+	if config.Genome.CrossoverDisableProb < 0 || config.Genome.CrossoverDisableProb > 1 {
+		return nil, configErrorf("crossover_disable_prob", "crossover_disable_prob must be between 0 and 1")
+	}
+	if config.Genome.RecurrentConnProb > 1 {
+		return nil, configErrorf("recurrent_conn_prob", "recurrent_conn_prob must be at most 1 (negative disables the bias)")
+	}
+	if config.Genome.ExpressionMode != "sample" && config.Genome.ExpressionMode != "expectation" {
+		return nil, configErrorf("expression_mode", "invalid expression_mode '%s', must be one of 'sample', 'expectation'", config.Genome.ExpressionMode)
+	}
+	switch config.Genome.WeightDecayType {
+	case "none", "l1":
+	case "multiplicative":
+		if config.Genome.WeightDecayRate > 1 {
+			return nil, configErrorf("weight_decay_rate", "weight_decay_rate must be between 0 and 1 for multiplicative decay")
+		}
+	default:
+		return nil, configErrorf("weight_decay_type", "invalid weight_decay_type '%s', must be one of 'none', 'multiplicative', 'l1'", config.Genome.WeightDecayType)
+	}
+	if config.Genome.WeightDecayRate < 0 {
+		return nil, configErrorf("weight_decay_rate", "weight_decay_rate cannot be negative")
+	}
+	if config.Genome.DisabledPurgeGenerations < 0 {
+		return nil, configErrorf("disabled_purge_generations", "disabled_purge_generations cannot be negative")
+	}
+	if config.Genome.DisabledPurgeRate < 0 || config.Genome.DisabledPurgeRate > 1 {
+		return nil, configErrorf("disabled_purge_rate", "disabled_purge_rate must be between 0 and 1")
+	}
+	if config.Genome.WeightPruneThreshold < 0 {
+		return nil, configErrorf("weight_prune_threshold", "weight_prune_threshold cannot be negative")
 	}
 	if config.Reproduction.SurvivalThreshold < 0 || config.Reproduction.SurvivalThreshold > 1 {
-		return nil, fmt.Errorf("config error: survival_threshold must be between 0 and 1")
+		return nil, configErrorf("survival_threshold", "survival_threshold must be between 0 and 1")
+	}
+	if config.Reproduction.AncestryGenerations < 0 {
+		return nil, configErrorf("ancestry_generations", "ancestry_generations cannot be negative")
+	}
+	if config.Reproduction.NoAncestry && config.Neat.EvaluationBudget > 0 {
+		return nil, configErrorf("evaluation_budget", "evaluation_budget needs ancestry tracking, which no_ancestry disables")
+	}
+	if config.Reproduction.NoAncestry && config.Experimental.StickySpeciation {
+		return nil, configErrorf("sticky_speciation", "sticky_speciation needs ancestry tracking, which no_ancestry disables")
+	}
+	if config.Reproduction.MinSurvivors < 1 {
+		return nil, configErrorf("min_survivors", "min_survivors must be positive")
+	}
+	if config.Reproduction.MaxSurvivors < 0 {
+		return nil, configErrorf("max_survivors", "max_survivors cannot be negative")
+	}
+	if config.Reproduction.MaxSurvivors > 0 && config.Reproduction.MaxSurvivors < config.Reproduction.MinSurvivors {
+		return nil, configErrorf("max_survivors", "max_survivors cannot be less than min_survivors")
 	}
 	if config.Reproduction.MinSpeciesSize <= 0 {
-		return nil, fmt.Errorf("config error: min_species_size must be positive")
+		return nil, configErrorf("min_species_size", "min_species_size must be positive")
 	}
 	if config.SpeciesSet.CompatibilityThreshold < 0 {
-		return nil, fmt.Errorf("config error: compatibility_threshold cannot be negative")
+		return nil, configErrorf("compatibility_threshold", "compatibility_threshold cannot be negative")
+	}
+	if config.SpeciesSet.SignatureSize < 0 {
+		return nil, configErrorf("speciation_signature_size", "speciation_signature_size cannot be negative")
+	}
+	if config.SpeciesSet.SignatureMargin < 0 {
+		return nil, configErrorf("speciation_signature_margin", "speciation_signature_margin cannot be negative")
+	}
+	if config.SpeciesSet.ReindexInterval < 0 {
+		return nil, configErrorf("species_reindex_interval", "species_reindex_interval cannot be negative")
+	}
+	if config.Neat.ComplexityPenalty < 0 {
+		return nil, configErrorf("complexity_penalty", "complexity_penalty cannot be negative")
+	}
+	if config.Neat.InputPenalty < 0 {
+		return nil, configErrorf("input_penalty", "input_penalty cannot be negative")
+	}
+	if config.Neat.EvaluationBudget < 0 {
+		return nil, configErrorf("evaluation_budget", "evaluation_budget cannot be negative")
+	}
+	if config.Neat.KeyOffset < 0 {
+		return nil, configErrorf("key_offset", "key_offset cannot be negative")
+	}
+	if config.Neat.EvaluationCacheSize < 0 {
+		return nil, configErrorf("evaluation_cache_size", "evaluation_cache_size cannot be negative")
+	}
+	if config.Neat.HallOfFameSize < 0 {
+		return nil, configErrorf("hall_of_fame_size", "hall_of_fame_size cannot be negative")
+	}
+	if config.Neat.ExtinctionReseedFraction < 0 || config.Neat.ExtinctionReseedFraction > 1 {
+		return nil, configErrorf("extinction_reseed_fraction", "extinction_reseed_fraction must be between 0 and 1")
 	}
 	if config.Stagnation.MaxStagnation <= 0 {
-		return nil, fmt.Errorf("config error: max_stagnation must be positive")
+		return nil, configErrorf("max_stagnation", "max_stagnation must be positive")
+	}
+	if config.Stagnation.Policy == "max-age" && config.Stagnation.MaxSpeciesAge <= 0 {
+		return nil, configErrorf("max_species_age", "max_species_age must be positive with stagnation_policy 'max-age'")
+	}
+	if config.Stagnation.ImprovementWindow < 0 {
+		return nil, configErrorf("improvement_window", "improvement_window cannot be negative")
+	}
+	if config.Stagnation.FitnessHistoryLength < -1 {
+		return nil, configErrorf("fitness_history_length", "fitness_history_length must be -1 (unbounded), 0 (max_stagnation) or positive")
 	}
 
 	// Validate fitness criterion
 	validCriteria := map[string]bool{"max": true, "min": true, "mean": true}
 	if !validCriteria[strings.ToLower(config.Neat.FitnessCriterion)] {
-		return nil, fmt.Errorf("config error: invalid fitness_criterion '%s', must be one of 'max', 'min', 'mean'", config.Neat.FitnessCriterion)
+		return nil, configErrorf("fitness_criterion", "invalid fitness_criterion '%s', must be one of 'max', 'min', 'mean'", config.Neat.FitnessCriterion)
+	}
+
+	if config.Neat.CheckpointFormat != "gob" && config.Neat.CheckpointFormat != "binary" {
+		return nil, configErrorf("checkpoint_format", "invalid checkpoint_format '%s', must be 'gob' or 'binary'", config.Neat.CheckpointFormat)
+	}
+
+	switch config.Genome.StructuralMutationSurer {
+	case "default", "true", "yes", "on", "1", "false", "no", "off", "0":
+	default:
+		return nil, configErrorf("structural_mutation_surer", "invalid structural_mutation_surer '%s', must be 'default' or a boolean", config.Genome.StructuralMutationSurer)
 	}
 
 	// Validate initial connection type (more complex types like 'partial N' require further parsing later)
@@ -302,25 +704,166 @@ func LoadConfig(filePath string) (*Config, error) {
 		"full_nodirect": true, "full": true, "full_direct": true,
 		"partial_nodirect": true, "partial": true, "partial_direct": true,
 	}
-	baseConnection := strings.Fields(config.Genome.InitialConnection)[0]
+	baseConnection, _, err := parseInitialConnection(config.Genome.InitialConnection)
+	if err != nil {
+		return nil, configErrorf("initial_connection", "%w", err)
+	}
 	if !validConnections[baseConnection] {
-		return nil, fmt.Errorf("config error: invalid initial_connection type '%s'", baseConnection)
+		return nil, configErrorf("initial_connection", "invalid initial_connection type '%s'", baseConnection)
+	}
+
+	// Validate reproduction strategy
+	validStrategies := map[string]bool{"default": true, "nsga2": true}
+	if !validStrategies[config.Reproduction.Strategy] {
+		return nil, configErrorf("strategy", "invalid reproduction strategy '%s', must be one of 'default', 'nsga2'", config.Reproduction.Strategy)
+	}
+	if _, ok := lookupFitnessScaling(config.Reproduction.FitnessScaling); !ok {
+		return nil, configErrorf("fitness_scaling", "invalid fitness_scaling '%s', must be one of %s", config.Reproduction.FitnessScaling, strings.Join(fitnessScalingNames(), ", "))
+	}
+	validSpawnFuncs := map[string]bool{"": true, "mean": true, "max": true, "min": true, "median": true}
+	if !validSpawnFuncs[config.Reproduction.SpawnFitnessFunc] {
+		return nil, configErrorf("spawn_fitness_func", "invalid spawn_fitness_func '%s', must be one of 'mean', 'max', 'min', 'median'", config.Reproduction.SpawnFitnessFunc)
+	}
+	if config.Reproduction.BoltzmannTemperature <= 0 {
+		return nil, configErrorf("boltzmann_temperature", "boltzmann_temperature must be positive")
 	}
 
 	// Validate stagnation fitness function
 	validStagnationFuncs := map[string]bool{"max": true, "min": true, "mean": true, "median": true, "sum": true} // Based on Python math_util
 	if !validStagnationFuncs[strings.ToLower(config.Stagnation.SpeciesFitnessFunc)] {
-		return nil, fmt.Errorf("config error: invalid species_fitness_func '%s'", config.Stagnation.SpeciesFitnessFunc)
+		return nil, configErrorf("species_fitness_func", "invalid species_fitness_func '%s'", config.Stagnation.SpeciesFitnessFunc)
+	}
+
+	// Validate stagnation policy
+	validPolicies := map[string]bool{"rank-elitism": true, "absolute": true, "none": true, "max-age": true, "improvement-rate": true}
+	if !validPolicies[config.Stagnation.Policy] {
+		return nil, configErrorf("stagnation_policy", "invalid stagnation_policy '%s', must be one of 'rank-elitism', 'absolute', 'none', 'max-age', 'improvement-rate'", config.Stagnation.Policy)
+	}
+
+	// Validate representative selection
+	validSelections := map[string]bool{"closest": true, "champion": true, "random": true, "medoid": true}
+	if !validSelections[config.SpeciesSet.RepresentativeSelection] {
+		return nil, configErrorf("representative_selection", "invalid representative_selection '%s', must be one of 'closest', 'champion', 'random', 'medoid'", config.SpeciesSet.RepresentativeSelection)
+	}
+
+	// Validate speciation method
+	validMethods := map[string]bool{"threshold": true, "cluster": true, "behavior": true}
+	if !validMethods[config.SpeciesSet.SpeciationMethod] {
+		return nil, configErrorf("speciation_method", "invalid speciation_method '%s', must be one of 'threshold', 'cluster', 'behavior'", config.SpeciesSet.SpeciationMethod)
+	}
+	if config.SpeciesSet.SpeciationMethod == "cluster" && config.SpeciesSet.SpeciationClusters <= 0 {
+		return nil, configErrorf("speciation_clusters", "speciation_clusters must be positive with speciation_method 'cluster'")
 	}
 
 	return config, nil
 }
 
-// Helper to get next node key - ensures unique positive integers >= NumOutputs
+// Copy returns a copy of the genome configuration with its own option and key slices.
+func (gc *GenomeConfig) Copy() *GenomeConfig {
+	c := *gc
+	c.ActivationOptions = append([]string(nil), gc.ActivationOptions...)
+	c.AggregationOptions = append([]string(nil), gc.AggregationOptions...)
+	c.InputKeys = append([]int(nil), gc.InputKeys...)
+	c.OutputKeys = append([]int(nil), gc.OutputKeys...)
+	c.innovations = gc.Innovations().clone()
+	return &c
+}
+
+// historyLimit returns the number of fitness history entries to keep per species (0 = unbounded).
+// By default only the max_stagnation most recent values are retained.
+func (sc *StagnationConfig) historyLimit() int {
+	switch {
+	case sc.FitnessHistoryLength < 0:
+		return 0
+	case sc.FitnessHistoryLength == 0:
+		return sc.MaxStagnation
+	default:
+		return sc.FitnessHistoryLength
+	}
+}
+
+// improvementWindow returns the number of generations the improvement rate is measured over.
+func (sc *StagnationConfig) improvementWindow() int {
+	if sc.ImprovementWindow == 0 {
+		return sc.MaxStagnation
+	}
+	return sc.ImprovementWindow
+}
+
+// IsInputKey reports whether key identifies one of the genome's inputs, including the bias input.
+func (gc *GenomeConfig) IsInputKey(key int) bool {
+	numInputs := gc.NumInputs
+	if gc.BiasInput {
+		numInputs++
+	}
+	return key < 0 && key >= -numInputs
+}
+
+// BiasInputKey returns the key of the always-on bias input and whether bias_input is enabled.
+// Networks set this input to 1 themselves, so callers only supply the num_inputs regular inputs.
+func (gc *GenomeConfig) BiasInputKey() (int, bool) {
+	return -(gc.NumInputs + 1), gc.BiasInput
+}
+
+// IsOutputKey reports whether key identifies one of the genome's output nodes.
+func (gc *GenomeConfig) IsOutputKey(key int) bool {
+	return key >= 0 && key < gc.NumOutputs
+}
+
+// hasFrozenOutputs reports whether output nodes use a fixed activation and response.
+func (gc *GenomeConfig) hasFrozenOutputs() bool {
+	return gc.OutputActivation != ""
+}
+
+// structuralMutationSurer reports whether structural mutations that can't apply as drawn fall back
+// to the closest change that can: adding a connection instead of splitting one when there is none,
+// and re-enabling a chosen connection that already exists. "default" follows
+// single_structural_mutation, as in neat-python.
+func (gc *GenomeConfig) structuralMutationSurer() bool {
+	switch gc.StructuralMutationSurer {
+	case "true", "yes", "on", "1":
+		return true
+	case "default":
+		return gc.SingleStructuralMutation
+	}
+	return false
+}
+
+// innovationsInit guards the lazy creation of trackers for configs not made by LoadConfig.
+var innovationsInit sync.Mutex
+
+// Innovations returns the tracker assigning new node keys to genomes using this config.
+func (gc *GenomeConfig) Innovations() *InnovationTracker {
+	innovationsInit.Lock()
+	defer innovationsInit.Unlock()
+	if gc.innovations == nil {
+		gc.innovations = NewInnovationTracker(gc.NumOutputs)
+	}
+	return gc.innovations
+}
+
+// GetNewNodeKey returns a node key that has not been used by any genome sharing this config.
 func (gc *GenomeConfig) GetNewNodeKey() int {
-	key := gc.NodeKeyIndex
-	gc.NodeKeyIndex++
-	return key
+	return gc.Innovations().NewNodeKey(nil)
+}
+
+// SetGenomeFactory replaces the factory building and varying genomes using this config; nil
+// restores DefaultGenomeFactory.
+func (gc *GenomeConfig) SetGenomeFactory(factory GenomeFactory) {
+	gc.factory = factory
+}
+
+// genomeFactory returns the factory building and varying genomes using this config.
+func (gc *GenomeConfig) genomeFactory() GenomeFactory {
+	if gc == nil || gc.factory == nil {
+		return DefaultGenomeFactory{}
+	}
+	return gc.factory
+}
+
+// log returns the logger for genome-level diagnostics.
+func (gc *GenomeConfig) log() Logger {
+	return loggerOrDefault(gc.logger)
 }
 
 // cleanIniString removes inline comments and trims whitespace from a string read from INI.
@@ -0,0 +1,118 @@
+package neat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// configSection pairs a config file section with the struct it is read into.
+type configSection struct {
+	name   string
+	fields any // Pointer to the struct
+}
+
+// sections returns the sections of c in the order they are written.
+func (c *Config) sections() []configSection {
+	return []configSection{
+		{"NEAT", &c.Neat},
+		{"DefaultGenome", &c.Genome},
+		{"DefaultReproduction", &c.Reproduction},
+		{"DefaultSpeciesSet", &c.SpeciesSet},
+		{"DefaultStagnation", &c.Stagnation},
+		{"Experimental", &c.Experimental},
+	}
+}
+
+// Save writes the effective configuration, with every default filled in and any changes made
+// during the run, so the exact parameters of a run can be archived with its checkpoints and
+// results. format is "ini", which LoadConfig reads back into an equal config, or "json", which
+// also lists the derived input_keys and output_keys.
+func (c *Config) Save(filePath string, format string) error {
+	var data []byte
+	switch strings.ToLower(format) {
+	case "ini":
+		data = []byte(c.iniString())
+	case "json":
+		var err error
+		if data, err = json.MarshalIndent(c.jsonSections(), "", "  "); err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid config format '%s', must be 'ini' or 'json'", format)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// iniString formats the config as a config file.
+func (c *Config) iniString() string {
+	var b strings.Builder
+	for i, section := range c.sections() {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s]\n", section.name)
+		forEachIniField(section.fields, func(key string, value reflect.Value) {
+			fmt.Fprintf(&b, "%s = %s\n", key, formatIniValue(value))
+		})
+	}
+	if len(c.Schedules) > 0 {
+		b.WriteString("\n[Schedules]\n")
+		for _, s := range c.Schedules {
+			fmt.Fprintf(&b, "%s = %s\n", s.Key, s.String())
+		}
+	}
+	return b.String()
+}
+
+// jsonSections returns the config as section -> key -> value, with the derived keys and schedules.
+func (c *Config) jsonSections() map[string]map[string]any {
+	sections := make(map[string]map[string]any)
+	for _, section := range c.sections() {
+		values := make(map[string]any)
+		forEachIniField(section.fields, func(key string, value reflect.Value) {
+			values[key] = value.Interface()
+		})
+		sections[section.name] = values
+	}
+	sections["DefaultGenome"]["input_keys"] = c.Genome.InputKeys
+	sections["DefaultGenome"]["output_keys"] = c.Genome.OutputKeys
+	schedules := make(map[string]any)
+	for _, s := range c.Schedules {
+		schedules[s.Key] = s.String()
+	}
+	sections["Schedules"] = schedules
+	return sections
+}
+
+// forEachIniField calls fn with the key and value of every field of *ptr read from the config file.
+func forEachIniField(ptr any, fn func(key string, value reflect.Value)) {
+	v := reflect.ValueOf(ptr).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if key := v.Type().Field(i).Tag.Get("ini"); key != "" {
+			fn(key, v.Field(i))
+		}
+	}
+}
+
+// formatIniValue formats a field value the way LoadConfig reads it back.
+func formatIniValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return "True"
+		}
+		return "False"
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Slice:
+		return strings.Join(v.Interface().([]string), " ")
+	}
+	return fmt.Sprint(v.Interface())
+}
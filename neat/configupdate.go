@@ -0,0 +1,142 @@
+package neat
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// ConfigPatch maps the ini keys of adjustable parameters to new values, e.g.
+// {"weight_mutate_power": 0.3, "compatibility_threshold": 2.5, "elitism": 1}. Integer parameters
+// must be given whole values.
+type ConfigPatch map[string]float64
+
+// ConfigChange records one parameter changed during a run.
+type ConfigChange struct {
+	Generation int // Last generation run before the change (0 = before the first)
+	Key        string
+	Old        float64
+	New        float64
+}
+
+// adjustableParam is a parameter UpdateConfig may change: a pointer to its float or int field
+// and its allowed range.
+type adjustableParam struct {
+	float    func(c *Config) *float64
+	int      func(c *Config) *int
+	min, max float64
+}
+
+func probabilityParam(field func(c *Config) *float64) adjustableParam {
+	return adjustableParam{float: field, min: 0, max: 1}
+}
+
+func nonNegativeParam(field func(c *Config) *float64) adjustableParam {
+	return adjustableParam{float: field, min: 0, max: math.Inf(1)}
+}
+
+// adjustableParams lists the parameters that can safely change between generations: they are
+// read afresh whenever they are used, and no state derived from them needs rebuilding.
+var adjustableParams = map[string]adjustableParam{
+	"conn_add_prob":           probabilityParam(func(c *Config) *float64 { return &c.Genome.ConnAddProb }),
+	"conn_delete_prob":        probabilityParam(func(c *Config) *float64 { return &c.Genome.ConnDeleteProb }),
+	"node_add_prob":           probabilityParam(func(c *Config) *float64 { return &c.Genome.NodeAddProb }),
+	"node_delete_prob":        probabilityParam(func(c *Config) *float64 { return &c.Genome.NodeDeleteProb }),
+	"bias_mutate_rate":        probabilityParam(func(c *Config) *float64 { return &c.Genome.BiasMutateRate }),
+	"bias_replace_rate":       probabilityParam(func(c *Config) *float64 { return &c.Genome.BiasReplaceRate }),
+	"bias_mutate_power":       nonNegativeParam(func(c *Config) *float64 { return &c.Genome.BiasMutatePower }),
+	"response_mutate_rate":    probabilityParam(func(c *Config) *float64 { return &c.Genome.ResponseMutateRate }),
+	"response_replace_rate":   probabilityParam(func(c *Config) *float64 { return &c.Genome.ResponseReplaceRate }),
+	"response_mutate_power":   nonNegativeParam(func(c *Config) *float64 { return &c.Genome.ResponseMutatePower }),
+	"weight_mutate_rate":      probabilityParam(func(c *Config) *float64 { return &c.Genome.WeightMutateRate }),
+	"weight_replace_rate":     probabilityParam(func(c *Config) *float64 { return &c.Genome.WeightReplaceRate }),
+	"weight_mutate_power":     nonNegativeParam(func(c *Config) *float64 { return &c.Genome.WeightMutatePower }),
+	"activation_mutate_rate":  probabilityParam(func(c *Config) *float64 { return &c.Genome.ActivationMutateRate }),
+	"aggregation_mutate_rate": probabilityParam(func(c *Config) *float64 { return &c.Genome.AggregationMutateRate }),
+	"enabled_mutate_rate":     probabilityParam(func(c *Config) *float64 { return &c.Genome.EnabledMutateRate }),
+
+	"compatibility_threshold":            nonNegativeParam(func(c *Config) *float64 { return &c.SpeciesSet.CompatibilityThreshold }),
+	"compatibility_disjoint_coefficient": nonNegativeParam(func(c *Config) *float64 { return &c.Genome.CompatibilityDisjointCoefficient }),
+	"compatibility_weight_coefficient":   nonNegativeParam(func(c *Config) *float64 { return &c.Genome.CompatibilityWeightCoefficient }),
+
+	"elitism":            {int: func(c *Config) *int { return &c.Reproduction.Elitism }, min: 0, max: math.Inf(1)},
+	"survival_threshold": probabilityParam(func(c *Config) *float64 { return &c.Reproduction.SurvivalThreshold }),
+	"species_elitism":    {int: func(c *Config) *int { return &c.Stagnation.SpeciesElitism }, min: 0, max: math.Inf(1)},
+	"max_stagnation":     {int: func(c *Config) *int { return &c.Stagnation.MaxStagnation }, min: 1, max: math.Inf(1)},
+	"complexity_penalty": nonNegativeParam(func(c *Config) *float64 { return &c.Neat.ComplexityPenalty }),
+	"input_penalty":      nonNegativeParam(func(c *Config) *float64 { return &c.Neat.InputPenalty }),
+}
+
+// AdjustableConfigKeys returns the ini keys UpdateConfig accepts, sorted.
+func AdjustableConfigKeys() []string {
+	keys := make([]string, 0, len(adjustableParams))
+	for key := range adjustableParams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// UpdateConfig changes selected parameters of a running population, so long runs can be tuned
+// without restarting; see AdjustableConfigKeys for the parameters it accepts. The whole patch is
+// validated before anything changes, so an invalid entry leaves the config untouched. Changed
+// values are logged and, if Statistics is set, recorded in Statistics.ConfigChanges. They take
+// effect from the next generation and are saved in checkpoints, which apply them on top of the
+// config file when loaded.
+func (p *Population) UpdateConfig(patch ConfigPatch) error {
+	keys := make([]string, 0, len(patch))
+	for key, value := range patch {
+		param, ok := adjustableParams[key]
+		if !ok {
+			return configErrorf(key, "'%s' cannot be changed during a run, must be one of %s", key, strings.Join(AdjustableConfigKeys(), ", "))
+		}
+		if math.IsNaN(value) || value < param.min || value > param.max {
+			return configErrorf(key, "invalid %s %v, must be between %v and %v", key, value, param.min, param.max)
+		}
+		if param.int != nil && value != math.Trunc(value) {
+			return configErrorf(key, "invalid %s %v, must be a whole number", key, value)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		param, value := adjustableParams[key], patch[key]
+		var old float64
+		if param.int != nil {
+			field := param.int(p.Config)
+			old = float64(*field)
+			*field = int(value)
+		} else {
+			field := param.float(p.Config)
+			old = *field
+			*field = value
+		}
+		if old == value {
+			continue
+		}
+		p.log().Info("Config parameter changed", "generation", p.Generation, "key", key, "old", old, "new", value)
+		change := ConfigChange{Generation: p.Generation, Key: key, Old: old, New: value}
+		p.configChanges = append(p.configChanges, change)
+		if p.Statistics != nil {
+			p.Statistics.ConfigChanges = append(p.Statistics.ConfigChanges, change)
+		}
+	}
+	return nil
+}
+
+// applyConfigChanges replays changes recorded by UpdateConfig onto config, in order.
+func applyConfigChanges(config *Config, changes []ConfigChange) error {
+	for _, change := range changes {
+		param, ok := adjustableParams[change.Key]
+		if !ok {
+			return configErrorf(change.Key, "'%s' cannot be changed during a run", change.Key)
+		}
+		if param.int != nil {
+			*param.int(config) = int(change.New)
+		} else {
+			*param.float(config) = change.New
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package neat
+
+import "fmt"
+
+// CurriculumProgress describes the active stage's history, as seen by a PromotionFunc.
+// The fitness slices hold one value per generation spent in the stage, oldest first.
+type CurriculumProgress struct {
+	Stage       int       // Index of the active stage
+	Generations int       // Generations evaluated in the active stage
+	MeanFitness []float64 // Mean fitness of each generation in the stage
+	BestFitness []float64 // Best fitness of each generation in the stage
+}
+
+// PromotionFunc reports whether the population is ready to move on from the active stage.
+type PromotionFunc func(progress CurriculumProgress) bool
+
+// MeanFitnessAbove promotes once the mean fitness has been at least threshold for the last k generations.
+func MeanFitnessAbove(threshold float64, k int) PromotionFunc {
+	return func(progress CurriculumProgress) bool {
+		return lastAtLeast(progress.MeanFitness, threshold, k)
+	}
+}
+
+// BestFitnessAbove promotes once the best fitness has been at least threshold for the last k generations.
+func BestFitnessAbove(threshold float64, k int) PromotionFunc {
+	return func(progress CurriculumProgress) bool {
+		return lastAtLeast(progress.BestFitness, threshold, k)
+	}
+}
+
+// lastAtLeast reports whether the last k values are all at least threshold.
+func lastAtLeast(values []float64, threshold float64, k int) bool {
+	k = max(k, 1)
+	if len(values) < k {
+		return false
+	}
+	for _, v := range values[len(values)-k:] {
+		if v < threshold {
+			return false
+		}
+	}
+	return true
+}
+
+// CurriculumStage is one fitness function of a curriculum and the criterion for leaving it.
+// The last stage's Promote is never consulted.
+type CurriculumStage struct {
+	Name    string
+	Fitness FitnessFunc
+	Promote PromotionFunc
+}
+
+// StageTransition records a promotion from one curriculum stage to the next.
+type StageTransition struct {
+	Generation int // Generation whose evaluation triggered the promotion
+	From       int
+	To         int
+}
+
+// CurriculumState is the part of a curriculum saved in checkpoints.
+type CurriculumState struct {
+	Stage       int
+	MeanFitness []float64
+	BestFitness []float64
+	Transitions []StageTransition
+}
+
+// CurriculumScheduler evaluates the population with a sequence of fitness stages, promoting to the
+// next stage when the active stage's criterion is met. Attach it with Population.SetCurriculum.
+type CurriculumScheduler struct {
+	Stages []CurriculumStage
+	State  CurriculumState
+}
+
+// NewCurriculumScheduler creates a scheduler starting at the first of the given stages.
+func NewCurriculumScheduler(stages ...CurriculumStage) (*CurriculumScheduler, error) {
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("curriculum requires at least one stage")
+	}
+	for i, stage := range stages {
+		if stage.Fitness == nil {
+			return nil, fmt.Errorf("curriculum stage %d (%s) has no fitness function", i, stage.Name)
+		}
+		if stage.Promote == nil && i < len(stages)-1 {
+			return nil, fmt.Errorf("curriculum stage %d (%s) has no promotion criterion", i, stage.Name)
+		}
+	}
+	return &CurriculumScheduler{Stages: stages}, nil
+}
+
+// ActiveStage returns the stage currently used for evaluation.
+func (cs *CurriculumScheduler) ActiveStage() CurriculumStage {
+	return cs.Stages[cs.State.Stage]
+}
+
+// Progress returns the history of the active stage.
+func (cs *CurriculumScheduler) Progress() CurriculumProgress {
+	return CurriculumProgress{
+		Stage:       cs.State.Stage,
+		Generations: len(cs.State.MeanFitness),
+		MeanFitness: cs.State.MeanFitness,
+		BestFitness: cs.State.BestFitness,
+	}
+}
+
+// record adds one generation's fitness to the active stage's history and promotes if its criterion
+// is met. It returns the transition, or nil if the stage did not change.
+func (cs *CurriculumScheduler) record(generation int, genomes map[int]*Genome) *StageTransition {
+	fitnesses := make([]float64, 0, len(genomes))
+	for _, g := range genomes {
+		fitnesses = append(fitnesses, g.Fitness)
+	}
+	if len(fitnesses) == 0 {
+		return nil
+	}
+	cs.State.MeanFitness = append(cs.State.MeanFitness, Mean(fitnesses))
+	cs.State.BestFitness = append(cs.State.BestFitness, MaxFloat(fitnesses))
+
+	if cs.State.Stage >= len(cs.Stages)-1 || !cs.ActiveStage().Promote(cs.Progress()) {
+		return nil
+	}
+	transition := StageTransition{Generation: generation, From: cs.State.Stage, To: cs.State.Stage + 1}
+	cs.State.Stage++
+	cs.State.MeanFitness = nil
+	cs.State.BestFitness = nil
+	cs.State.Transitions = append(cs.State.Transitions, transition)
+	return &transition
+}
+
+// SetCurriculum makes the population evaluate genomes with the curriculum's active stage. While a
+// curriculum is set, RunGeneration ignores its fitnessFunc argument (pass nil).
+// Because fitness scales usually differ between stages, BestGenome and HallOfFame are reset on every promotion.
+// If the population was loaded from a checkpoint that recorded curriculum progress, the
+// scheduler resumes from that progress.
+func (p *Population) SetCurriculum(cs *CurriculumScheduler) error {
+	if cs != nil && p.restoredCurriculum != nil {
+		if p.restoredCurriculum.Stage >= len(cs.Stages) {
+			return fmt.Errorf("checkpoint is at curriculum stage %d but the curriculum has %d stages", p.restoredCurriculum.Stage, len(cs.Stages))
+		}
+		cs.State = *p.restoredCurriculum
+		p.restoredCurriculum = nil
+	}
+	p.Curriculum = cs
+	return nil
+}
+
+// advanceCurriculum records the evaluated generation in the curriculum and applies any promotion.
+func (p *Population) advanceCurriculum() {
+	if p.Curriculum == nil {
+		return
+	}
+	transition := p.Curriculum.record(p.Generation, p.Population)
+	if transition == nil {
+		return
+	}
+	p.log().Info("Curriculum stage promoted", "generation", p.Generation,
+		"from", p.Curriculum.Stages[transition.From].Name, "to", p.Curriculum.Stages[transition.To].Name)
+	p.BestGenome = nil
+	p.HallOfFame = nil
+	if p.EvaluationCache != nil {
+		p.EvaluationCache.Clear() // Cached fitness came from the previous stage's function
+	}
+	if p.Statistics != nil {
+		p.Statistics.StageTransitions = append(p.Statistics.StageTransitions, *transition)
+	}
+}
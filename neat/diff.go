@@ -0,0 +1,159 @@
+package neat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConnectionDiff describes a connection gene present in both genomes whose attributes differ.
+type ConnectionDiff struct {
+	Key      ConnectionKey
+	WeightA  float64
+	WeightB  float64
+	EnabledA bool
+	EnabledB bool
+}
+
+// NodeDiff describes a node gene present in both genomes whose attributes differ.
+type NodeDiff struct {
+	Key        int
+	A          *NodeGene
+	B          *NodeGene
+	Attributes []string // Names of the differing attributes, e.g. "bias", "activation"
+}
+
+// GenomeDiff is a structured comparison of two genomes, produced by DiffGenomes.
+// All slices are sorted by key.
+type GenomeDiff struct {
+	KeyA int
+	KeyB int
+
+	MatchingConnections []ConnectionKey  // Connections present in both genomes
+	ConnectionsOnlyA    []ConnectionKey  // Disjoint/excess connections of A
+	ConnectionsOnlyB    []ConnectionKey  // Disjoint/excess connections of B
+	ConnectionChanges   []ConnectionDiff // Matching connections with a different weight or enabled flag
+
+	MatchingNodes []int      // Nodes present in both genomes
+	NodesOnlyA    []int      // Nodes only in A
+	NodesOnlyB    []int      // Nodes only in B
+	NodeChanges   []NodeDiff // Matching nodes with differing attributes
+}
+
+// Identical reports whether the genomes have the same genes with the same attributes.
+func (d *GenomeDiff) Identical() bool {
+	return len(d.ConnectionsOnlyA) == 0 && len(d.ConnectionsOnlyB) == 0 && len(d.ConnectionChanges) == 0 &&
+		len(d.NodesOnlyA) == 0 && len(d.NodesOnlyB) == 0 && len(d.NodeChanges) == 0
+}
+
+// DiffGenomes compares the node and connection genes of a and b.
+func DiffGenomes(a, b *Genome) *GenomeDiff {
+	d := &GenomeDiff{KeyA: a.Key, KeyB: b.Key}
+
+	for _, ck := range sortedConnectionKeys(a) {
+		ca := a.Connections[ck]
+		cb, ok := b.Connections[ck]
+		if !ok {
+			d.ConnectionsOnlyA = append(d.ConnectionsOnlyA, ck)
+			continue
+		}
+		d.MatchingConnections = append(d.MatchingConnections, ck)
+		if ca.Weight != cb.Weight || ca.Enabled != cb.Enabled {
+			d.ConnectionChanges = append(d.ConnectionChanges, ConnectionDiff{
+				Key: ck, WeightA: ca.Weight, WeightB: cb.Weight, EnabledA: ca.Enabled, EnabledB: cb.Enabled,
+			})
+		}
+	}
+	for _, ck := range sortedConnectionKeys(b) {
+		if _, ok := a.Connections[ck]; !ok {
+			d.ConnectionsOnlyB = append(d.ConnectionsOnlyB, ck)
+		}
+	}
+
+	for _, k := range sortedNodeKeys(a) {
+		na := a.Nodes[k]
+		nb, ok := b.Nodes[k]
+		if !ok {
+			d.NodesOnlyA = append(d.NodesOnlyA, k)
+			continue
+		}
+		d.MatchingNodes = append(d.MatchingNodes, k)
+		if attrs := nodeAttributeDiffs(na, nb); len(attrs) > 0 {
+			d.NodeChanges = append(d.NodeChanges, NodeDiff{Key: k, A: na, B: nb, Attributes: attrs})
+		}
+	}
+	for _, k := range sortedNodeKeys(b) {
+		if _, ok := a.Nodes[k]; !ok {
+			d.NodesOnlyB = append(d.NodesOnlyB, k)
+		}
+	}
+	return d
+}
+
+// nodeAttributeDiffs returns the names of the attributes that differ between two node genes.
+func nodeAttributeDiffs(a, b *NodeGene) []string {
+	var attrs []string
+	if a.Bias != b.Bias {
+		attrs = append(attrs, "bias")
+	}
+	if a.Response != b.Response {
+		attrs = append(attrs, "response")
+	}
+	if a.Activation != b.Activation {
+		attrs = append(attrs, "activation")
+	}
+	if a.Aggregation != b.Aggregation {
+		attrs = append(attrs, "aggregation")
+	}
+	if a.ActivationParam != b.ActivationParam {
+		attrs = append(attrs, "activation_param")
+	}
+	return attrs
+}
+
+// String renders the diff as a readable multi-line summary.
+func (d *GenomeDiff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Genome %d vs %d\n", d.KeyA, d.KeyB)
+	fmt.Fprintf(&b, "Nodes: %d matching, %d only in %d, %d only in %d\n",
+		len(d.MatchingNodes), len(d.NodesOnlyA), d.KeyA, len(d.NodesOnlyB), d.KeyB)
+	for _, k := range d.NodesOnlyA {
+		fmt.Fprintf(&b, "  - node %d\n", k)
+	}
+	for _, k := range d.NodesOnlyB {
+		fmt.Fprintf(&b, "  + node %d\n", k)
+	}
+	for _, nd := range d.NodeChanges {
+		fmt.Fprintf(&b, "  ~ node %d:", nd.Key)
+		for _, attr := range nd.Attributes {
+			switch attr {
+			case "bias":
+				fmt.Fprintf(&b, " bias %.4f -> %.4f", nd.A.Bias, nd.B.Bias)
+			case "response":
+				fmt.Fprintf(&b, " response %.4f -> %.4f", nd.A.Response, nd.B.Response)
+			case "activation":
+				fmt.Fprintf(&b, " activation %s -> %s", nd.A.Activation, nd.B.Activation)
+			case "aggregation":
+				fmt.Fprintf(&b, " aggregation %s -> %s", nd.A.Aggregation, nd.B.Aggregation)
+			case "activation_param":
+				fmt.Fprintf(&b, " activation_param %.4f -> %.4f", nd.A.ActivationParam, nd.B.ActivationParam)
+			}
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Connections: %d matching, %d only in %d, %d only in %d\n",
+		len(d.MatchingConnections), len(d.ConnectionsOnlyA), d.KeyA, len(d.ConnectionsOnlyB), d.KeyB)
+	for _, ck := range d.ConnectionsOnlyA {
+		fmt.Fprintf(&b, "  - %d -> %d\n", ck.InNodeID, ck.OutNodeID)
+	}
+	for _, ck := range d.ConnectionsOnlyB {
+		fmt.Fprintf(&b, "  + %d -> %d\n", ck.InNodeID, ck.OutNodeID)
+	}
+	for _, cd := range d.ConnectionChanges {
+		fmt.Fprintf(&b, "  ~ %d -> %d: weight %.4f -> %.4f", cd.Key.InNodeID, cd.Key.OutNodeID, cd.WeightA, cd.WeightB)
+		if cd.EnabledA != cd.EnabledB {
+			fmt.Fprintf(&b, ", enabled %t -> %t", cd.EnabledA, cd.EnabledB)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+// Package neat implements NeuroEvolution of Augmenting Topologies, following neat-python.
+// A Population evolves Genomes described by a Config; phenotypes are built from genomes by the
+// nn subpackage, and the eval subpackage provides ready-made fitness functions.
+//
+// # Concurrency
+//
+// A Population is driven by one goroutine: RunGeneration (or Run, which calls it in a loop) fails
+// if called while another generation of the same population is running, and hooks, reporters and
+// the fitness function run inside it.
+// Other goroutines read the population through Inspect, which waits for the running generation
+// to finish; Snapshot, called from Inspect or a hook, copies the state for analysis that may run
+// while evolution continues. SetLogger, SetStagnation, SetSpeciationPolicy, SetSpeciator,
+// SetReproductionStrategy, SetGenomeFactory, SetCurriculum, AddReporter, InjectGenome,
+// UpdateConfig, AddSchedule, ReindexSpecies and SaveCheckpoint must be called between generations
+// or from a hook.
+//
+// During evaluation the fitness function owns the genomes it is given and may process them in
+// parallel. It may set each genome's Fitness, Fitnesses and Behavior from any goroutine (one writer
+// per genome) and may read any genome of the generation concurrently: building networks (directly
+// or through a PhenotypeCache), Copy, DeepClone, Distance, GenomeJSON and the like only read the
+// genome. It must not add or remove entries of the genomes map.
+//
+// Genomes sharing a GenomeConfig may be mutated concurrently, as long as each genome is mutated by
+// one goroutine at a time: new node keys come from the config's InnovationTracker, which is locked.
+// The config itself is otherwise read-only while genomes use it. Elites, the best genome and the
+// hall of fame are copies, so no genome is shared between generations, and a mutated copy never
+// changes the genome it was copied from.
+//
+// Randomness comes from the math/rand top-level functions, which are safe for concurrent use; a
+// parallel fitness function therefore makes runs irreproducible even with a fixed seed unless it
+// uses its own per-genome sources. The registries (RegisterActivation, RegisterAggregation and
+// fitness scaling) are locked and may be extended at any time.
+//
+// # Errors
+//
+// Failures callers may want to handle are matched with errors.Is: ErrExtinction when every species
+// died out without reset_on_extinction, ErrConfigInvalid for invalid config values (errors.As with
+// a *ConfigError gives the offending key), ErrCycleDetected for a cycle in a feed-forward genome,
+// and ErrUnknownActivation or ErrUnknownAggregation for unregistered function names.
+package neat
@@ -0,0 +1,194 @@
+package neat
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Environment is an episodic control task, such as a Gym environment.
+type Environment interface {
+	// Reset starts a new episode with the given seed and returns the first observation.
+	Reset(seed int64) ([]float64, error)
+	// Step applies an action and returns the next observation, the reward for the step and
+	// whether the episode has ended.
+	Step(action []float64) (observation []float64, reward float64, done bool, err error)
+}
+
+// Policy maps an observation to an action, typically by activating a genome's network.
+type Policy func(observation []float64) ([]float64, error)
+
+// RunEpisode runs one episode of env with policy, stopping when the environment reports the
+// episode is done or after maxSteps steps (0 = no limit). It returns the total reward and the
+// number of steps taken.
+func RunEpisode(env Environment, policy Policy, seed int64, maxSteps int) (float64, int, error) {
+	observation, err := env.Reset(seed)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to reset environment: %w", err)
+	}
+	total := 0.0
+	steps := 0
+	for maxSteps <= 0 || steps < maxSteps {
+		action, err := policy(observation)
+		if err != nil {
+			return total, steps, fmt.Errorf("policy failed at step %d: %w", steps, err)
+		}
+		var reward float64
+		var done bool
+		observation, reward, done, err = env.Step(action)
+		if err != nil {
+			return total, steps, fmt.Errorf("environment step %d failed: %w", steps, err)
+		}
+		total += reward
+		steps++
+		if done {
+			break
+		}
+	}
+	return total, steps, nil
+}
+
+// EpisodeEvaluator scores each genome by running episodes of an environment with the genome's
+// policy, one episode per seed, and aggregating the episode rewards into the genome's fitness.
+// Fixed seeds give every genome the same starting conditions, which keeps fitness comparable
+// within and across generations. Episodes run one after another on the single Env, unless NewEnv
+// is set: FitnessFunc then evaluates up to Workers genomes at once, each worker stepping its own
+// environment; Policy must then be safe for concurrent use. The eval package provides network
+// policies (eval.NetworkPolicy).
+type EpisodeEvaluator struct {
+	Env         Environment
+	Policy      func(g *Genome) (Policy, error) // Builds the policy of a genome, e.g. from its network; called per episode so stateful policies start fresh
+	Seeds       []int64                         // One episode per seed (default a single episode with seed 0)
+	MaxSteps    int                             // Step limit per episode (0 = until the environment ends it)
+	Aggregation string                          // Name of the StatFunctions entry used to combine episode rewards (default "mean")
+	Steps       int                             // Total number of environment steps taken
+	NewEnv      func() (Environment, error)     // Creates one environment per worker for parallel evaluation (nil = sequential on Env)
+	Workers     int                             // Max genomes evaluated at once with NewEnv (0 = runtime.NumCPU())
+}
+
+// NewEpisodeEvaluator creates an evaluator running one episode per seed with mean aggregation.
+func NewEpisodeEvaluator(env Environment, policy func(g *Genome) (Policy, error), seeds []int64, maxSteps int) *EpisodeEvaluator {
+	return &EpisodeEvaluator{
+		Env:         env,
+		Policy:      policy,
+		Seeds:       seeds,
+		MaxSteps:    maxSteps,
+		Aggregation: "mean",
+	}
+}
+
+// EvaluateGenome runs the episodes for g on Env and returns the aggregated reward.
+// It does not modify g.Fitness.
+func (ee *EpisodeEvaluator) EvaluateGenome(g *Genome) (float64, error) {
+	fitness, steps, err := ee.evaluate(ee.Env, g)
+	ee.Steps += steps
+	return fitness, err
+}
+
+// evaluate runs the episodes for g on env, returning the aggregated reward and the steps taken.
+func (ee *EpisodeEvaluator) evaluate(env Environment, g *Genome) (float64, int, error) {
+	seeds := ee.Seeds
+	if len(seeds) == 0 {
+		seeds = []int64{0}
+	}
+	aggregation := ee.Aggregation
+	if aggregation == "" {
+		aggregation = "mean"
+	}
+	aggregate, ok := StatFunctions[aggregation]
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown episode aggregation function: %s", aggregation)
+	}
+	rewards := make([]float64, len(seeds))
+	totalSteps := 0
+	for i, seed := range seeds {
+		policy, err := ee.Policy(g)
+		if err != nil {
+			return 0, totalSteps, fmt.Errorf("failed to build policy for genome %d: %w", g.Key, err)
+		}
+		reward, steps, err := RunEpisode(env, policy, seed, ee.MaxSteps)
+		totalSteps += steps
+		if err != nil {
+			return 0, totalSteps, fmt.Errorf("episode with seed %d of genome %d failed: %w", seed, g.Key, err)
+		}
+		rewards[i] = reward
+	}
+	return aggregate(rewards), totalSteps, nil
+}
+
+// FitnessFunc returns a FitnessFunc that sets each genome's fitness to its aggregated episode reward.
+func (ee *EpisodeEvaluator) FitnessFunc() FitnessFunc {
+	return func(genomes map[int]*Genome) error {
+		keys := make([]int, 0, len(genomes))
+		for k := range genomes {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		if ee.NewEnv != nil {
+			return ee.evaluateParallel(genomes, keys)
+		}
+		for _, k := range keys {
+			fitness, err := ee.EvaluateGenome(genomes[k])
+			if err != nil {
+				return err
+			}
+			genomes[k].Fitness = fitness
+		}
+		return nil
+	}
+}
+
+// evaluateParallel evaluates the genomes with keys on up to Workers environments created by
+// NewEnv. The first error, in key order, is returned.
+func (ee *EpisodeEvaluator) evaluateParallel(genomes map[int]*Genome, keys []int) error {
+	workers := ee.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(keys))
+	envs := make([]Environment, workers)
+	for i := range envs {
+		env, err := ee.NewEnv()
+		if err != nil {
+			return fmt.Errorf("failed to create environment: %w", err)
+		}
+		envs[i] = env
+	}
+
+	next := make(chan int)
+	errs := make([]error, len(keys))
+	steps := make([]int, workers)
+	var wg sync.WaitGroup
+	for w, env := range envs {
+		wg.Add(1)
+		go func(w int, env Environment) {
+			defer wg.Done()
+			for i := range next {
+				g := genomes[keys[i]]
+				fitness, n, err := ee.evaluate(env, g)
+				steps[w] += n
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				g.Fitness = fitness
+			}
+		}(w, env)
+	}
+	for i := range keys {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for _, n := range steps {
+		ee.Steps += n
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package neat
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors callers can branch on with errors.Is. ErrExtinction (see ExtinctionError) and
+// ErrGenerationNotStored are declared next to the code returning them.
+var (
+	// ErrConfigInvalid matches every *ConfigError.
+	ErrConfigInvalid = errors.New("invalid config")
+	// ErrCycleDetected matches errors for a cycle in a genome that must be feed-forward.
+	ErrCycleDetected = errors.New("cycle detected")
+	// ErrUnknownActivation matches errors for an activation function name that isn't registered.
+	ErrUnknownActivation = errors.New("unknown activation function")
+	// ErrUnknownAggregation matches errors for an aggregation function name that isn't registered.
+	ErrUnknownAggregation = errors.New("unknown aggregation function")
+)
+
+// ConfigError reports an invalid config value: a bad entry in the config file, or in a schedule
+// or patch applied to a running population. It matches ErrConfigInvalid and, through Unwrap, the
+// cause when there is one (e.g. ErrUnknownActivation for an unknown activation_options entry).
+type ConfigError struct {
+	Field string // Config key the error is about, e.g. "conn_add_prob"
+	Err   error  // Description, wrapping the cause if any
+}
+
+func (e *ConfigError) Error() string {
+	return "config error: " + e.Err.Error()
+}
+
+func (e *ConfigError) Is(target error) bool {
+	return target == ErrConfigInvalid
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// configErrorf returns a *ConfigError about field, formatting the description like fmt.Errorf.
+func configErrorf(field string, format string, args ...any) *ConfigError {
+	return &ConfigError{Field: field, Err: fmt.Errorf(format, args...)}
+}
@@ -0,0 +1,33 @@
+package eval
+
+import (
+	"github.com/baldhumanity/neat-go/neat"
+	"github.com/baldhumanity/neat-go/neat/nn"
+)
+
+// NetworkPolicy builds a policy that activates g's network with each observation and uses the
+// outputs as the action: a feed-forward network if the genome config is feed-forward, a
+// recurrent network otherwise. Each call builds a fresh network, so a recurrent policy starts
+// every episode from a reset state. It is safe for concurrent use.
+func NetworkPolicy(g *neat.Genome) (neat.Policy, error) {
+	var net Activator
+	var err error
+	if g.Config.FeedForward {
+		net, err = nn.CreateFeedForwardNetwork(g)
+	} else {
+		net, err = nn.CreateRecurrentNetwork(g)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return net.Activate, nil
+}
+
+// NewEpisodeEvaluator creates a neat.EpisodeEvaluator whose genomes act through NetworkPolicy.
+// Pass newEnv to evaluate genomes in parallel, each worker on its own environment; with a nil
+// newEnv, env is used sequentially.
+func NewEpisodeEvaluator(env neat.Environment, newEnv func() (neat.Environment, error), seeds []int64, maxSteps int) *neat.EpisodeEvaluator {
+	ee := neat.NewEpisodeEvaluator(env, NetworkPolicy, seeds, maxSteps)
+	ee.NewEnv = newEnv
+	return ee
+}
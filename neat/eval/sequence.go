@@ -0,0 +1,190 @@
+package eval
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/baldhumanity/neat-go/neat"
+	"github.com/baldhumanity/neat-go/neat/nn"
+)
+
+// Sequence is one time series: Inputs[t] is fed to the network at step t and its outputs are
+// compared with Targets[t].
+type Sequence struct {
+	Inputs  [][]float64
+	Targets [][]float64
+}
+
+// StatefulActivator is a phenotype that carries state from one Activate call to the next, such as
+// nn.RecurrentNetwork. Reset clears that state.
+type StatefulActivator interface {
+	Activator
+	Reset()
+}
+
+// Feedback selects what, if anything, is fed back to the network as extra inputs at each step.
+type Feedback int
+
+const (
+	// NoFeedback feeds only the sequence inputs.
+	NoFeedback Feedback = iota
+	// TeacherForcing appends the previous step's targets to the inputs.
+	TeacherForcing
+	// FreeRunning appends the network's own previous outputs to the inputs, after the first
+	// ForcedSteps steps, which are teacher-forced.
+	FreeRunning
+)
+
+// SequenceEvaluator scores genomes on time series. Each sequence is fed step by step into a fresh
+// (reset) network, the loss is averaged over the steps after the washout period, and the
+// per-sequence errors are aggregated into the genome's fitness.
+//
+// With feedback the network receives len(Targets[t]) extra inputs after the sequence inputs, so
+// num_inputs must be their sum. The feedback at step 0 is all zeros.
+type SequenceEvaluator struct {
+	Sequences   []Sequence
+	Loss        LossFunc // Per-step loss (default MSE)
+	Washout     int      // Initial steps of every sequence that are run but not scored, letting the state settle
+	Feedback    Feedback
+	ForcedSteps int    // Teacher-forced steps at the start of every sequence in FreeRunning mode
+	Aggregation string // Name of the StatFunctions entry used to combine sequence errors (default "mean")
+	Workers     int    // Max genomes evaluated at once (0 = runtime.NumCPU())
+
+	// Network builds a genome's phenotype (default nn.CreateRecurrentNetwork).
+	Network func(g *neat.Genome) (StatefulActivator, error)
+	// Fitness converts the aggregated error into a fitness to maximize (default: the negated error).
+	Fitness func(err float64) float64
+}
+
+// NewSequenceEvaluator creates an evaluator with MSE loss, no washout or feedback, and mean aggregation.
+func NewSequenceEvaluator(sequences []Sequence) *SequenceEvaluator {
+	return &SequenceEvaluator{
+		Sequences:   sequences,
+		Loss:        MSE,
+		Aggregation: "mean",
+		Workers:     runtime.NumCPU(),
+	}
+}
+
+// SequenceError runs one sequence on net, which is reset first, and returns the mean loss over
+// the scored steps.
+func (se *SequenceEvaluator) SequenceError(net StatefulActivator, seq Sequence) (float64, error) {
+	if len(seq.Inputs) != len(seq.Targets) {
+		return 0, fmt.Errorf("sequence has %d input steps but %d target steps", len(seq.Inputs), len(seq.Targets))
+	}
+	if len(seq.Inputs) <= se.Washout {
+		return 0, fmt.Errorf("sequence of %d steps is not longer than the washout of %d steps", len(seq.Inputs), se.Washout)
+	}
+	loss := se.Loss
+	if loss == nil {
+		loss = MSE
+	}
+
+	net.Reset()
+	var feedback, inputs []float64
+	total := 0.0
+	for t, stepInputs := range seq.Inputs {
+		targets := seq.Targets[t]
+		if se.Feedback != NoFeedback {
+			if feedback == nil {
+				feedback = make([]float64, len(targets))
+			}
+			inputs = append(append(inputs[:0], stepInputs...), feedback...)
+		} else {
+			inputs = stepInputs
+		}
+		outputs, err := net.Activate(inputs)
+		if err != nil {
+			return 0, fmt.Errorf("activation at step %d failed: %w", t, err)
+		}
+		if len(outputs) != len(targets) {
+			return 0, fmt.Errorf("network has %d outputs but step %d has %d targets", len(outputs), t, len(targets))
+		}
+		if t >= se.Washout {
+			total += loss(outputs, targets)
+		}
+
+		switch {
+		case se.Feedback == TeacherForcing, se.Feedback == FreeRunning && t < se.ForcedSteps:
+			copy(feedback, targets)
+		case se.Feedback == FreeRunning:
+			copy(feedback, outputs)
+		}
+	}
+	return total / float64(len(seq.Inputs)-se.Washout), nil
+}
+
+// EvaluateGenome returns the aggregated error of g over all sequences. It does not modify g.Fitness.
+func (se *SequenceEvaluator) EvaluateGenome(g *neat.Genome) (float64, error) {
+	if len(se.Sequences) == 0 {
+		return 0, fmt.Errorf("sequence evaluation requires at least one sequence")
+	}
+	aggregation := se.Aggregation
+	if aggregation == "" {
+		aggregation = "mean"
+	}
+	aggregate, ok := neat.StatFunctions[aggregation]
+	if !ok {
+		return 0, fmt.Errorf("unknown sequence aggregation function: %s", aggregation)
+	}
+	network := se.Network
+	if network == nil {
+		network = func(g *neat.Genome) (StatefulActivator, error) { return nn.CreateRecurrentNetwork(g) }
+	}
+	net, err := network(g)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create network for genome %d: %w", g.Key, err)
+	}
+	errs := make([]float64, len(se.Sequences))
+	for i, seq := range se.Sequences {
+		if errs[i], err = se.SequenceError(net, seq); err != nil {
+			return 0, fmt.Errorf("sequence %d of genome %d: %w", i, g.Key, err)
+		}
+	}
+	return aggregate(errs), nil
+}
+
+// FitnessFunc returns a FitnessFunc that sets each genome's fitness from its aggregated sequence
+// error, evaluating up to Workers genomes concurrently.
+func (se *SequenceEvaluator) FitnessFunc() neat.FitnessFunc {
+	return func(genomes map[int]*neat.Genome) error {
+		fitness := se.Fitness
+		if fitness == nil {
+			fitness = func(err float64) float64 { return -err }
+		}
+		workers := se.Workers
+		if workers <= 0 {
+			workers = runtime.NumCPU()
+		}
+
+		keys := make([]int, 0, len(genomes))
+		for k := range genomes {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		results := make([]float64, len(keys))
+		errs := make([]error, len(keys))
+		semaphore := make(chan struct{}, workers)
+		var wg sync.WaitGroup
+		for i, k := range keys {
+			semaphore <- struct{}{}
+			wg.Add(1)
+			go func(i int, g *neat.Genome) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+				results[i], errs[i] = se.EvaluateGenome(g)
+			}(i, genomes[k])
+		}
+		wg.Wait()
+
+		for i, k := range keys {
+			if errs[i] != nil {
+				return errs[i]
+			}
+			genomes[k].Fitness = fitness(results[i])
+		}
+		return nil
+	}
+}
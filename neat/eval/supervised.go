@@ -0,0 +1,197 @@
+// Package eval provides ready-made fitness functions for common evaluation setups.
+package eval
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/baldhumanity/neat-go/neat"
+	"github.com/baldhumanity/neat-go/neat/nn"
+)
+
+// LossFunc measures how far a network's outputs are from the targets for one sample.
+type LossFunc func(outputs, targets []float64) float64
+
+// MSE is the mean squared error over the outputs.
+func MSE(outputs, targets []float64) float64 {
+	sum := 0.0
+	for i, t := range targets {
+		d := outputs[i] - t
+		sum += d * d
+	}
+	return sum / float64(len(targets))
+}
+
+// probabilityEpsilon keeps log() finite for outputs of exactly 0 or 1.
+const probabilityEpsilon = 1e-12
+
+// BinaryCrossEntropy treats every output as an independent probability (e.g. from a sigmoid
+// output node) and returns the mean binary cross-entropy against 0/1 targets.
+func BinaryCrossEntropy(outputs, targets []float64) float64 {
+	sum := 0.0
+	for i, t := range targets {
+		p := math.Min(math.Max(outputs[i], probabilityEpsilon), 1-probabilityEpsilon)
+		sum -= t*math.Log(p) + (1-t)*math.Log(1-p)
+	}
+	return sum / float64(len(targets))
+}
+
+// SoftmaxCrossEntropy applies a softmax to the outputs and returns the cross-entropy against a
+// target distribution, typically one-hot class labels.
+func SoftmaxCrossEntropy(outputs, targets []float64) float64 {
+	maxOutput := math.Inf(-1)
+	for _, o := range outputs {
+		maxOutput = math.Max(maxOutput, o)
+	}
+	sumExp := 0.0
+	for _, o := range outputs {
+		sumExp += math.Exp(o - maxOutput)
+	}
+	logSumExp := maxOutput + math.Log(sumExp)
+	loss := 0.0
+	for i, t := range targets {
+		loss -= t * (outputs[i] - logSumExp)
+	}
+	return loss
+}
+
+// Losses maps names to the built-in loss functions.
+var Losses = map[string]LossFunc{
+	"mse":                   MSE,
+	"binary_cross_entropy":  BinaryCrossEntropy,
+	"softmax_cross_entropy": SoftmaxCrossEntropy,
+}
+
+// Activator is a network phenotype that maps inputs to outputs.
+type Activator interface {
+	Activate(inputs []float64) ([]float64, error)
+}
+
+// Supervised evaluates genomes on a labelled dataset: each genome's network is run on every
+// sample (or on a mini-batch of samples) and its fitness is derived from the mean loss.
+type Supervised struct {
+	Inputs  [][]float64 // One input vector per sample
+	Targets [][]float64 // One target vector per sample, as long as the network's outputs
+	Loss    LossFunc    // Per-sample loss (default MSE)
+
+	// BatchSize > 0 evaluates a random mini-batch of that many samples per generation instead of
+	// the whole dataset. All genomes of a generation see the same batch, so their fitness stays
+	// comparable; use the full dataset when fitness must be exactly reproducible.
+	BatchSize int
+	Workers   int // Max genomes evaluated at once (0 = runtime.NumCPU())
+
+	// Network builds a genome's phenotype (default nn.CreateFeedForwardNetwork).
+	Network func(g *neat.Genome) (Activator, error)
+	// Fitness converts the mean loss into a fitness to maximize (default: the negated loss, so a
+	// perfect genome has fitness 0 and fitness_threshold is a small negative number).
+	Fitness func(loss float64) float64
+
+	Rand *rand.Rand // Source for mini-batch sampling (nil = the global math/rand source)
+}
+
+// NewSupervised creates an evaluator of the whole dataset with the given loss, using all CPUs.
+func NewSupervised(inputs, targets [][]float64, loss LossFunc) *Supervised {
+	return &Supervised{Inputs: inputs, Targets: targets, Loss: loss, Workers: runtime.NumCPU()}
+}
+
+// MeanLoss returns the mean loss of g over the given samples (all samples if indices is nil).
+func (s *Supervised) MeanLoss(g *neat.Genome, indices []int) (float64, error) {
+	network := s.Network
+	if network == nil {
+		network = func(g *neat.Genome) (Activator, error) { return nn.CreateFeedForwardNetwork(g) }
+	}
+	loss := s.Loss
+	if loss == nil {
+		loss = MSE
+	}
+	net, err := network(g)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create network for genome %d: %w", g.Key, err)
+	}
+	if indices == nil {
+		indices = make([]int, len(s.Inputs))
+		for i := range indices {
+			indices[i] = i
+		}
+	}
+	if len(indices) == 0 {
+		return 0, fmt.Errorf("supervised evaluation requires at least one sample")
+	}
+	total := 0.0
+	for _, i := range indices {
+		outputs, err := net.Activate(s.Inputs[i])
+		if err != nil {
+			return 0, fmt.Errorf("activation of genome %d on sample %d failed: %w", g.Key, i, err)
+		}
+		if len(outputs) != len(s.Targets[i]) {
+			return 0, fmt.Errorf("genome %d has %d outputs but sample %d has %d targets", g.Key, len(outputs), i, len(s.Targets[i]))
+		}
+		total += loss(outputs, s.Targets[i])
+	}
+	return total / float64(len(indices)), nil
+}
+
+// batch returns the sample indices evaluated this generation, or nil for the whole dataset.
+func (s *Supervised) batch() []int {
+	if s.BatchSize <= 0 || s.BatchSize >= len(s.Inputs) {
+		return nil
+	}
+	perm := rand.Perm
+	if s.Rand != nil {
+		perm = s.Rand.Perm
+	}
+	indices := perm(len(s.Inputs))[:s.BatchSize]
+	sort.Ints(indices) // Same summation order for every genome
+	return indices
+}
+
+// FitnessFunc returns a FitnessFunc that sets each genome's fitness from its mean loss,
+// evaluating up to Workers genomes concurrently.
+func (s *Supervised) FitnessFunc() neat.FitnessFunc {
+	return func(genomes map[int]*neat.Genome) error {
+		if len(s.Inputs) != len(s.Targets) {
+			return fmt.Errorf("dataset has %d inputs but %d targets", len(s.Inputs), len(s.Targets))
+		}
+		fitness := s.Fitness
+		if fitness == nil {
+			fitness = func(loss float64) float64 { return -loss }
+		}
+		workers := s.Workers
+		if workers <= 0 {
+			workers = runtime.NumCPU()
+		}
+		indices := s.batch()
+
+		keys := make([]int, 0, len(genomes))
+		for k := range genomes {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		losses := make([]float64, len(keys))
+		errs := make([]error, len(keys))
+		semaphore := make(chan struct{}, workers)
+		var wg sync.WaitGroup
+		for i, k := range keys {
+			semaphore <- struct{}{}
+			wg.Add(1)
+			go func(i int, g *neat.Genome) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+				losses[i], errs[i] = s.MeanLoss(g, indices)
+			}(i, genomes[k])
+		}
+		wg.Wait()
+
+		for i, k := range keys {
+			if errs[i] != nil {
+				return errs[i]
+			}
+			genomes[k].Fitness = fitness(losses[i])
+		}
+		return nil
+	}
+}
@@ -0,0 +1,103 @@
+package neat
+
+import "container/list"
+
+// EvaluationCache remembers the fitness of recently evaluated genomes, keyed by a hash of all
+// their genes and attribute values, so identical genomes (elites, or offspring that happened not
+// to mutate) are not evaluated again. It holds at most Size entries, evicting the least recently
+// used. Genomes with an extension that isn't a HashableExtension bypass the cache. Only use it with
+// deterministic fitness functions.
+type EvaluationCache struct {
+	Size   int
+	Hits   int // Genomes whose fitness was taken from the cache
+	Misses int // Genomes that had to be evaluated
+
+	entries map[string]*list.Element
+	order   *list.List // Front = most recently used
+}
+
+type cachedEvaluation struct {
+	hash      string
+	fitness   float64
+	fitnesses []float64
+}
+
+// NewEvaluationCache creates a cache holding up to size evaluations.
+func NewEvaluationCache(size int) *EvaluationCache {
+	return &EvaluationCache{Size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// Len returns the number of cached evaluations.
+func (c *EvaluationCache) Len() int {
+	return c.order.Len()
+}
+
+// Clear removes all cached evaluations, e.g. after the fitness function changed.
+func (c *EvaluationCache) Clear() {
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// split separates genomes with a cached evaluation, which get their Fitness and Fitnesses
+// restored, from those that still need evaluating. It returns the latter and the hashes of all
+// hashable genomes.
+func (c *EvaluationCache) split(genomes map[int]*Genome) (map[int]*Genome, map[int]string) {
+	pending := make(map[int]*Genome, len(genomes))
+	hashes := make(map[int]string, len(genomes))
+	for key, g := range genomes {
+		hash, hashable := genomeContentHash(g)
+		if !hashable {
+			pending[key] = g
+			c.Misses++
+			continue
+		}
+		hashes[key] = hash
+		elem, ok := c.entries[hash]
+		if !ok {
+			pending[key] = g
+			c.Misses++
+			continue
+		}
+		c.order.MoveToFront(elem)
+		entry := elem.Value.(*cachedEvaluation)
+		g.Fitness = entry.fitness
+		g.Fitnesses = append([]float64(nil), entry.fitnesses...)
+		c.Hits++
+	}
+	return pending, hashes
+}
+
+// store caches the evaluation results of the genomes with a hash.
+func (c *EvaluationCache) store(genomes map[int]*Genome, hashes map[int]string) {
+	for key, g := range genomes {
+		hash, ok := hashes[key]
+		if !ok {
+			continue
+		}
+		entry := &cachedEvaluation{hash: hash, fitness: g.Fitness, fitnesses: append([]float64(nil), g.Fitnesses...)}
+		if elem, ok := c.entries[hash]; ok {
+			elem.Value = entry
+			c.order.MoveToFront(elem)
+			continue
+		}
+		c.entries[hash] = c.order.PushFront(entry)
+		for c.order.Len() > c.Size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cachedEvaluation).hash)
+		}
+	}
+}
+
+// evaluate runs fitnessFunc on the genomes without a cached evaluation and caches the results.
+func (c *EvaluationCache) evaluate(genomes map[int]*Genome, fitnessFunc FitnessFunc) error {
+	pending, hashes := c.split(genomes)
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := fitnessFunc(pending); err != nil {
+		return err
+	}
+	c.store(pending, hashes)
+	return nil
+}
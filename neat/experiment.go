@@ -0,0 +1,254 @@
+package neat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Experiment repeats runs of one configuration with different seeds and aggregates their
+// statistics, for fair comparisons between configurations or operators. Run i sets the config's
+// seed to Seed+i. Runs execute in parallel and share Fitness, which must therefore be safe for
+// concurrent use. The seed drives the per-species random streams of reproduction; other random
+// choices draw from the shared math/rand source, so runs are not exactly reproducible.
+type Experiment struct {
+	Name        string                    // Label used in reports and comparisons (default: the config path)
+	ConfigPath  string                    // Config file loaded afresh for every run
+	Fitness     FitnessFunc               // Fitness function shared by all runs
+	NewFitness  func() FitnessFunc        // Creates a fitness function per run, for stateful evaluators (used instead of Fitness when set)
+	Runs        int                       // Number of repeated runs
+	Generations int                       // Max generations per run; a run stops early once it reaches the fitness threshold
+	Seed        int64                     // Seed of the first run (0 = 1)
+	Workers     int                       // Max runs at once (0 = runtime.NumCPU())
+	Logger      Logger                    // Logger for the runs (nil = discard their output)
+	Setup       func(p *Population) error // Optional hook called on each new population before it runs, e.g. to add reporters
+}
+
+// ExperimentRun is the outcome of one run of an experiment.
+type ExperimentRun struct {
+	Index       int
+	Seed        int64
+	Generations int         // Generations run
+	Solved      int         // Generation in which the fitness threshold was reached (0 = not reached)
+	Extinct     bool        // The run ended early because every species died out
+	BestFitness float64     // Best fitness of any generation
+	Statistics  *Statistics // Per-generation statistics of the run
+}
+
+// ExperimentReport aggregates the runs of an experiment. Per-generation aggregates cover every
+// run: runs that stopped early contribute their final best fitness to later generations.
+type ExperimentReport struct {
+	Name                     string
+	ConfigPath               string
+	Runs                     []ExperimentRun
+	MeanBestFitness          []float64 // Mean over runs of the best fitness so far, per generation (index 0 = generation 1)
+	StdevBestFitness         []float64 // Standard deviation of the same, per generation
+	SuccessRate              float64   // Fraction of runs that reached the fitness threshold
+	MeanGenerationsToSolve   float64   // Mean generation of solution over the successful runs (0 if none)
+	MedianGenerationsToSolve float64   // Median of the same
+}
+
+// NewExperiment creates an experiment of runs runs of at most generations generations each.
+func NewExperiment(configPath string, fitness FitnessFunc, runs, generations int) *Experiment {
+	return &Experiment{
+		ConfigPath:  configPath,
+		Fitness:     fitness,
+		Runs:        runs,
+		Generations: generations,
+		Workers:     runtime.NumCPU(),
+	}
+}
+
+// Run executes the runs and aggregates them into a report.
+func (e *Experiment) Run() (*ExperimentReport, error) {
+	if e.Fitness == nil && e.NewFitness == nil {
+		return nil, fmt.Errorf("experiment requires a fitness function")
+	}
+	if e.Runs <= 0 || e.Generations <= 0 {
+		return nil, fmt.Errorf("experiment requires a positive number of runs and generations")
+	}
+	seed := e.Seed
+	if seed == 0 {
+		seed = 1 // Seed 0 means unseeded in the config
+	}
+	workers := e.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	runs := make([]ExperimentRun, e.Runs)
+	errs := make([]error, e.Runs)
+	semaphore := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := range runs {
+		runs[i] = ExperimentRun{Index: i, Seed: seed + int64(i)}
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(run *ExperimentRun) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			errs[run.Index] = e.run(run)
+		}(&runs[i])
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("experiment run %d failed: %w", i, err)
+		}
+	}
+
+	name := e.Name
+	if name == "" {
+		name = e.ConfigPath
+	}
+	return newExperimentReport(name, e.ConfigPath, runs, e.Generations), nil
+}
+
+// run executes one run of the experiment.
+func (e *Experiment) run(run *ExperimentRun) error {
+	config, err := LoadConfig(e.ConfigPath)
+	if err != nil {
+		return err
+	}
+	config.Neat.Seed = run.Seed
+	p, err := NewPopulation(config)
+	if err != nil {
+		return err
+	}
+	logger := e.Logger
+	if logger == nil {
+		logger = NewConsoleLogger(io.Discard, slog.LevelError)
+	}
+	p.SetLogger(logger)
+	p.Statistics = NewStatistics()
+	if e.Setup != nil {
+		if err := e.Setup(p); err != nil {
+			return err
+		}
+	}
+	run.Statistics = p.Statistics
+	fitness := e.Fitness
+	if e.NewFitness != nil {
+		fitness = e.NewFitness()
+	}
+	for p.Generation < e.Generations {
+		winner, err := p.RunGeneration(fitness)
+		if errors.Is(err, ErrExtinction) {
+			run.Extinct = true
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if winner != nil {
+			run.Solved = p.Generation
+			break
+		}
+	}
+	run.Generations = p.Generation
+	run.BestFitness = math.Inf(-1)
+	for _, gs := range p.Statistics.Generations {
+		run.BestFitness = math.Max(run.BestFitness, gs.BestFitness)
+	}
+	return nil
+}
+
+// newExperimentReport aggregates runs over the given number of generations.
+func newExperimentReport(name, configPath string, runs []ExperimentRun, generations int) *ExperimentReport {
+	r := &ExperimentReport{
+		Name:             name,
+		ConfigPath:       configPath,
+		Runs:             runs,
+		MeanBestFitness:  make([]float64, generations),
+		StdevBestFitness: make([]float64, generations),
+	}
+	// Best fitness so far of every run at every generation, carried forward after a run stops.
+	curves := make([][]float64, len(runs))
+	for i, run := range runs {
+		curves[i] = make([]float64, generations)
+		best := math.Inf(-1)
+		for gen := 0; gen < generations; gen++ {
+			if gen < len(run.Statistics.Generations) {
+				best = math.Max(best, run.Statistics.Generations[gen].BestFitness)
+			}
+			curves[i][gen] = best
+		}
+	}
+	column := make([]float64, len(runs))
+	for gen := 0; gen < generations; gen++ {
+		for i := range runs {
+			column[i] = curves[i][gen]
+		}
+		r.MeanBestFitness[gen] = Mean(column)
+		r.StdevBestFitness[gen] = Stdev(column)
+	}
+
+	var solvedAt []float64
+	for _, run := range runs {
+		if run.Solved > 0 {
+			solvedAt = append(solvedAt, float64(run.Solved))
+		}
+	}
+	if len(runs) > 0 {
+		r.SuccessRate = float64(len(solvedAt)) / float64(len(runs))
+	}
+	if len(solvedAt) > 0 {
+		r.MeanGenerationsToSolve = Mean(solvedAt)
+		r.MedianGenerationsToSolve = Median(solvedAt)
+	}
+	return r
+}
+
+// WriteJSON saves the report, including every run's statistics, as indented JSON.
+func (r *ExperimentReport) WriteJSON(filePath string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode experiment report: %w", err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write experiment report '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// LoadExperimentReport reads a report saved with WriteJSON.
+func LoadExperimentReport(filePath string) (*ExperimentReport, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read experiment report '%s': %w", filePath, err)
+	}
+	var r ExperimentReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode experiment report '%s': %w", filePath, err)
+	}
+	return &r, nil
+}
+
+// CompareExperiments renders a Markdown table comparing experiment reports side by side: runs,
+// success rate, generations to solution and final mean best fitness.
+func CompareExperiments(reports ...*ExperimentReport) string {
+	var b strings.Builder
+	b.WriteString("| Experiment | Runs | Success rate | Mean gens to solve | Median gens to solve | Final mean best fitness |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, r := range reports {
+		final, stdev := math.NaN(), math.NaN()
+		if n := len(r.MeanBestFitness); n > 0 {
+			final, stdev = r.MeanBestFitness[n-1], r.StdevBestFitness[n-1]
+		}
+		solve, median := "-", "-"
+		if r.SuccessRate > 0 {
+			solve = fmt.Sprintf("%.1f", r.MeanGenerationsToSolve)
+			median = fmt.Sprintf("%.1f", r.MedianGenerationsToSolve)
+		}
+		fmt.Fprintf(&b, "| %s | %d | %.0f%% | %s | %s | %.4f ± %.4f |\n",
+			r.Name, len(r.Runs), 100*r.SuccessRate, solve, median, final, stdev)
+	}
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package neat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// ExperimentalConfig holds opt-in flags for in-development behaviors, read from the optional
+// [Experimental] config section. Every flag defaults to false, so existing configs keep producing
+// bit-identical runs; a flag may become the default once its behavior has proven itself.
+type ExperimentalConfig struct {
+	NewSpawnAllocation  bool `ini:"new_spawn_allocation"` // Largest-remainder spawn allocation that hits pop_size exactly without random corrections
+	StickySpeciation    bool `ini:"sticky_speciation"`    // Offspring stay in their first parent's species while within the compatibility threshold
+	InnovationReuse     bool `ini:"innovation_reuse"`     // Splitting the same connection in one generation yields the same new node key
+	StructuralAlignment bool `ini:"structural_alignment"` // Crossover matches hidden nodes created by splitting the same connection, whatever their keys
+}
+
+// ExperimentalFeature describes one flag of the [Experimental] config section.
+type ExperimentalFeature struct {
+	Name        string // Config key
+	Description string
+	flag        func(c *ExperimentalConfig) *bool
+}
+
+// experimentalFeatures is the registry of known experimental flags.
+var experimentalFeatures = []ExperimentalFeature{
+	{
+		Name:        "new_spawn_allocation",
+		Description: "Allocate offspring to species by largest remainder so totals match pop_size exactly and deterministically.",
+		flag:        func(c *ExperimentalConfig) *bool { return &c.NewSpawnAllocation },
+	},
+	{
+		Name:        "sticky_speciation",
+		Description: "Keep offspring in their first parent's species when still compatible with its representative.",
+		flag:        func(c *ExperimentalConfig) *bool { return &c.StickySpeciation },
+	},
+	{
+		Name:        "innovation_reuse",
+		Description: "Give identical add-node mutations within a generation the same node key.",
+		flag:        func(c *ExperimentalConfig) *bool { return &c.InnovationReuse },
+	},
+	{
+		Name:        "structural_alignment",
+		Description: "Align hidden nodes created by the same connection split under different keys during crossover.",
+		flag:        func(c *ExperimentalConfig) *bool { return &c.StructuralAlignment },
+	},
+}
+
+// ExperimentalFeatures returns the registry of experimental flags, sorted by name.
+func ExperimentalFeatures() []ExperimentalFeature {
+	features := append([]ExperimentalFeature(nil), experimentalFeatures...)
+	sort.Slice(features, func(i, j int) bool { return features[i].Name < features[j].Name })
+	return features
+}
+
+// Enabled returns the names of the enabled experimental flags, sorted by name.
+func (c *ExperimentalConfig) Enabled() []string {
+	enabled := []string{}
+	for _, f := range ExperimentalFeatures() {
+		if *f.flag(c) {
+			enabled = append(enabled, f.Name)
+		}
+	}
+	return enabled
+}
+
+// loadExperimentalConfig reads the [Experimental] section. Unknown keys are rejected so a
+// misspelled flag doesn't silently run the stable behavior.
+func loadExperimentalConfig(section *ini.Section, config *ExperimentalConfig) error {
+	for _, key := range section.Keys() {
+		var feature *ExperimentalFeature
+		for i := range experimentalFeatures {
+			if experimentalFeatures[i].Name == key.Name() {
+				feature = &experimentalFeatures[i]
+				break
+			}
+		}
+		if feature == nil {
+			names := make([]string, len(experimentalFeatures))
+			for i, f := range ExperimentalFeatures() {
+				names[i] = f.Name
+			}
+			return fmt.Errorf("unknown experimental flag '%s' (known: %s)", key.Name(), strings.Join(names, ", "))
+		}
+		value, err := key.Bool()
+		if err != nil {
+			return fmt.Errorf("invalid value for experimental flag '%s': %w", key.Name(), err)
+		}
+		*feature.flag(config) = value
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package neat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrExtinction matches (with errors.Is) the error returned when every species died out and
+// reset_on_extinction is disabled.
+var ErrExtinction = errors.New("all species became extinct")
+
+// ExtinctionError reports complete extinction in a generation. It matches ErrExtinction.
+type ExtinctionError struct {
+	Generation int
+}
+
+func (e *ExtinctionError) Error() string {
+	return fmt.Sprintf("all species became extinct in generation %d", e.Generation)
+}
+
+func (e *ExtinctionError) Is(target error) bool {
+	return target == ErrExtinction
+}
+
+// handleExtinction is the single place complete extinction is dealt with. Reporters are notified;
+// then, with reset_on_extinction, the species are cleared and a new population is returned, partly
+// bred from the population's best genomes when extinction_reseed_fraction > 0. Otherwise an
+// *ExtinctionError is returned.
+func (r *Reproduction) handleExtinction(config *Config, speciesSet *SpeciesSet, popSize int, generation int) (map[int]*Genome, error) {
+	logger := loggerOrDefault(r.logger)
+	logger.Warn("All species became extinct", "generation", generation)
+	r.Reporters.CompleteExtinction()
+	if !config.Neat.ResetOnExtinction {
+		return nil, &ExtinctionError{Generation: generation}
+	}
+
+	fraction := config.Neat.ExtinctionReseedFraction
+	if fraction > 0 && len(r.reseedFrom) > 0 {
+		logger.Info("Reseeding population due to extinction", "seeds", len(r.reseedFrom), "fraction", fraction)
+	} else {
+		logger.Info("Resetting population due to extinction")
+	}
+	// Species keys keep counting up, so new species never reuse keys of the extinct ones.
+	speciesSet.Species = make(map[int]*Species)
+	speciesSet.GenomeToSpecies = make(map[int]int)
+	r.Ancestors = make(map[int][]int)
+	r.AncestorBorn = make(map[int]int)
+	return r.ReseedPopulation(&config.Genome, popSize, r.reseedFrom, fraction), nil
+}
+
+// updateHallOfFame merges the evaluated population into the hall of fame, keeping the
+// hall_of_fame_size fittest distinct genomes seen so far (ties broken by lower key).
+func (p *Population) updateHallOfFame() {
+	size := p.Config.Neat.HallOfFameSize
+	if size <= 0 {
+		return
+	}
+	inHall := make(map[int]bool, len(p.HallOfFame))
+	for _, g := range p.HallOfFame {
+		inHall[g.Key] = true
+	}
+	candidates := append([]*Genome(nil), p.HallOfFame...)
+	for _, g := range p.Population {
+		if !inHall[g.Key] {
+			candidates = append(candidates, g)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Fitness != candidates[j].Fitness {
+			return candidates[i].Fitness > candidates[j].Fitness
+		}
+		return candidates[i].Key < candidates[j].Key
+	})
+	if len(candidates) > size {
+		candidates = candidates[:size]
+	}
+	// Snapshot new entries so later mutation of the population can't change them.
+	for i, g := range candidates {
+		if !inHall[g.Key] {
+			candidates[i] = g.Copy()
+		}
+	}
+	p.HallOfFame = candidates
+}
+
+// extinctionSeeds returns the genomes a reset population is bred from: the best genome
+// followed by the hall of fame, without duplicates.
+func (p *Population) extinctionSeeds() []*Genome {
+	seeds := []*Genome{}
+	seen := make(map[int]bool)
+	if p.BestGenome != nil {
+		seeds = append(seeds, p.BestGenome)
+		seen[p.BestGenome.Key] = true
+	}
+	for _, g := range p.HallOfFame {
+		if !seen[g.Key] {
+			seeds = append(seeds, g)
+			seen[g.Key] = true
+		}
+	}
+	return seeds
+}
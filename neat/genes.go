@@ -29,17 +29,31 @@ type NodeGene struct {
 	Response    float64
 	Activation  string // Name of the activation function
 	Aggregation string // Name of the aggregation function
+
+	ActivationParam float64 // Scales the activation function's shape parameter (1.0 = standard; evolved with evolve_activation_param)
 }
 
 // NewNodeGene creates a new NodeGene with attributes initialized according to the config.
 func NewNodeGene(key int, config *GenomeConfig) *NodeGene {
-	ng := &NodeGene{
+	ng := allocNodeGene()
+	*ng = NodeGene{
 		Key:         key,
-		Activation:  initStringAttribute(config.ActivationDefault, config.ActivationOptions),
-		Aggregation: initStringAttribute(config.AggregationDefault, config.AggregationOptions),
+		Activation:  initStringAttribute(config.ActivationDefault, config.ActivationOptions, config.log()),
+		Aggregation: initStringAttribute(config.AggregationDefault, config.AggregationOptions, config.log()),
 	}
 	ng.Bias = initFloatAttribute(config.BiasInitMean, config.BiasInitStdev, config.BiasInitType, config.BiasMinValue, config.BiasMaxValue)
 	ng.Response = initFloatAttribute(config.ResponseInitMean, config.ResponseInitStdev, config.ResponseInitType, config.ResponseMinValue, config.ResponseMaxValue)
+	if config.BiasInput {
+		ng.Bias = 0 // Bias comes from connections to the bias input
+	}
+	ng.ActivationParam = 1.0
+	if config.EvolveActivationParam {
+		ng.ActivationParam = initFloatAttribute(config.ActivationParamInitMean, config.ActivationParamInitStdev, config.ActivationParamInitType, config.ActivationParamMinValue, config.ActivationParamMaxValue)
+	}
+	if config.hasFrozenOutputs() && config.IsOutputKey(key) {
+		ng.Activation = config.OutputActivation
+		ng.Response = 1.0
+	}
 	return ng
 }
 
@@ -51,20 +65,24 @@ func (ng *NodeGene) String() string {
 
 // Copy creates a deep copy of the NodeGene.
 func (ng *NodeGene) Copy() *NodeGene {
-	return &NodeGene{
-		Key:         ng.Key,
-		Bias:        ng.Bias,
-		Response:    ng.Response,
-		Activation:  ng.Activation,
-		Aggregation: ng.Aggregation,
-	}
+	c := allocNodeGene()
+	*c = *ng
+	return c
 }
 
 // Mutate adjusts the attributes of the NodeGene based on mutation rates in the config.
+// Output nodes keep their fixed activation and response when output_activation is set.
 func (ng *NodeGene) Mutate(config *GenomeConfig) {
-	ng.Bias = mutateFloatAttribute(ng.Bias, config.BiasMutateRate, config.BiasReplaceRate, config.BiasMutatePower, config.BiasInitMean, config.BiasInitStdev, config.BiasInitType, config.BiasMinValue, config.BiasMaxValue)
-	ng.Response = mutateFloatAttribute(ng.Response, config.ResponseMutateRate, config.ResponseReplaceRate, config.ResponseMutatePower, config.ResponseInitMean, config.ResponseInitStdev, config.ResponseInitType, config.ResponseMinValue, config.ResponseMaxValue)
-	ng.Activation = mutateStringAttribute(ng.Activation, config.ActivationMutateRate, config.ActivationOptions)
+	if !config.BiasInput {
+		ng.Bias = mutateFloatAttribute(ng.Bias, config.BiasMutateRate, config.BiasReplaceRate, config.BiasMutatePower, config.BiasInitMean, config.BiasInitStdev, config.BiasInitType, config.BiasMinValue, config.BiasMaxValue)
+	}
+	if !(config.hasFrozenOutputs() && config.IsOutputKey(ng.Key)) {
+		ng.Response = mutateFloatAttribute(ng.Response, config.ResponseMutateRate, config.ResponseReplaceRate, config.ResponseMutatePower, config.ResponseInitMean, config.ResponseInitStdev, config.ResponseInitType, config.ResponseMinValue, config.ResponseMaxValue)
+		ng.Activation = mutateStringAttribute(ng.Activation, config.ActivationMutateRate, config.ActivationOptions)
+	}
+	if config.EvolveActivationParam {
+		ng.ActivationParam = mutateFloatAttribute(ng.ActivationParam, config.ActivationParamMutateRate, config.ActivationParamReplaceRate, config.ActivationParamMutatePower, config.ActivationParamInitMean, config.ActivationParamInitStdev, config.ActivationParamInitType, config.ActivationParamMinValue, config.ActivationParamMaxValue)
+	}
 	ng.Aggregation = mutateStringAttribute(ng.Aggregation, config.AggregationMutateRate, config.AggregationOptions)
 }
 
@@ -77,11 +95,16 @@ func (ng *NodeGene) Distance(other *NodeGene, config *GenomeConfig) float64 {
 	if ng.Aggregation != other.Aggregation {
 		d += 1.0
 	}
+	if config.EvolveActivationParam {
+		d += math.Abs(ng.ActivationParam - other.ActivationParam)
+	}
 	return d * config.CompatibilityWeightCoefficient // Using the same coefficient as weights for now
 }
 
 // Crossover creates a new NodeGene by randomly inheriting attributes from two parent NodeGenes.
-func (ng *NodeGene) Crossover(other *NodeGene) *NodeGene {
+// The activation parameter is only drawn with evolve_activation_param, so configs without it use
+// the same random numbers as before it existed.
+func (ng *NodeGene) Crossover(other *NodeGene, config *GenomeConfig) *NodeGene {
 	// Assume ng is the primary parent (e.g., the more fit one if applicable)
 	child := ng.Copy() // Start with a copy of the primary parent
 
@@ -97,6 +120,9 @@ func (ng *NodeGene) Crossover(other *NodeGene) *NodeGene {
 	if rand.Float64() < 0.5 {
 		child.Aggregation = other.Aggregation
 	}
+	if config.EvolveActivationParam && rand.Float64() < 0.5 {
+		child.ActivationParam = other.ActivationParam
+	}
 
 	return child
 }
@@ -106,9 +132,13 @@ func (ng *NodeGene) Crossover(other *NodeGene) *NodeGene {
 // ConnectionGene represents a connection between two nodes in the genome.
 // The Key is a tuple (in Python), represented here as ConnectionKey struct.
 type ConnectionGene struct {
-	Key     ConnectionKey // Represents the (in_node_id, out_node_id) tuple
-	Weight  float64
-	Enabled bool
+	Key        ConnectionKey // Represents the (in_node_id, out_node_id) tuple
+	Weight     float64
+	Enabled    bool
+	Expression float64 // Probability the connection is expressed in the phenotype (1.0 unless connection_expression is enabled)
+	// DisabledFor counts the consecutive generations the connection has been disabled for, carried
+	// over by elites and inherited by offspring, for disabled_purge_generations; it is 0 while enabled.
+	DisabledFor int
 	// InnovationNumber is handled implicitly by using the Key (ConnectionKey) as the map key in Genome.
 }
 
@@ -120,11 +150,19 @@ type ConnectionKey struct {
 
 // NewConnectionGene creates a new ConnectionGene with attributes initialized according to the config.
 func NewConnectionGene(key ConnectionKey, config *GenomeConfig) *ConnectionGene {
-	cg := &ConnectionGene{
+	cg := allocConnectionGene()
+	*cg = ConnectionGene{
 		Key:     key,
 		Enabled: initBoolAttribute(config.EnabledDefault),
 	}
-	cg.Weight = initFloatAttribute(config.WeightInitMean, config.WeightInitStdev, config.WeightInitType, config.WeightMinValue, config.WeightMaxValue)
+	cg.Weight = 1.0 // Weight-agnostic genomes keep unit weights; the shared value is applied at evaluation.
+	if !config.WeightAgnostic {
+		cg.Weight = initFloatAttribute(config.WeightInitMean, config.WeightInitStdev, config.WeightInitType, config.WeightMinValue, config.WeightMaxValue)
+	}
+	cg.Expression = 1.0
+	if config.ConnectionExpression {
+		cg.Expression = initFloatAttribute(config.ExpressionInitMean, config.ExpressionInitStdev, config.ExpressionInitType, config.ExpressionMinValue, config.ExpressionMaxValue)
+	}
 	return cg
 }
 
@@ -136,32 +174,48 @@ func (cg *ConnectionGene) String() string {
 
 // Copy creates a deep copy of the ConnectionGene.
 func (cg *ConnectionGene) Copy() *ConnectionGene {
-	return &ConnectionGene{
-		Key:     cg.Key,
-		Weight:  cg.Weight,
-		Enabled: cg.Enabled,
-	}
+	c := allocConnectionGene()
+	*c = *cg
+	return c
 }
 
 // Mutate adjusts the attributes of the ConnectionGene based on mutation rates in the config.
-// It now accepts the genome to check for cycles when enabling connections in feedforward mode.
+// The genome the gene belongs to provides the context for cycle checks: unless
+// config.AllowCyclicEnable is set, a disabled connection is not re-enabled if doing so
+// would close a cycle through the genome's enabled connections.
 func (cg *ConnectionGene) Mutate(genome *Genome, config *GenomeConfig) {
-	cg.Weight = mutateFloatAttribute(cg.Weight, config.WeightMutateRate, config.WeightReplaceRate, config.WeightMutatePower, config.WeightInitMean, config.WeightInitStdev, config.WeightInitType, config.WeightMinValue, config.WeightMaxValue)
-	// Pass necessary context to mutateBoolAttribute for potential cycle check
-	cg.Enabled = mutateBoolAttribute(cg.Enabled, config.EnabledMutateRate, config.EnabledRateToTrueAdd, config.EnabledRateToFalseAdd, genome, cg)
+	if !config.WeightAgnostic {
+		cg.Weight = mutateFloatAttribute(cg.Weight, config.WeightMutateRate, config.WeightReplaceRate, config.WeightMutatePower, config.WeightInitMean, config.WeightInitStdev, config.WeightInitType, config.WeightMinValue, config.WeightMaxValue)
+	}
+	enabled := mutateBoolAttribute(cg.Enabled, config.EnabledMutateRate, config.EnabledRateToTrueAdd, config.EnabledRateToFalseAdd)
+	if enabled && !cg.Enabled && !config.AllowCyclicEnable && genome != nil && createsCycle(genome, cg.Key.InNodeID, cg.Key.OutNodeID) {
+		enabled = false
+	}
+	cg.Enabled = enabled
+	if config.ConnectionExpression {
+		cg.Expression = mutateFloatAttribute(cg.Expression, config.ExpressionMutateRate, config.ExpressionReplaceRate, config.ExpressionMutatePower, config.ExpressionInitMean, config.ExpressionInitStdev, config.ExpressionInitType, config.ExpressionMinValue, config.ExpressionMaxValue)
+	}
 }
 
 // Distance calculates the genetic distance between two ConnectionGenes.
 func (cg *ConnectionGene) Distance(other *ConnectionGene, config *GenomeConfig) float64 {
-	d := math.Abs(cg.Weight - other.Weight)
+	d := 0.0
+	if !config.WeightAgnostic {
+		d = math.Abs(cg.Weight - other.Weight)
+	}
 	if cg.Enabled != other.Enabled {
 		d += 1.0
 	}
+	if config.ConnectionExpression {
+		d += math.Abs(cg.Expression - other.Expression)
+	}
 	return d * config.CompatibilityWeightCoefficient
 }
 
 // Crossover creates a new ConnectionGene by randomly inheriting attributes from two parent ConnectionGenes.
-func (cg *ConnectionGene) Crossover(other *ConnectionGene) *ConnectionGene {
+// The expression probability is only drawn with connection_expression, so configs without it use
+// the same random numbers as before it existed.
+func (cg *ConnectionGene) Crossover(other *ConnectionGene, config *GenomeConfig) *ConnectionGene {
 	// Assume cg is the primary parent
 	child := cg.Copy()
 
@@ -172,6 +226,10 @@ func (cg *ConnectionGene) Crossover(other *ConnectionGene) *ConnectionGene {
 	// However, neat-python just randomly chooses one parent's value. We'll follow neat-python here.
 	if rand.Float64() < 0.5 {
 		child.Enabled = other.Enabled
+		child.DisabledFor = other.DisabledFor
+	}
+	if config.ConnectionExpression && rand.Float64() < 0.5 {
+		child.Expression = other.Expression
 	}
 
 	return child
@@ -194,8 +252,7 @@ func initFloatAttribute(mean, stdev float64, initType string, minVal, maxVal flo
 		} // Prevent issues if stdev is huge
 		val = rand.Float64()*(rangeMax-rangeMin) + rangeMin
 	default:
-		// Consider returning an error or panicking for unknown type
-		fmt.Printf("Warning: Unknown float init_type '%s', using gaussian\n", initType)
+		// Unknown types are rejected by LoadConfig; fall back to gaussian for hand-built configs.
 		val = rand.NormFloat64()*stdev + mean
 	}
 	return clamp(val, minVal, maxVal)
@@ -221,7 +278,7 @@ func initBoolAttribute(defaultValStr string) bool {
 	return parseBoolAttribute(defaultValStr) // Use helper from config.go (assuming it's accessible or moved)
 }
 
-func mutateBoolAttribute(value bool, mutateRate, rateToTrueAdd, rateToFalseAdd float64, genome *Genome, cg *ConnectionGene) bool {
+func mutateBoolAttribute(value bool, mutateRate, rateToTrueAdd, rateToFalseAdd float64) bool {
 	effectiveMutateRate := mutateRate
 	if value { // Currently true, might mutate to false
 		effectiveMutateRate += rateToFalseAdd
@@ -231,25 +288,16 @@ func mutateBoolAttribute(value bool, mutateRate, rateToTrueAdd, rateToFalseAdd f
 
 	if effectiveMutateRate > 0 && rand.Float64() < effectiveMutateRate {
 		// Instead of just flipping, decide the new state (true or false).
-		newState := rand.Float64() < 0.5
-
-		// Cycle Check: Only allow enabling if it doesn't create a cycle in feedforward mode
-		if !value && newState && genome.Config.FeedForward {
-			// Trying to enable the connection (value=false, newState=true)
-			if createsCycle(genome, cg.Key.InNodeID, cg.Key.OutNodeID) {
-				return false // Prevent enabling if it creates a cycle
-			}
-		}
-		return newState // Return the randomly chosen state (true/false) if no cycle issue
+		return rand.Float64() < 0.5
 	}
 	// No mutation
 	return value
 }
 
-func initStringAttribute(defaultVal string, options []string) string {
+func initStringAttribute(defaultVal string, options []string, logger Logger) string {
 	if len(options) == 0 {
 		// This should ideally be caught during config validation
-		fmt.Println("Warning: Attempting to initialize string attribute with no options.")
+		logger.Warn("Attempting to initialize string attribute with no options")
 		return ""
 	}
 	defaultValLower := strings.ToLower(defaultVal)
@@ -263,7 +311,7 @@ func initStringAttribute(defaultVal string, options []string) string {
 		}
 	}
 	// If default is not 'random'/'none' and not in options, issue warning and pick random
-	fmt.Printf("Warning: Default string value '%s' not in options %v. Choosing random.\n", defaultVal, options)
+	logger.Warn("Default string value not in options, choosing random", "default", defaultVal, "options", options)
 	return options[rand.Intn(len(options))]
 }
 
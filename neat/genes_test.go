@@ -0,0 +1,40 @@
+package neat
+
+import "testing"
+
+func TestConnectionCrossoverExpression(t *testing.T) {
+	config := loadTestConfig(t)
+	key := ConnectionKey{InNodeID: -1, OutNodeID: 0}
+	a := &ConnectionGene{Key: key, Weight: 1, Enabled: true, Expression: 0.25}
+	b := &ConnectionGene{Key: key, Weight: 2, Enabled: true, Expression: 0.75}
+	for _, enabled := range []bool{false, true} {
+		config.Genome.ConnectionExpression = enabled
+		inherited := false
+		for i := 0; i < 100; i++ {
+			if a.Crossover(b, &config.Genome).Expression == b.Expression {
+				inherited = true
+			}
+		}
+		if inherited != enabled {
+			t.Errorf("connection_expression = %v: expression inherited from the other parent = %v", enabled, inherited)
+		}
+	}
+}
+
+func TestNodeCrossoverActivationParam(t *testing.T) {
+	config := loadTestConfig(t)
+	a := &NodeGene{Key: 0, Response: 1, Activation: "sigmoid", Aggregation: "sum", ActivationParam: 0.5}
+	b := &NodeGene{Key: 0, Response: 1, Activation: "sigmoid", Aggregation: "sum", ActivationParam: 2}
+	for _, enabled := range []bool{false, true} {
+		config.Genome.EvolveActivationParam = enabled
+		inherited := false
+		for i := 0; i < 100; i++ {
+			if a.Crossover(b, &config.Genome).ActivationParam == b.ActivationParam {
+				inherited = true
+			}
+		}
+		if inherited != enabled {
+			t.Errorf("evolve_activation_param = %v: parameter inherited from the other parent = %v", enabled, inherited)
+		}
+	}
+}
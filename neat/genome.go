@@ -2,8 +2,10 @@ package neat
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -14,26 +16,86 @@ type Genome struct {
 	Nodes       map[int]*NodeGene                 // Map node ID -> NodeGene
 	Connections map[ConnectionKey]*ConnectionGene // Map connection key -> ConnectionGene
 	Fitness     float64                           // Fitness score of the genome.
+	Fitnesses   []float64                         // Objective values for multi-objective (NSGA-II) reproduction, all maximized.
+	Behavior    []float64                         // Behavior descriptor used by MAP-Elites, set by the fitness function.
+	Extension   GenomeExtension                   // Custom evolvable attributes of a GenomeFactory (nil = none).
 	// Config holds a reference to the configuration for easy access to parameters.
 	// Note: Storing the whole config might be overkill; maybe just GenomeConfig?
 	// Let's start with GenomeConfig.
 	Config *GenomeConfig
+
+	revision uint64 // Changes whenever the genes change; see Revision
+	samples  uint64 // Sample sources handed out so far; see SampleRand
+
+	sortedConns []*ConnectionGene // Connections ordered by key, for Distance; current while sortedRev == revision
+	sortedRev   uint64
 }
 
 // NewGenome creates a new Genome instance with the specified key and config reference.
 func NewGenome(key int, config *GenomeConfig) *Genome {
-	return &Genome{
-		Key:         key,
-		Nodes:       make(map[int]*NodeGene),
-		Connections: make(map[ConnectionKey]*ConnectionGene),
-		Fitness:     0.0,
-		Config:      config,
+	g := allocGenome()
+	g.Key = key
+	g.Config = config
+	g.revision = genomeRevisions.Add(1)
+	return g
+}
+
+// Copy returns a deep copy of the genome: nodes, connections, fitness, objective and behavior
+// values and the extension are duplicated so the copy can be mutated without affecting the
+// original. The GenomeConfig reference is shared.
+func (g *Genome) Copy() *Genome {
+	c := allocGenome()
+	c.Key = g.Key
+	c.Fitness = g.Fitness
+	c.Config = g.Config
+	c.revision = g.revision // Same genes, so cached phenotypes stay valid
+	c.samples = g.samples   // An elite continues its sample stream rather than repeating it
+	if g.Fitnesses != nil {
+		c.Fitnesses = make([]float64, len(g.Fitnesses))
+		copy(c.Fitnesses, g.Fitnesses)
+	}
+	if g.Behavior != nil {
+		c.Behavior = make([]float64, len(g.Behavior))
+		copy(c.Behavior, g.Behavior)
+	}
+	if g.Extension != nil {
+		c.Extension = g.Extension.Copy()
 	}
+	for k, ng := range g.Nodes {
+		c.Nodes[k] = ng.Copy()
+	}
+	for k, cg := range g.Connections {
+		c.Connections[k] = cg.Copy()
+	}
+	if conns, ok := g.indexedConnections(); ok {
+		for _, cg := range conns {
+			c.sortedConns = append(c.sortedConns, c.Connections[cg.Key])
+		}
+		c.sortedRev = c.revision
+	}
+	return c
 }
 
-// ConfigureNew initializes a new genome based on the configuration.
-// It creates input, output, and potentially hidden nodes, and sets up initial connections.
+// DeepClone returns a fully independent copy of the genome, including its own copy of the GenomeConfig.
+// Use it to hand genomes to other goroutines or archives: unlike Copy, mutating the clone
+// (which draws new node keys from its config) never touches state shared with the population.
+func (g *Genome) DeepClone() *Genome {
+	c := g.Copy()
+	if g.Config != nil {
+		c.Config = g.Config.Copy()
+	}
+	return c
+}
+
+// ConfigureNew initializes a new genome based on the configuration, through the config's
+// GenomeFactory (see DefaultGenomeFactory.ConfigureNew).
 func (g *Genome) ConfigureNew() {
+	g.Config.genomeFactory().ConfigureNew(g)
+}
+
+// configureNew creates the output and hidden nodes and the initial connections.
+func (g *Genome) configureNew() {
+	g.MarkModified()
 	// Create node genes for the output nodes first.
 	for _, nodeKey := range g.Config.OutputKeys {
 		g.Nodes[nodeKey] = NewNodeGene(nodeKey, g.Config)
@@ -42,14 +104,8 @@ func (g *Genome) ConfigureNew() {
 	// Create node genes for the hidden nodes, if any.
 	if g.Config.NumHidden > 0 {
 		for i := 0; i < g.Config.NumHidden; i++ {
-			// Get a unique key for the new hidden node.
-			// We use the NodeKeyIndex from the config, which should be initialized >= NumOutputs
-			nodeKey := g.Config.GetNewNodeKey() // This increments the index
-			// Ensure the key isn't already somehow used (shouldn't happen with proper indexing)
-			if _, exists := g.Nodes[nodeKey]; exists {
-				// This indicates a potential issue with NodeKeyIndex management
-				panic(fmt.Sprintf("Attempted to create duplicate node key: %d", nodeKey))
-			}
+			// Get a unique key for the new hidden node; it is always above the genome's existing keys.
+			nodeKey := g.Config.Innovations().NewNodeKey(g)
 			g.Nodes[nodeKey] = NewNodeGene(nodeKey, g.Config)
 		}
 	}
@@ -57,24 +113,15 @@ func (g *Genome) ConfigureNew() {
 	// Add connections based on the initial_connection configuration.
 	// This part is complex and depends on the specific connection scheme.
 	g.setupInitialConnections()
+	g.indexConnections()
 }
 
 // setupInitialConnections creates the initial connections based on the config string.
 func (g *Genome) setupInitialConnections() {
 	connType := g.Config.InitialConnection
-	// Handle potential 'partial N' format
-	parts := strings.Fields(connType)
-	baseConnType := parts[0]
-	connectionFraction := 1.0 // Default for non-partial types
-	if strings.HasPrefix(baseConnType, "partial") && len(parts) > 1 {
-		// Try to parse the fraction (error handling might be needed here)
-		// connectionFraction = strconv.ParseFloat(parts[1], 64)
-		// For now, assume valid config means it's handled, maybe add later.
-		// Let's just focus on the type for the switch statement.
-	}
+	baseConnType, connectionFraction, _ := parseInitialConnection(connType)
 
 	// Collect input, output, and hidden node keys for easier iteration
-	inputKeys := g.Config.InputKeys
 	outputKeys := g.Config.OutputKeys
 	hiddenKeys := []int{}
 	for nk := range g.Nodes {
@@ -97,138 +144,42 @@ func (g *Genome) setupInitialConnections() {
 	case "unconnected":
 		// No connections are made.
 	case "fs_neat_nohidden", "fs_neat":
-		// Connect all inputs to all outputs (FS-NEAT without hidden).
+		// Connect one randomly chosen input to all outputs (FS-NEAT without hidden).
 		// Python `fs_neat` also defaults to this if num_hidden > 0, with a warning.
-		for _, ik := range inputKeys {
+		for _, ik := range fsNeatInputs(g.Config) {
 			for _, ok := range outputKeys {
 				connKey := ConnectionKey{InNodeID: ik, OutNodeID: ok}
 				g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
 			}
 		}
 	case "fs_neat_hidden":
-		// Connect all inputs to all hidden nodes, and all hidden nodes to all outputs.
-		for _, ik := range inputKeys {
+		// Connect one randomly chosen input to all hidden nodes and all outputs, as neat-python does.
+		for _, ik := range fsNeatInputs(g.Config) {
 			for _, hk := range hiddenKeys {
 				connKey := ConnectionKey{InNodeID: ik, OutNodeID: hk}
 				g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
 			}
-		}
-		for _, hk := range hiddenKeys {
 			for _, ok := range outputKeys {
-				connKey := ConnectionKey{InNodeID: hk, OutNodeID: ok}
+				connKey := ConnectionKey{InNodeID: ik, OutNodeID: ok}
 				g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
 			}
 		}
 	case "full_nodirect", "full":
-		// Connect inputs to hidden, hidden to hidden, and hidden to outputs.
-		// No direct input-to-output connections.
 		// Python `full` defaults to this if num_hidden > 0, with a warning.
-		outputNodes := make(map[int]bool)
-		for _, ok := range outputKeys {
-			outputNodes[ok] = true
-		}
-
-		for _, ik := range inputKeys {
-			for _, hk := range hiddenKeys {
-				connKey := ConnectionKey{InNodeID: ik, OutNodeID: hk}
-				g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-			}
-		}
-		for _, hk1 := range hiddenKeys {
-			for _, hk2 := range hiddenKeys {
-				connKey := ConnectionKey{InNodeID: hk1, OutNodeID: hk2}
-				g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-			}
-			for _, ok := range outputKeys {
-				connKey := ConnectionKey{InNodeID: hk1, OutNodeID: ok}
-				g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-			}
+		for _, key := range g.fullConnections(hiddenKeys, false) {
+			g.Connections[key] = NewConnectionGene(key, g.Config)
 		}
 	case "full_direct":
-		// Fully connect, including direct input-output, input-hidden, hidden-hidden, hidden-output.
-		for _, ik := range inputKeys {
-			for _, hk := range hiddenKeys {
-				connKey := ConnectionKey{InNodeID: ik, OutNodeID: hk}
-				g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-			}
-			for _, ok := range outputKeys {
-				connKey := ConnectionKey{InNodeID: ik, OutNodeID: ok}
-				g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-			}
+		for _, key := range g.fullConnections(hiddenKeys, true) {
+			g.Connections[key] = NewConnectionGene(key, g.Config)
 		}
-		for _, hk1 := range hiddenKeys {
-			for _, hk2 := range hiddenKeys {
-				connKey := ConnectionKey{InNodeID: hk1, OutNodeID: hk2}
-				g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-			}
-			for _, ok := range outputKeys {
-				connKey := ConnectionKey{InNodeID: hk1, OutNodeID: ok}
-				g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-			}
-		}
-	case "partial_nodirect", "partial":
-		// Partially connect (probabilistically) like full_nodirect.
-		// Python `partial` defaults to this if num_hidden > 0, with a warning.
-		// TODO: Implement probabilistic connection based on connectionFraction.
-		fmt.Println("Warning: initial_connection 'partial_nodirect'/'partial' not fully implemented yet (using full_nodirect logic).")
-		// Fallback to full_nodirect logic for now
-		outputNodes := make(map[int]bool)
-		for _, ok := range outputKeys {
-			outputNodes[ok] = true
-		}
-		for _, ik := range inputKeys {
-			for _, hk := range hiddenKeys {
-				if rand.Float64() < connectionFraction { // Apply probability
-					connKey := ConnectionKey{InNodeID: ik, OutNodeID: hk}
-					g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-				}
-			}
-		}
-		for _, hk1 := range hiddenKeys {
-			for _, hk2 := range hiddenKeys {
-				if rand.Float64() < connectionFraction {
-					connKey := ConnectionKey{InNodeID: hk1, OutNodeID: hk2}
-					g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-				}
-			}
-			for _, ok := range outputKeys {
-				if rand.Float64() < connectionFraction {
-					connKey := ConnectionKey{InNodeID: hk1, OutNodeID: ok}
-					g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-				}
-			}
-		}
-	case "partial_direct":
-		// Partially connect (probabilistically) like full_direct.
-		fmt.Println("Warning: initial_connection 'partial_direct' not fully implemented yet (using full_direct logic).")
-		// Fallback to full_direct logic for now
-		for _, ik := range inputKeys {
-			for _, hk := range hiddenKeys {
-				if rand.Float64() < connectionFraction {
-					connKey := ConnectionKey{InNodeID: ik, OutNodeID: hk}
-					g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-				}
-			}
-			for _, ok := range outputKeys {
-				if rand.Float64() < connectionFraction {
-					connKey := ConnectionKey{InNodeID: ik, OutNodeID: ok}
-					g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-				}
-			}
-		}
-		for _, hk1 := range hiddenKeys {
-			for _, hk2 := range hiddenKeys {
-				if rand.Float64() < connectionFraction {
-					connKey := ConnectionKey{InNodeID: hk1, OutNodeID: hk2}
-					g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-				}
-			}
-			for _, ok := range outputKeys {
-				if rand.Float64() < connectionFraction {
-					connKey := ConnectionKey{InNodeID: hk1, OutNodeID: ok}
-					g.Connections[connKey] = NewConnectionGene(connKey, g.Config)
-				}
-			}
+	case "partial_nodirect", "partial", "partial_direct":
+		// A random connection_fraction of the full connections, rounded to the nearest count.
+		// Python `partial` defaults to partial_nodirect if num_hidden > 0, with a warning.
+		keys := g.fullConnections(hiddenKeys, baseConnType == "partial_direct")
+		rand.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+		for _, key := range keys[:int(math.Round(float64(len(keys))*connectionFraction))] {
+			g.Connections[key] = NewConnectionGene(key, g.Config)
 		}
 	default:
 		// This should be caught by config validation ideally
@@ -236,8 +187,38 @@ func (g *Genome) setupInitialConnections() {
 	}
 }
 
-// ConfigureCrossover creates a new genome by combining genes from two parent genomes.
+// fsNeatInputs returns the inputs FS-NEAT starts from: one regular input chosen at random, plus
+// the bias input if bias_input is enabled, since it stands in for per-node biases rather than a
+// feature.
+func fsNeatInputs(config *GenomeConfig) []int {
+	regular := config.InputKeys
+	biasKey, hasBias := config.BiasInputKey()
+	if hasBias {
+		regular = regular[:len(regular)-1] // The bias input is the last input key
+	}
+	if len(regular) == 0 {
+		return nil
+	}
+	inputs := []int{regular[rand.Intn(len(regular))]}
+	if hasBias {
+		inputs = append(inputs, biasKey)
+	}
+	return inputs
+}
+
+// ConfigureCrossover creates a new genome by combining genes from two parent genomes, through the
+// config's GenomeFactory (see DefaultGenomeFactory.ConfigureCrossover).
 func (g *Genome) ConfigureCrossover(parent1, parent2 *Genome) {
+	config := g.Config
+	if config == nil {
+		config = parent1.Config
+	}
+	config.genomeFactory().ConfigureCrossover(g, parent1, parent2)
+}
+
+// configureCrossover inherits the genes of the fitter parent, matching genes from either parent.
+func (g *Genome) configureCrossover(parent1, parent2 *Genome) {
+	g.MarkModified()
 	// Assume parent1 is the more fit parent (convention from neat-python)
 	// This matters for deciding which disjoint/excess genes to inherit.
 	if parent1.Fitness < parent2.Fitness {
@@ -245,22 +226,36 @@ func (g *Genome) ConfigureCrossover(parent1, parent2 *Genome) {
 	}
 
 	g.Config = parent1.Config // Child inherits config from parent
+	// With structural alignment, nodes of parent2 created by the same split as nodes of parent1
+	// are matched with them despite different keys.
+	parent2 = g.Config.Innovations().alignedView(parent1, parent2)
 
-	// Inherit nodes: All nodes from the fitter parent are inherited.
-	// Node attributes are handled during connection gene crossover if nodes match.
-	// In neat-python, node crossover isn't explicitly done, nodes are just copied
-	// from the primary parent, and the attributes only matter if the connection exists.
-	// Let's follow that - copy all nodes from parent1.
+	// Inherit node genes: all nodes come from the fitter parent. Nodes present in both parents
+	// inherit each attribute from either parent at random, as in neat-python.
 	for key, node1 := range parent1.Nodes {
-		g.Nodes[key] = node1.Copy() // Must copy to avoid modifying parent
+		node2, exists := parent2.Nodes[key]
+		if exists {
+			g.Nodes[key] = node1.Crossover(node2, g.Config)
+		} else {
+			g.Nodes[key] = node1.Copy() // Must copy to avoid modifying parent
+		}
 	}
 
 	// Inherit connection genes:
+	var reenabled []ConnectionKey // Genes disabled in parent1 but enabled in the child
 	for key, conn1 := range parent1.Connections {
 		conn2, exists := parent2.Connections[key]
 		if exists {
 			// Homologous gene: crossover attributes.
-			g.Connections[key] = conn1.Crossover(conn2)
+			child := conn1.Crossover(conn2, g.Config)
+			if g.Config.CrossoverEnabledMode == "paper" && (!conn1.Enabled || !conn2.Enabled) {
+				// Original NEAT: a gene disabled in either parent is likely to stay disabled.
+				child.Enabled = rand.Float64() >= g.Config.CrossoverDisableProb
+			}
+			if child.Enabled && !conn1.Enabled {
+				reenabled = append(reenabled, key)
+			}
+			g.Connections[key] = child
 		} else {
 			// Disjoint or excess gene (from fitter parent): copy directly.
 			g.Connections[key] = conn1.Copy()
@@ -269,71 +264,51 @@ func (g *Genome) ConfigureCrossover(parent1, parent2 *Genome) {
 
 	// Note: We don't explicitly inherit disjoint/excess genes from the less fit parent (parent2)
 	// following the standard NEAT algorithm and neat-python's implementation.
-}
-
-// Mutate applies mutations to the genome, including structural and attribute mutations.
-func (g *Genome) Mutate() {
-	// Determine if structural mutation should occur.
-	// Handle 'single_structural_mutation' and 'structural_mutation_surer'.
-	// Placeholder logic - assumes only one structural mutation max if single=true.
-	// 'surer' logic not implemented yet.
-	if g.Config.SingleStructuralMutation {
-		mutNodeAdd := rand.Float64() < g.Config.NodeAddProb
-		mutConnAdd := rand.Float64() < g.Config.ConnAddProb
-		mutNodeDel := rand.Float64() < g.Config.NodeDeleteProb
-		mutConnDel := rand.Float64() < g.Config.ConnDeleteProb
+	if parent1.Extension != nil {
+		g.Extension = parent1.Extension.Copy()
+	}
 
-		// Count how many structural mutations are candidates
-		candidates := 0
-		if mutNodeAdd {
-			candidates++
-		}
-		if mutConnAdd {
-			candidates++
-		}
-		if mutNodeDel {
-			candidates++
-		}
-		if mutConnDel {
-			candidates++
+	// A gene parent1 had disabled may close a cycle through parent1's other genes once enabled, as
+	// with mutation; unless cyclic enabling is allowed, such genes stay disabled.
+	if !g.Config.AllowCyclicEnable && len(reenabled) > 0 {
+		sort.Slice(reenabled, func(i, j int) bool {
+			if reenabled[i].InNodeID != reenabled[j].InNodeID {
+				return reenabled[i].InNodeID < reenabled[j].InNodeID
+			}
+			return reenabled[i].OutNodeID < reenabled[j].OutNodeID
+		})
+		for _, key := range reenabled {
+			g.Connections[key].Enabled = false
+			g.Connections[key].Enabled = !createsCycle(g, key.InNodeID, key.OutNodeID)
 		}
+	}
+}
 
-		structureMutated := false
-		if candidates > 0 {
-			// Choose one candidate mutation randomly if multiple triggered
-			choice := rand.Intn(candidates)
-			idx := 0
+// Mutate applies mutations to the genome, including structural and attribute mutations, through
+// the config's GenomeFactory (see DefaultGenomeFactory.Mutate).
+func (g *Genome) Mutate() {
+	g.Config.genomeFactory().Mutate(g)
+}
 
-			if mutNodeAdd {
-				if idx == choice {
-					g.mutateAddNode()
-					structureMutated = true
-				}
-				idx++
-			}
-			if !structureMutated && mutConnAdd {
-				if idx == choice {
-					g.mutateAddConnection()
-					structureMutated = true
-				}
-				idx++
-			}
-			if !structureMutated && mutNodeDel {
-				if idx == choice {
-					g.mutateDeleteNode()
-					structureMutated = true
-				}
-				idx++
-			}
-			if !structureMutated && mutConnDel {
-				if idx == choice {
-					g.mutateDeleteConnection()
-					// structureMutated = true // No need to set, it's the last possible one
-				}
-				// idx++ // No need to increment
-			}
+// mutate applies the configured structural and attribute mutations.
+func (g *Genome) mutate() {
+	g.MarkModified()
+	if g.Config.SingleStructuralMutation {
+		// At most one structural mutation, chosen with the configured probabilities, which are
+		// scaled down when they sum to more than 1 (as in neat-python).
+		c := g.Config
+		div := math.Max(1, c.NodeAddProb+c.NodeDeleteProb+c.ConnAddProb+c.ConnDeleteProb)
+		r := rand.Float64()
+		switch {
+		case r < c.NodeAddProb/div:
+			g.mutateAddNode()
+		case r < (c.NodeAddProb+c.NodeDeleteProb)/div:
+			g.mutateDeleteNode()
+		case r < (c.NodeAddProb+c.NodeDeleteProb+c.ConnAddProb)/div:
+			g.mutateAddConnection()
+		case r < (c.NodeAddProb+c.NodeDeleteProb+c.ConnAddProb+c.ConnDeleteProb)/div:
+			g.mutateDeleteConnection()
 		}
-
 	} else {
 		// Allow multiple structural mutations if single=false
 		if rand.Float64() < g.Config.NodeAddProb {
@@ -357,13 +332,80 @@ func (g *Genome) Mutate() {
 
 	// Mutate connection attributes.
 	for _, cg := range g.Connections {
-		cg.Mutate(g, g.Config) // Pass genome 'g' to connection mutation
+		cg.Mutate(g, g.Config) // The genome provides context for cycle checks when re-enabling
 	}
+
+	g.indexConnections()
+}
+
+// advanceGeneration applies the changes a genome undergoes once per generation, whether it was
+// bred or carried over as an elite: disabled connections age and may be purged, and weights decay.
+func (g *Genome) advanceGeneration() {
+	purged := g.purgeDisabledConnections()
+	if g.applyWeightDecay() || purged {
+		g.MarkModified()
+		g.indexConnections()
+	}
+}
+
+// purgeDisabledConnections ages the disabled connections by a generation and, with
+// disabled_purge_generations, removes those disabled for that many generations in a row with
+// probability disabled_purge_rate, so genomes don't accumulate disabled genes that only slow down
+// distance calculations and bloat checkpoints. A connection that is enabled again starts over.
+// It reports whether any connection was removed.
+func (g *Genome) purgeDisabledConnections() bool {
+	after := g.Config.DisabledPurgeGenerations
+	purged := false
+	for key, cg := range g.Connections {
+		if cg.Enabled {
+			cg.DisabledFor = 0
+			continue
+		}
+		cg.DisabledFor++
+		if after > 0 && cg.DisabledFor >= after && rand.Float64() < g.Config.DisabledPurgeRate {
+			delete(g.Connections, key)
+			purged = true
+		}
+	}
+	return purged
+}
+
+// applyWeightDecay shrinks connection weights according to weight_decay_type and removes
+// connections whose weight falls below weight_prune_threshold. It reports whether either is
+// configured, i.e. whether the genes may have changed.
+func (g *Genome) applyWeightDecay() bool {
+	if g.Config.WeightAgnostic {
+		return false // Tied weights are never decayed or pruned.
+	}
+	if g.Config.WeightDecayType == "none" && g.Config.WeightPruneThreshold <= 0 {
+		return false
+	}
+	rate := g.Config.WeightDecayRate
+	for key, cg := range g.Connections {
+		switch g.Config.WeightDecayType {
+		case "multiplicative":
+			cg.Weight *= 1.0 - rate
+		case "l1":
+			// Soft thresholding: move the weight toward zero by rate without crossing it.
+			if cg.Weight > 0 {
+				cg.Weight = math.Max(0, cg.Weight-rate)
+			} else {
+				cg.Weight = math.Min(0, cg.Weight+rate)
+			}
+		}
+		if g.Config.WeightPruneThreshold > 0 && math.Abs(cg.Weight) < g.Config.WeightPruneThreshold {
+			delete(g.Connections, key)
+		}
+	}
+	return true
 }
 
 // mutateAddNode adds a new node by splitting an existing connection.
 func (g *Genome) mutateAddNode() {
 	if len(g.Connections) == 0 {
+		if g.Config.structuralMutationSurer() {
+			g.mutateAddConnection()
+		}
 		return // Cannot split if no connections exist.
 	}
 
@@ -381,12 +423,17 @@ func (g *Genome) mutateAddNode() {
 	// if !connToSplit.Enabled {
 	// 	return
 	// }
+	// Splitting a disabled connection restores its path, so it is subject to the same cycle check
+	// as re-enabling it.
+	if !connToSplit.Enabled && !g.Config.AllowCyclicEnable && createsCycle(g, connToSplitKey.InNodeID, connToSplitKey.OutNodeID) {
+		return
+	}
 
 	// Disable the original connection.
 	connToSplit.Enabled = false
 
 	// Create the new node.
-	newNodeKey := g.Config.GetNewNodeKey()
+	newNodeKey := g.Config.Innovations().SplitNodeKey(g, connToSplitKey)
 	newNode := NewNodeGene(newNodeKey, g.Config)
 	g.Nodes[newNodeKey] = newNode
 
@@ -434,6 +481,18 @@ func (g *Genome) mutateAddConnection() {
 		return // Cannot add connection if no possible start or end nodes.
 	}
 
+	// Without feed_forward, recurrent_conn_prob decides up front whether this mutation adds a
+	// recurrent connection (self-loops included) or a forward one. If no connection of that kind
+	// is found, a valid one of the other kind is added instead.
+	biased, wantRecurrent := false, false
+	var fallback ConnectionKey
+	hasFallback := false
+	if !g.Config.FeedForward && g.Config.RecurrentConnProb >= 0 {
+		biased = true
+		canRecur := g.Config.AllowSelfConnections || g.Config.AllowRecurrentConnections
+		wantRecurrent = canRecur && rand.Float64() < g.Config.RecurrentConnProb
+	}
+
 	// Attempt to find a valid pair of nodes that are not already connected.
 	// Limit attempts to prevent infinite loops in densely connected genomes.
 	maxAttempts := 20 // Arbitrary limit
@@ -456,7 +515,14 @@ func (g *Genome) mutateAddConnection() {
 		connKey := ConnectionKey{InNodeID: inNodeKey, OutNodeID: outNodeKey}
 
 		// Check if this connection already exists.
-		if _, exists := g.Connections[connKey]; exists {
+		if existing, exists := g.Connections[connKey]; exists {
+			// With structural_mutation_surer, a disabled connection is re-enabled instead, subject
+			// to the same cycle check as enabled mutation.
+			if g.Config.structuralMutationSurer() && !existing.Enabled &&
+				(g.Config.AllowCyclicEnable || !createsCycle(g, inNodeKey, outNodeKey)) {
+				existing.Enabled = true
+				return
+			}
 			continue // Connection already exists
 		}
 
@@ -467,6 +533,20 @@ func (g *Genome) mutateAddConnection() {
 			if createsCycle(g, inNodeKey, outNodeKey) { // Placeholder function
 				continue // Recurrent connection disallowed
 			}
+		} else {
+			if inNodeKey == outNodeKey && !g.Config.AllowSelfConnections {
+				continue
+			}
+			recurrent := createsCycle(g, inNodeKey, outNodeKey)
+			if recurrent && inNodeKey != outNodeKey && !g.Config.AllowRecurrentConnections {
+				continue
+			}
+			if biased && recurrent != wantRecurrent {
+				if !hasFallback {
+					fallback, hasFallback = connKey, true
+				}
+				continue // Not the kind of connection this mutation adds
+			}
 		}
 
 		// Found a valid new connection.
@@ -475,13 +555,73 @@ func (g *Genome) mutateAddConnection() {
 		return // Successfully added a connection
 	}
 
+	if hasFallback {
+		g.Connections[fallback] = NewConnectionGene(fallback, g.Config)
+		return
+	}
+
 	// Failed to find a valid connection after multiple attempts.
 	// fmt.Println("Warning: Failed to find a valid new connection to add.")
 }
 
-// Distance calculates the genetic distance between this genome and another.
-// It considers disjoint/excess genes and differences in matching gene attributes.
+// fullConnections lists the connections of a fully connected genome as neat-python builds them:
+// inputs to hidden nodes and hidden nodes to outputs, plus inputs to outputs if direct or there
+// are no hidden nodes, plus a self-connection on every node of a recurrent genome that allows them.
+func (g *Genome) fullConnections(hiddenKeys []int, direct bool) []ConnectionKey {
+	var keys []ConnectionKey
+	for _, ik := range g.Config.InputKeys {
+		for _, hk := range hiddenKeys {
+			keys = append(keys, ConnectionKey{InNodeID: ik, OutNodeID: hk})
+		}
+	}
+	for _, hk := range hiddenKeys {
+		for _, ok := range g.Config.OutputKeys {
+			keys = append(keys, ConnectionKey{InNodeID: hk, OutNodeID: ok})
+		}
+	}
+	if direct || len(hiddenKeys) == 0 {
+		for _, ik := range g.Config.InputKeys {
+			for _, ok := range g.Config.OutputKeys {
+				keys = append(keys, ConnectionKey{InNodeID: ik, OutNodeID: ok})
+			}
+		}
+	}
+	if !g.Config.FeedForward && g.Config.AllowSelfConnections {
+		for _, nk := range sortedKeys(g.Nodes) {
+			keys = append(keys, ConnectionKey{InNodeID: nk, OutNodeID: nk})
+		}
+	}
+	return keys
+}
+
+// parseInitialConnection splits an initial_connection value into its type and, for the partial
+// types, the connection fraction given after it (1 for the other types).
+func parseInitialConnection(value string) (string, float64, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return "", 0, fmt.Errorf("initial_connection is empty")
+	}
+	if !strings.HasPrefix(fields[0], "partial") {
+		return fields[0], 1, nil
+	}
+	if len(fields) != 2 {
+		return fields[0], 0, fmt.Errorf("initial_connection '%s' needs a connection fraction, e.g. '%s 0.5'", fields[0], fields[0])
+	}
+	fraction, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || fraction < 0 || fraction > 1 {
+		return fields[0], 0, fmt.Errorf("initial_connection fraction '%s' must be between 0 and 1", fields[1])
+	}
+	return fields[0], fraction, nil
+}
+
+// Distance calculates the genetic distance between this genome and another, through the config's
+// GenomeFactory (see DefaultGenomeFactory.Distance).
 func (g *Genome) Distance(other *Genome) float64 {
+	return g.Config.genomeFactory().Distance(g, other)
+}
+
+// distance considers disjoint/excess genes and differences in matching gene attributes.
+func (g *Genome) distance(other *Genome) float64 {
 	// Ensure configs are compatible for distance calculation?
 	// Assume they share the same basic config for now.
 	disjointCount := 0
@@ -497,23 +637,46 @@ func (g *Genome) Distance(other *Genome) float64 {
 	// nodes2 := other.Nodes
 	// maxNodeKey := max(maxKey(nodes1), maxKey(nodes2))
 
-	// Iterate over connections of the first genome.
-	for key, conn1 := range g.Connections {
-		if conn2, exists := other.Connections[key]; exists {
-			// Matching connection gene.
-			weightDiffSum += conn1.Distance(conn2, g.Config) // Distance includes weight and enabled status
-			matchingGeneCount++
-		} else {
-			// Disjoint or excess gene in genome 1.
-			disjointCount++ // Simplified: treat all non-matching as disjoint for now
+	conns1, indexed1 := g.indexedConnections()
+	conns2, indexed2 := other.indexedConnections()
+	if indexed1 && indexed2 {
+		// Both genomes have their connections sorted by key: align them in one merge pass.
+		i, j := 0, 0
+		for i < len(conns1) && j < len(conns2) {
+			switch k1, k2 := conns1[i].Key, conns2[j].Key; {
+			case k1 == k2:
+				weightDiffSum += conns1[i].Distance(conns2[j], g.Config)
+				matchingGeneCount++
+				i++
+				j++
+			case connectionKeyLess(k1, k2):
+				disjointCount++
+				i++
+			default:
+				disjointCount++
+				j++
+			}
+		}
+		disjointCount += len(conns1) - i + len(conns2) - j
+	} else {
+		// Iterate over connections of the first genome.
+		for key, conn1 := range g.Connections {
+			if conn2, exists := other.Connections[key]; exists {
+				// Matching connection gene.
+				weightDiffSum += conn1.Distance(conn2, g.Config) // Distance includes weight and enabled status
+				matchingGeneCount++
+			} else {
+				// Disjoint or excess gene in genome 1.
+				disjointCount++ // Simplified: treat all non-matching as disjoint for now
+			}
 		}
-	}
 
-	// Iterate over connections of the second genome to find its disjoint/excess genes.
-	for key := range other.Connections {
-		if _, exists := g.Connections[key]; !exists {
-			// Disjoint or excess gene in genome 2.
-			disjointCount++
+		// Iterate over connections of the second genome to find its disjoint/excess genes.
+		for key := range other.Connections {
+			if _, exists := g.Connections[key]; !exists {
+				// Disjoint or excess gene in genome 2.
+				disjointCount++
+			}
 		}
 	}
 
@@ -536,6 +699,39 @@ func (g *Genome) Distance(other *Genome) float64 {
 	return compatibility
 }
 
+// indexConnections records the genome's connections sorted by key, so Distance can align two
+// genomes with a merge pass instead of a map lookup per gene, which dominates speciation of large
+// populations. It is called whenever reproduction finishes changing a genome's genes; genomes
+// changed elsewhere lose the index with their revision (see MarkModified) and are compared
+// through their maps until speciation indexes them again.
+func (g *Genome) indexConnections() {
+	clear(g.sortedConns)
+	g.sortedConns = g.sortedConns[:0]
+	for _, cg := range g.Connections {
+		g.sortedConns = append(g.sortedConns, cg)
+	}
+	sort.Slice(g.sortedConns, func(i, j int) bool {
+		return connectionKeyLess(g.sortedConns[i].Key, g.sortedConns[j].Key)
+	})
+	g.sortedRev = g.revision
+}
+
+// indexedConnections returns the connections sorted by key, if the index is current.
+func (g *Genome) indexedConnections() ([]*ConnectionGene, bool) {
+	if g.sortedRev != g.revision || len(g.sortedConns) != len(g.Connections) {
+		return nil, false
+	}
+	return g.sortedConns, true
+}
+
+// connectionKeyLess orders connection keys by input node, then output node.
+func connectionKeyLess(a, b ConnectionKey) bool {
+	if a.InNodeID != b.InNodeID {
+		return a.InNodeID < b.InNodeID
+	}
+	return a.OutNodeID < b.OutNodeID
+}
+
 // Helper function: max returns the greater of two integers.
 func max(a, b int) int {
 	if a > b {
@@ -598,20 +794,19 @@ func (g *Genome) mutateDeleteConnection() {
 	delete(g.Connections, keyToDelete)
 }
 
-// mutateDeleteNode removes a random hidden/output node and its associated connections.
+// mutateDeleteNode removes a random hidden node and its associated connections. Output nodes are
+// fixed by the config and never deleted, as in neat-python.
 func (g *Genome) mutateDeleteNode() {
-	// Collect possible nodes to delete (cannot delete inputs, only outputs/hidden)
+	// Collect possible nodes to delete. Inputs are not in g.Nodes, only referred to by key.
 	deletableNodeKeys := make([]int, 0, len(g.Nodes))
 	for k := range g.Nodes {
-		// We identify hidden/output nodes by them being present in g.Nodes map
-		// (Input nodes are not in g.Nodes by default, only referred to by key)
-		// Double-check it's not an output node if there's a stricter policy needed,
-		// but standard NEAT allows deleting output nodes.
-		deletableNodeKeys = append(deletableNodeKeys, k)
+		if !g.Config.IsOutputKey(k) {
+			deletableNodeKeys = append(deletableNodeKeys, k)
+		}
 	}
 
 	if len(deletableNodeKeys) == 0 {
-		return // No hidden/output nodes to delete
+		return // No hidden nodes to delete
 	}
 
 	// Select a node to delete randomly
@@ -0,0 +1,120 @@
+package neat
+
+import (
+	"math"
+	"testing"
+)
+
+// relatedGenomes returns n pairs of genomes, the second of each a copy of the first mutated
+// further, so they share most of their genes as members of one species do.
+func relatedGenomes(tb testing.TB, n int) [][2]*Genome {
+	tb.Helper()
+	config := loadTestConfig(tb)
+	pairs := make([][2]*Genome, n)
+	for i := range pairs {
+		a := grownGenome(tb, config, i, 40)
+		b := a.Copy()
+		for j := 0; j < 10; j++ {
+			b.Mutate()
+		}
+		pairs[i] = [2]*Genome{a, b}
+	}
+	return pairs
+}
+
+// TestDistanceMergeMatchesMaps checks that the merge pass over indexed connections gives the same
+// distances as aligning genes through the connection maps.
+func TestDistanceMergeMatchesMaps(t *testing.T) {
+	for i, pair := range relatedGenomes(t, 20) {
+		a, b := pair[0], pair[1]
+		if _, ok := a.indexedConnections(); !ok {
+			t.Fatal("mutated genome is not indexed")
+		}
+		merged := a.distance(b)
+		a.MarkModified()
+		b.MarkModified()
+		if _, ok := a.indexedConnections(); ok {
+			t.Fatal("modified genome is still indexed")
+		}
+		mapped := a.distance(b)
+		if math.Abs(merged-mapped) > 1e-12 {
+			t.Errorf("pair %d: merge distance %v, map distance %v", i, merged, mapped)
+		}
+		if d := a.distance(a); d != 0 {
+			t.Errorf("pair %d: distance to itself %v, want 0", i, d)
+		}
+	}
+}
+
+var distanceSink float64
+
+// BenchmarkGenomeDistance compares the merge pass over indexed connections with map lookups.
+func BenchmarkGenomeDistance(b *testing.B) {
+	pairs := relatedGenomes(b, 50)
+	for _, indexed := range []bool{true, false} {
+		name := "merge"
+		if !indexed {
+			name = "maps"
+		}
+		b.Run(name, func(b *testing.B) {
+			for _, pair := range pairs {
+				for _, g := range pair {
+					if indexed {
+						g.indexConnections()
+					} else {
+						g.MarkModified()
+					}
+				}
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				pair := pairs[i%len(pairs)]
+				distanceSink = pair[0].distance(pair[1])
+			}
+		})
+	}
+}
+
+func TestMutateDeleteNodeKeepsOutputs(t *testing.T) {
+	config := loadTestConfig(t)
+	g, err := NewGenomeBuilder(&config.Genome).AddHidden(1, 2).
+		Connect(-1, 1, 1).Connect(1, 0, 1).Connect(-2, 2, 1).Connect(2, 0, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		g.mutateDeleteNode()
+	}
+	if len(g.Nodes) != 1 || g.Nodes[0] == nil {
+		t.Fatalf("nodes after deleting every hidden node = %v, want only output 0", sortedKeys(g.Nodes))
+	}
+	if len(g.Connections) != 0 {
+		t.Errorf("%d connections of deleted nodes remain", len(g.Connections))
+	}
+}
+
+func TestDisabledConnectionsPurgedAfterGenerations(t *testing.T) {
+	config := loadTestConfig(t)
+	config.Genome.DisabledPurgeGenerations = 3
+	config.Genome.DisabledPurgeRate = 1
+	g, err := NewGenomeBuilder(&config.Genome).
+		Connect(-1, 0, 1).Connect(-2, 0, 1).Disable(-2, 0).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabled := ConnectionKey{InNodeID: -2, OutNodeID: 0}
+	for generation := 1; generation < 3; generation++ {
+		g.advanceGeneration()
+		if cg := g.Connections[disabled]; cg == nil || cg.DisabledFor != generation {
+			t.Fatalf("after %d generations the disabled connection is %+v, want DisabledFor %d", generation, cg, generation)
+		}
+	}
+	g.advanceGeneration()
+	if _, ok := g.Connections[disabled]; ok {
+		t.Error("connection disabled for 3 generations was not purged")
+	}
+	if cg := g.Connections[ConnectionKey{InNodeID: -1, OutNodeID: 0}]; cg == nil || cg.DisabledFor != 0 {
+		t.Errorf("enabled connection = %+v, want it kept with DisabledFor 0", cg)
+	}
+}
@@ -0,0 +1,68 @@
+package neat
+
+// GenomeFactory builds, varies and compares the genomes of a GenomeConfig, so genomes can carry
+// custom evolvable attributes in Genome.Extension. Genome.ConfigureNew, ConfigureCrossover, Mutate
+// and Distance call the factory of the genome's config, so Reproduction, the speciators and
+// Population use a custom factory without further changes. Embed DefaultGenomeFactory to keep the
+// standard operators and extend them:
+//
+//	type lrFactory struct{ neat.DefaultGenomeFactory }
+//
+//	func (f lrFactory) Mutate(g *neat.Genome) {
+//		f.DefaultGenomeFactory.Mutate(g)
+//		g.Extension.(*learningRate).mutate()
+//	}
+//
+// Install a factory with Population.SetGenomeFactory or GenomeConfig.SetGenomeFactory. Only the
+// extension can be customized: node and connection genes keep their types.
+type GenomeFactory interface {
+	ConfigureNew(g *Genome)
+	ConfigureCrossover(g, parent1, parent2 *Genome)
+	Mutate(g *Genome)
+	Distance(a, b *Genome) float64
+}
+
+// GenomeExtension holds custom evolvable attributes of a genome, set by a GenomeFactory.
+// Genome.Copy and Genome.Compact copy it with Copy. Gob checkpoints and stores save it if its
+// concrete type is registered with gob.Register; binary checkpoints refuse genomes with an
+// extension. Implement HashableExtension to let the evaluation cache and trial progress tell
+// genomes apart by their extension; genomes whose extension doesn't are always evaluated anew.
+type GenomeExtension interface {
+	Copy() GenomeExtension
+}
+
+// HashableExtension is a GenomeExtension with a content hash. ContentHash must return the same
+// string for extensions that make genomes behave identically, and different strings otherwise.
+type HashableExtension interface {
+	GenomeExtension
+	ContentHash() string
+}
+
+// DefaultGenomeFactory provides the standard NEAT operators and leaves Extension alone, except
+// that crossover inherits the fitter parent's extension.
+type DefaultGenomeFactory struct{}
+
+var _ GenomeFactory = DefaultGenomeFactory{}
+
+// ConfigureNew creates the output and, if configured, hidden nodes of g, and its initial
+// connections.
+func (DefaultGenomeFactory) ConfigureNew(g *Genome) {
+	g.configureNew()
+}
+
+// ConfigureCrossover makes g a child of parent1 and parent2: genes matching in both parents take
+// their attributes from either, the others come from the fitter parent.
+func (DefaultGenomeFactory) ConfigureCrossover(g, parent1, parent2 *Genome) {
+	g.configureCrossover(parent1, parent2)
+}
+
+// Mutate applies the configured structural and attribute mutations to g.
+func (DefaultGenomeFactory) Mutate(g *Genome) {
+	g.mutate()
+}
+
+// Distance returns the genetic distance between a and b, from their disjoint genes and the
+// differences between their matching genes.
+func (DefaultGenomeFactory) Distance(a, b *Genome) float64 {
+	return a.distance(b)
+}
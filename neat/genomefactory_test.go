@@ -0,0 +1,234 @@
+package neat
+
+import (
+	"encoding/gob"
+	"math"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// rateExtension is a hashable extension holding one evolvable attribute.
+type rateExtension struct {
+	Rate float64
+}
+
+func (x *rateExtension) Copy() GenomeExtension {
+	c := *x
+	return &c
+}
+
+func (x *rateExtension) ContentHash() string {
+	return formatHashFloat(x.Rate)
+}
+
+// opaqueExtension is an extension without a content hash.
+type opaqueExtension struct {
+	Rate float64
+}
+
+func (x *opaqueExtension) Copy() GenomeExtension {
+	c := *x
+	return &c
+}
+
+// countingFactory extends the default operators with a rateExtension and counts its calls.
+type countingFactory struct {
+	DefaultGenomeFactory
+	news, crossovers, mutations, distances atomic.Int64
+}
+
+func (f *countingFactory) ConfigureNew(g *Genome) {
+	f.news.Add(1)
+	f.DefaultGenomeFactory.ConfigureNew(g)
+	g.Extension = &rateExtension{Rate: 1}
+}
+
+func (f *countingFactory) ConfigureCrossover(g, parent1, parent2 *Genome) {
+	f.crossovers.Add(1)
+	f.DefaultGenomeFactory.ConfigureCrossover(g, parent1, parent2)
+}
+
+func (f *countingFactory) Mutate(g *Genome) {
+	f.mutations.Add(1)
+	f.DefaultGenomeFactory.Mutate(g)
+	g.Extension.(*rateExtension).Rate *= 1.5
+}
+
+func (f *countingFactory) Distance(a, b *Genome) float64 {
+	f.distances.Add(1)
+	return f.DefaultGenomeFactory.Distance(a, b) +
+		math.Abs(a.Extension.(*rateExtension).Rate-b.Extension.(*rateExtension).Rate)
+}
+
+// TestGenomeFactoryDrivesEvolution checks that Population and Reproduction create, cross over and
+// mutate genomes through a custom factory, and that every genome keeps an extension.
+func TestGenomeFactoryDrivesEvolution(t *testing.T) {
+	config := loadTestConfig(t)
+	config.Neat.PopSize = 30
+	config.Neat.NoFitnessTermination = true
+	p, err := NewPopulation(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetLogger(NopLogger{})
+	f := &countingFactory{}
+	p.SetGenomeFactory(f)
+	if n := f.news.Load(); n != int64(config.Neat.PopSize) {
+		t.Errorf("ConfigureNew called %d times, want %d", n, config.Neat.PopSize)
+	}
+
+	fitness := func(genomes map[int]*Genome) error {
+		for _, g := range genomes {
+			_, enabled := g.Size()
+			g.Fitness = float64(enabled)
+		}
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := p.RunGeneration(fitness); err != nil {
+			t.Fatal(err)
+		}
+		for key, g := range p.Population {
+			if _, ok := g.Extension.(*rateExtension); !ok {
+				t.Fatalf("generation %d: genome %d has extension %v", p.Generation, key, g.Extension)
+			}
+		}
+	}
+	if f.crossovers.Load() == 0 || f.mutations.Load() == 0 || f.distances.Load() == 0 {
+		t.Errorf("factory calls: %d crossovers, %d mutations, %d distances; want all > 0",
+			f.crossovers.Load(), f.mutations.Load(), f.distances.Load())
+	}
+}
+
+// TestGenomeFactorySpeciators checks that the speciators measuring genetic distance use the
+// factory's Distance.
+func TestGenomeFactorySpeciators(t *testing.T) {
+	for _, speciator := range []Speciator{ThresholdSpeciator{}, ClusterSpeciator{Clusters: 3}} {
+		config := loadTestConfig(t)
+		f := &countingFactory{}
+		config.Genome.SetGenomeFactory(f)
+		population := make(map[int]*Genome)
+		for key := 0; key < 20; key++ {
+			g := NewGenome(key, &config.Genome)
+			g.ConfigureNew()
+			g.Mutate()
+			population[key] = g
+		}
+		f.distances.Store(0)
+		if _, err := speciator.Speciate(config, NewSpeciesSet(&config.SpeciesSet), population, 1); err != nil {
+			t.Fatalf("%T: %v", speciator, err)
+		}
+		if f.distances.Load() == 0 {
+			t.Errorf("%T did not call the factory's Distance", speciator)
+		}
+	}
+}
+
+// TestGenomeExtensionCopies checks that Copy, crossover and the compact form copy the extension
+// rather than share it.
+func TestGenomeExtensionCopies(t *testing.T) {
+	config := loadTestConfig(t)
+	fitter := grownGenome(t, config, 1, 10)
+	fitter.Fitness = 2
+	fitter.Extension = &rateExtension{Rate: 0.5}
+	other := grownGenome(t, config, 2, 10)
+	other.Fitness = 1
+	other.Extension = &rateExtension{Rate: 4}
+
+	c := fitter.Copy()
+	if x, ok := c.Extension.(*rateExtension); !ok || x.Rate != 0.5 || x == fitter.Extension {
+		t.Errorf("copied extension = %v, want a separate rate 0.5", c.Extension)
+	}
+
+	child := NewGenome(3, &config.Genome)
+	child.ConfigureCrossover(other, fitter)
+	if x, ok := child.Extension.(*rateExtension); !ok || x.Rate != 0.5 || x == fitter.Extension {
+		t.Errorf("child extension = %v, want a copy of the fitter parent's rate 0.5", child.Extension)
+	}
+
+	e := fitter.Compact().Expand(&config.Genome)
+	if x, ok := e.Extension.(*rateExtension); !ok || x.Rate != 0.5 || x == fitter.Extension {
+		t.Errorf("expanded extension = %v, want a separate rate 0.5", e.Extension)
+	}
+}
+
+// TestEvaluationCacheExtensions checks that the cache tells genomes apart by their extension's
+// content hash and always evaluates genomes whose extension has none.
+func TestEvaluationCacheExtensions(t *testing.T) {
+	config := loadTestConfig(t)
+	base := grownGenome(t, config, 1, 10)
+	withRate := func(key int, ext GenomeExtension) *Genome {
+		g := base.Copy()
+		g.Key = key
+		g.Extension = ext
+		return g
+	}
+	evaluated := 0
+	fitness := func(genomes map[int]*Genome) error {
+		for _, g := range genomes {
+			evaluated++
+			g.Fitness = 1
+		}
+		return nil
+	}
+
+	cache := NewEvaluationCache(10)
+	if err := cache.evaluate(map[int]*Genome{1: withRate(1, &rateExtension{Rate: 1}), 2: withRate(2, &rateExtension{Rate: 2})}, fitness); err != nil {
+		t.Fatal(err)
+	}
+	if evaluated != 2 {
+		t.Fatalf("evaluated %d genomes differing only in their extension, want 2", evaluated)
+	}
+	if err := cache.evaluate(map[int]*Genome{3: withRate(3, &rateExtension{Rate: 2})}, fitness); err != nil {
+		t.Fatal(err)
+	}
+	if evaluated != 2 {
+		t.Error("a genome with a cached extension was evaluated again")
+	}
+
+	evaluated = 0
+	for i := 0; i < 2; i++ {
+		if err := cache.evaluate(map[int]*Genome{4: withRate(4, &opaqueExtension{Rate: 1})}, fitness); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if evaluated != 2 || cache.Len() != 2 {
+		t.Errorf("genome with an unhashable extension: evaluated %d times, %d cached; want 2 and 2", evaluated, cache.Len())
+	}
+}
+
+// TestCheckpointExtensions checks that gob checkpoints keep registered extensions and binary
+// checkpoints refuse them.
+func TestCheckpointExtensions(t *testing.T) {
+	gob.Register(&rateExtension{})
+	config := loadTestConfig(t)
+	config.Neat.PopSize = 10
+	p, err := NewPopulation(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetLogger(NopLogger{})
+	p.SetGenomeFactory(&countingFactory{})
+	dir := t.TempDir()
+
+	err = p.SaveCheckpoint(filepath.Join(dir, "binary.gz"), CheckpointOptions{Format: "binary"})
+	if err == nil || !strings.Contains(err.Error(), "extension") {
+		t.Errorf("binary checkpoint of genomes with extensions: error %v, want one about the extension", err)
+	}
+
+	path := filepath.Join(dir, "gob.gz")
+	if err := p.SaveCheckpoint(path, CheckpointOptions{Format: "gob"}); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCheckpoint(path, "testdata/config.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, g := range loaded.Population {
+		if x, ok := g.Extension.(*rateExtension); !ok || x.Rate != 1 {
+			t.Errorf("genome %d extension loaded as %v, want rate 1", key, g.Extension)
+		}
+	}
+}
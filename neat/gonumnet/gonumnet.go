@@ -0,0 +1,87 @@
+// Package gonumnet converts genomes and their networks into gonum types, so evolved networks can
+// be analyzed with gonum's graph algorithms (topological sorts, paths, centrality) and numeric
+// tooling. It is a separate package so the neat and nn packages don't depend on gonum.
+package gonumnet
+
+import (
+	"fmt"
+
+	"github.com/baldhumanity/neat-go/neat"
+	"github.com/baldhumanity/neat-go/neat/nn"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+// NodeKind tells which part of the network a Node belongs to.
+type NodeKind int
+
+const (
+	Hidden NodeKind = iota
+	Input
+	Output
+)
+
+// Node is a genome node in a gonum graph. Its ID is the node key, so inputs have negative IDs.
+type Node struct {
+	Key  int
+	Kind NodeKind
+}
+
+// ID implements graph.Node.
+func (n Node) ID() int64 { return int64(n.Key) }
+
+// Graph returns the enabled connections of g as a weighted directed graph whose nodes are Node
+// values and whose edge weights are the connection weights (connection expression is ignored).
+// Every input and node gene is included, connected or not. The result implements graph.Directed
+// and graph.Weighted. Self-connections can't be represented in gonum's simple graphs and are
+// rejected.
+func Graph(g *neat.Genome) (*simple.WeightedDirectedGraph, error) {
+	dg := simple.NewWeightedDirectedGraph(0, 0)
+	for _, key := range g.Config.InputKeys {
+		dg.AddNode(Node{Key: key, Kind: Input})
+	}
+	for key := range g.Nodes {
+		kind := Hidden
+		if g.Config.IsOutputKey(key) {
+			kind = Output
+		}
+		dg.AddNode(Node{Key: key, Kind: kind})
+	}
+	for ck, cg := range g.Connections {
+		if !cg.Enabled {
+			continue
+		}
+		if ck.InNodeID == ck.OutNodeID {
+			return nil, fmt.Errorf("gonumnet: self-connection on node %d can't be represented", ck.InNodeID)
+		}
+		from := dg.Node(int64(ck.InNodeID))
+		to := dg.Node(int64(ck.OutNodeID))
+		if from == nil || to == nil {
+			return nil, fmt.Errorf("gonumnet: connection %d->%d references an unknown node", ck.InNodeID, ck.OutNodeID)
+		}
+		dg.SetWeightedEdge(dg.NewWeightedEdge(from, to, cg.Weight))
+	}
+	return dg, nil
+}
+
+// LayerWeights wraps the weights of each layer of net in a Width x InputWidth matrix. Column j
+// of a layer reads position j of the network's value vector: the inputs, then the earlier layers
+// (see nn.DenseNetwork). The matrices share their data with net, so changing one changes the
+// network.
+func LayerWeights(net *nn.DenseNetwork) []*mat.Dense {
+	weights := make([]*mat.Dense, len(net.Layers))
+	for i, layer := range net.Layers {
+		weights[i] = mat.NewDense(layer.Width, layer.InputWidth, layer.Weights)
+	}
+	return weights
+}
+
+// Layers lowers a feed-forward genome into an nn.DenseNetwork and returns it with its layer
+// weight matrices (see LayerWeights).
+func Layers(g *neat.Genome) (*nn.DenseNetwork, []*mat.Dense, error) {
+	net, err := nn.CreateDenseNetwork(g)
+	if err != nil {
+		return nil, nil, err
+	}
+	return net, LayerWeights(net), nil
+}
@@ -0,0 +1,49 @@
+package neat
+
+import "fmt"
+
+// GenerationHook is called at a fixed point of RunGeneration with the population in its current
+// state. Hooks may inspect or modify the population (e.g. save a checkpoint, adjust the config for
+// a curriculum). Returning an error aborts the generation with that error.
+type GenerationHook func(p *Population) error
+
+// generationHooks holds the callbacks registered on a Population, in registration order.
+type generationHooks struct {
+	generationStart []GenerationHook
+	postEvaluate    []GenerationHook
+	postSpeciate    []GenerationHook
+	postReproduce   []GenerationHook
+}
+
+// OnGenerationStart registers a hook called after the generation counter is incremented,
+// before fitness evaluation.
+func (p *Population) OnGenerationStart(hook GenerationHook) {
+	p.hooks.generationStart = append(p.hooks.generationStart, hook)
+}
+
+// OnPostEvaluate registers a hook called after fitness evaluation and best-genome tracking,
+// before the fitness threshold is checked.
+func (p *Population) OnPostEvaluate(hook GenerationHook) {
+	p.hooks.postEvaluate = append(p.hooks.postEvaluate, hook)
+}
+
+// OnPostSpeciate registers a hook called after the population has been divided into species.
+func (p *Population) OnPostSpeciate(hook GenerationHook) {
+	p.hooks.postSpeciate = append(p.hooks.postSpeciate, hook)
+}
+
+// OnPostReproduce registers a hook called after the next generation replaced the population,
+// at the end of the generation.
+func (p *Population) OnPostReproduce(hook GenerationHook) {
+	p.hooks.postReproduce = append(p.hooks.postReproduce, hook)
+}
+
+// runHooks calls each hook in order, stopping at the first error.
+func (p *Population) runHooks(stage string, hooks []GenerationHook) error {
+	for _, hook := range hooks {
+		if err := hook(p); err != nil {
+			return fmt.Errorf("%s hook failed in generation %d: %w", stage, p.Generation, err)
+		}
+	}
+	return nil
+}
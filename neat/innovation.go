@@ -0,0 +1,275 @@
+package neat
+
+import "sync"
+
+// InnovationTracker assigns the keys of new hidden nodes for all genomes sharing a GenomeConfig.
+// It is safe for concurrent use, so genomes may be mutated from several goroutines. Every key is
+// also greater than all node keys of the genome receiving it, so genomes created elsewhere (loaded
+// from a checkpoint, hand-built, injected) never get a duplicate node.
+//
+// With split reuse enabled (innovation_reuse), splitting the same connection in different genomes
+// yields the same node key until the next BeginGeneration, so identical structural innovations
+// line up in crossover and speciation. With origin tracking (structural_alignment), it remembers
+// which connection every split node replaced, so crossover can align nodes of the same split
+// that received different keys. Population forgets the origins of nodes its genomes no longer
+// have once per generation.
+type InnovationTracker struct {
+	mu          sync.Mutex
+	nextNodeKey int
+	splits      map[ConnectionKey]int // Node key created by splitting each connection (nil unless split reuse is on)
+	origins     map[int]ConnectionKey // Connection split to create each node (nil unless origin tracking is on)
+}
+
+// NewInnovationTracker creates a tracker whose first node key is nextNodeKey.
+func NewInnovationTracker(nextNodeKey int) *InnovationTracker {
+	return &InnovationTracker{nextNodeKey: nextNodeKey}
+}
+
+// NextNodeKey returns the key the next new node would get, ignoring genome contents.
+func (t *InnovationTracker) NextNodeKey() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.nextNodeKey
+}
+
+// Reserve makes sure no key up to and including key is assigned to a new node.
+func (t *InnovationTracker) Reserve(key int) {
+	t.mu.Lock()
+	t.nextNodeKey = max(t.nextNodeKey, key+1)
+	t.mu.Unlock()
+}
+
+// ReserveGenome reserves the node keys of g.
+func (t *InnovationTracker) ReserveGenome(g *Genome) {
+	t.Reserve(maxNodeKey(g))
+}
+
+// NewNodeKey returns a key for a new node of g (g may be nil). Keys are never handed out twice.
+func (t *InnovationTracker) NewNodeKey(g *Genome) int {
+	floor := maxNodeKey(g) // Scanned outside the lock: g belongs to the caller
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.newNodeKeyLocked(floor)
+}
+
+// SplitNodeKey returns the key for the node created by splitting conn in g. With split reuse, an
+// earlier split of the same connection this generation supplies the key, unless g already has
+// that node.
+func (t *InnovationTracker) SplitNodeKey(g *Genome, conn ConnectionKey) int {
+	floor := maxNodeKey(g)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.splits == nil {
+		return t.recordOrigin(t.newNodeKeyLocked(floor), conn)
+	}
+	if key, ok := t.splits[conn]; ok {
+		if _, exists := g.Nodes[key]; !exists {
+			return key
+		}
+		return t.recordOrigin(t.newNodeKeyLocked(floor), conn) // The genome already has the reused node
+	}
+	key := t.newNodeKeyLocked(floor)
+	t.splits[conn] = key
+	return t.recordOrigin(key, conn)
+}
+
+// recordOrigin notes that key was created by splitting conn, if origins are tracked, and returns key.
+func (t *InnovationTracker) recordOrigin(key int, conn ConnectionKey) int {
+	if t.origins != nil {
+		t.origins[key] = conn
+	}
+	return key
+}
+
+// TrackSplitOrigins turns recording of the connection each new node splits on or off. Turning
+// it off forgets the recorded origins. Origins are not saved in checkpoints, so nodes created
+// before a resume are not aligned.
+func (t *InnovationTracker) TrackSplitOrigins(on bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case !on:
+		t.origins = nil
+	case t.origins == nil:
+		t.origins = make(map[int]ConnectionKey)
+	}
+}
+
+// SplitOrigin returns the connection that was split to create the node with the given key, if
+// origins were tracked when it was created.
+func (t *InnovationTracker) SplitOrigin(key int) (ConnectionKey, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	conn, ok := t.origins[key]
+	return conn, ok
+}
+
+// pruneOrigins forgets the origins of the nodes whose keys are not in live.
+func (t *InnovationTracker) pruneOrigins(live map[int]bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.origins {
+		if !live[key] {
+			delete(t.origins, key)
+		}
+	}
+}
+
+// pruneSplitOrigins forgets the split origins of nodes that no genome of the population or its
+// species has any more, so origin tracking doesn't grow with every node ever created. Only those
+// genomes are bred from, so the forgotten origins are never needed for alignment.
+func (p *Population) pruneSplitOrigins() {
+	tracker := p.Config.Genome.Innovations()
+	tracker.mu.Lock()
+	tracked := len(tracker.origins)
+	tracker.mu.Unlock()
+	if tracked == 0 {
+		return
+	}
+	live := make(map[int]bool, tracked)
+	addNodes := func(g *Genome) {
+		for key := range g.Nodes {
+			live[key] = true
+		}
+	}
+	for _, g := range p.Population {
+		addNodes(g)
+	}
+	for _, sp := range p.SpeciesSet.Species {
+		if sp.Representative != nil {
+			addNodes(sp.Representative)
+		}
+		for _, g := range sp.Members {
+			addNodes(g)
+		}
+	}
+	tracker.pruneOrigins(live)
+}
+
+// alignedView returns parent2 as seen from parent1 for crossover: each hidden node of parent2
+// that split the same connection as a hidden node of parent1, but under a different key, takes
+// that node's key, and its connections are renamed accordingly. Splits of connections between
+// aligned nodes are aligned too. Genes are shared with parent2, not copied. Without tracked
+// origins or anything to align, parent2 itself is returned.
+func (t *InnovationTracker) alignedView(parent1, parent2 *Genome) *Genome {
+	t.mu.Lock()
+	if len(t.origins) == 0 {
+		t.mu.Unlock()
+		return parent2
+	}
+	byOrigin1 := make(map[ConnectionKey][]int) // Split connection -> parent1 nodes not in parent2
+	for _, k := range sortedNodeKeys(parent1) {
+		if _, shared := parent2.Nodes[k]; shared {
+			continue
+		}
+		if conn, ok := t.origins[k]; ok {
+			byOrigin1[conn] = append(byOrigin1[conn], k)
+		}
+	}
+	var keys2 []int // parent2 nodes not in parent1 with a known origin
+	origins2 := make(map[int]ConnectionKey)
+	for _, k := range sortedNodeKeys(parent2) {
+		if _, shared := parent1.Nodes[k]; shared {
+			continue
+		}
+		if conn, ok := t.origins[k]; ok {
+			keys2 = append(keys2, k)
+			origins2[k] = conn
+		}
+	}
+	t.mu.Unlock()
+	if len(byOrigin1) == 0 || len(keys2) == 0 {
+		return parent2
+	}
+
+	mapping := make(map[int]int)
+	used := make(map[int]bool)
+	rename := func(k int) int {
+		if m, ok := mapping[k]; ok {
+			return m
+		}
+		return k
+	}
+	for changed := true; changed; { // Repeat so splits of aligned connections align as well
+		changed = false
+		for _, k2 := range keys2 {
+			if _, done := mapping[k2]; done {
+				continue
+			}
+			conn := origins2[k2]
+			conn = ConnectionKey{InNodeID: rename(conn.InNodeID), OutNodeID: rename(conn.OutNodeID)}
+			for _, k1 := range byOrigin1[conn] {
+				if !used[k1] {
+					mapping[k2], used[k1], changed = k1, true, true
+					break
+				}
+			}
+		}
+	}
+	if len(mapping) == 0 {
+		return parent2
+	}
+
+	view := &Genome{
+		Key:         parent2.Key,
+		Fitness:     parent2.Fitness,
+		Config:      parent2.Config,
+		Nodes:       make(map[int]*NodeGene, len(parent2.Nodes)),
+		Connections: make(map[ConnectionKey]*ConnectionGene, len(parent2.Connections)),
+	}
+	for k, ng := range parent2.Nodes {
+		view.Nodes[rename(k)] = ng
+	}
+	for ck, cg := range parent2.Connections {
+		view.Connections[ConnectionKey{InNodeID: rename(ck.InNodeID), OutNodeID: rename(ck.OutNodeID)}] = cg
+	}
+	return view
+}
+
+// BeginGeneration forgets the splits of the previous generation and turns split reuse on or off.
+func (t *InnovationTracker) BeginGeneration(reuseSplits bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.splits = nil
+	if reuseSplits {
+		t.splits = make(map[ConnectionKey]int)
+	}
+}
+
+// clone returns an independent tracker with the same state.
+func (t *InnovationTracker) clone() *InnovationTracker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := &InnovationTracker{nextNodeKey: t.nextNodeKey}
+	if t.splits != nil {
+		c.splits = make(map[ConnectionKey]int, len(t.splits))
+		for k, v := range t.splits {
+			c.splits[k] = v
+		}
+	}
+	if t.origins != nil {
+		c.origins = make(map[int]ConnectionKey, len(t.origins))
+		for k, v := range t.origins {
+			c.origins[k] = v
+		}
+	}
+	return c
+}
+
+func (t *InnovationTracker) newNodeKeyLocked(floor int) int {
+	key := max(t.nextNodeKey, floor+1)
+	t.nextNodeKey = key + 1
+	return key
+}
+
+// maxNodeKey returns the largest node key of g, or -1 if g is nil or has no nodes.
+func maxNodeKey(g *Genome) int {
+	maxKey := -1
+	if g == nil {
+		return maxKey
+	}
+	for key := range g.Nodes {
+		maxKey = max(maxKey, key)
+	}
+	return maxKey
+}
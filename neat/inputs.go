@@ -0,0 +1,153 @@
+package neat
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// ConnectedInputs returns the input keys, in configuration order, that reach at least one output
+// through enabled connections, directly or via hidden nodes. The bias input is not included.
+// These are the features the genome actually uses, which FS-NEAT (initial_connection = fs_neat)
+// grows one connection at a time.
+func (g *Genome) ConnectedInputs() []int {
+	incoming := make(map[int][]int)
+	for ck, cg := range g.Connections {
+		if cg.Enabled {
+			incoming[ck.OutNodeID] = append(incoming[ck.OutNodeID], ck.InNodeID)
+		}
+	}
+	reached := make(map[int]bool, len(g.Nodes))
+	stack := append([]int(nil), g.Config.OutputKeys...)
+	for _, k := range stack {
+		reached[k] = true
+	}
+	for len(stack) > 0 {
+		k := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, in := range incoming[k] {
+			if !reached[in] {
+				reached[in] = true
+				stack = append(stack, in)
+			}
+		}
+	}
+	biasKey, hasBias := g.Config.BiasInputKey()
+	var inputs []int
+	for _, k := range g.Config.InputKeys {
+		if reached[k] && !(hasBias && k == biasKey) {
+			inputs = append(inputs, k)
+		}
+	}
+	return inputs
+}
+
+// InputUsageReporter records, at the end of each generation, the fraction of genomes whose
+// outputs are reachable from each input (see Genome.ConnectedInputs), across the population and
+// within each species. Together with input_penalty this supports feature-selective NEAT
+// workflows: inputs whose usage falls away over a run are candidates for removal.
+type InputUsageReporter struct {
+	BaseReporter
+	Generations []int                     // Recorded generations, in order
+	Population  []map[int]float64         // Input key -> fraction of the population using it, per generation
+	Species     []map[int]map[int]float64 // Species key -> input key -> fraction of its members using it, per generation
+	MeanInputs  []float64                 // Mean number of connected inputs per genome, per generation
+
+	generation int
+}
+
+// NewInputUsageReporter creates an empty input usage reporter.
+func NewInputUsageReporter() *InputUsageReporter {
+	return &InputUsageReporter{}
+}
+
+func (r *InputUsageReporter) StartGeneration(generation int) {
+	r.generation = generation
+}
+
+func (r *InputUsageReporter) EndGeneration(config *Config, population map[int]*Genome, speciesSet *SpeciesSet) {
+	if speciesSet == nil {
+		return
+	}
+	// The species members are the generation that was just evaluated; population already holds
+	// the offspring.
+	total := make(map[int]float64)
+	species := make(map[int]map[int]float64, len(speciesSet.Species))
+	members, connected := 0, 0
+	for sid, s := range speciesSet.Species {
+		usage := make(map[int]float64)
+		for _, g := range s.Members {
+			inputs := g.ConnectedInputs()
+			for _, k := range inputs {
+				usage[k]++
+				total[k]++
+			}
+			connected += len(inputs)
+		}
+		for k := range usage {
+			usage[k] /= float64(len(s.Members))
+		}
+		species[sid] = usage
+		members += len(s.Members)
+	}
+	mean := 0.0
+	if members > 0 {
+		for k := range total {
+			total[k] /= float64(members)
+		}
+		mean = float64(connected) / float64(members)
+	}
+	r.Generations = append(r.Generations, r.generation)
+	r.Population = append(r.Population, total)
+	r.Species = append(r.Species, species)
+	r.MeanInputs = append(r.MeanInputs, mean)
+}
+
+// InputKeys returns the keys of all inputs used in any recorded generation, in decreasing order
+// (-1, -2, ..., matching the order of the network inputs).
+func (r *InputUsageReporter) InputKeys() []int {
+	seen := make(map[int]bool)
+	for _, usage := range r.Population {
+		for k := range usage {
+			seen[k] = true
+		}
+	}
+	keys := make([]int, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(keys)))
+	return keys
+}
+
+// WriteCSV writes the population-wide usage to filePath, one row per generation with the mean
+// number of connected inputs followed by one column per input.
+func (r *InputUsageReporter) WriteCSV(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create input usage CSV '%s': %w", filePath, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	keys := r.InputKeys()
+	header := []string{"generation", "mean_inputs"}
+	for _, k := range keys {
+		header = append(header, "input_"+strconv.Itoa(k))
+	}
+	w.Write(header)
+	for i, usage := range r.Population {
+		record := []string{strconv.Itoa(r.Generations[i]), strconv.FormatFloat(r.MeanInputs[i], 'g', -1, 64)}
+		for _, k := range keys {
+			record = append(record, strconv.FormatFloat(usage[k], 'g', -1, 64))
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write input usage CSV '%s': %w", filePath, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,83 @@
+package neat
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Logger receives the library's diagnostic output. Arguments after msg are alternating
+// key/value pairs, as with log/slog; a *slog.Logger satisfies this interface directly.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// DefaultLogger is used by populations without an explicit logger. It prints info-level
+// and more severe messages to standard output.
+var DefaultLogger Logger = NewConsoleLogger(os.Stdout, slog.LevelInfo)
+
+// ConsoleLogger writes human-readable lines ("msg key=value ...") to a writer.
+// Warnings and errors are prefixed with "Warning:" and "Error:".
+type ConsoleLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level slog.Level
+}
+
+// NewConsoleLogger creates a ConsoleLogger writing messages at or above level to w.
+func NewConsoleLogger(w io.Writer, level slog.Level) *ConsoleLogger {
+	return &ConsoleLogger{w: w, level: level}
+}
+
+func (l *ConsoleLogger) Debug(msg string, args ...any) { l.log(slog.LevelDebug, msg, args) }
+func (l *ConsoleLogger) Info(msg string, args ...any)  { l.log(slog.LevelInfo, msg, args) }
+func (l *ConsoleLogger) Warn(msg string, args ...any)  { l.log(slog.LevelWarn, msg, args) }
+func (l *ConsoleLogger) Error(msg string, args ...any) { l.log(slog.LevelError, msg, args) }
+
+func (l *ConsoleLogger) log(level slog.Level, msg string, args []any) {
+	if level < l.level {
+		return
+	}
+	var b strings.Builder
+	switch {
+	case level >= slog.LevelError:
+		b.WriteString("Error: ")
+	case level >= slog.LevelWarn:
+		b.WriteString("Warning: ")
+	}
+	b.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", args[i])
+		}
+	}
+	b.WriteString("\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.w, b.String())
+}
+
+// NopLogger discards all messages.
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...any) {}
+func (NopLogger) Info(string, ...any)  {}
+func (NopLogger) Warn(string, ...any)  {}
+func (NopLogger) Error(string, ...any) {}
+
+// loggerOrDefault returns l, or DefaultLogger if l is nil.
+func loggerOrDefault(l Logger) Logger {
+	if l == nil {
+		return DefaultLogger
+	}
+	return l
+}
@@ -0,0 +1,244 @@
+package neat
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BehaviorDimension is one axis of a MAP-Elites archive. Values are clamped to [Min, Max]
+// and divided into Bins equal-width cells.
+type BehaviorDimension struct {
+	Name string
+	Min  float64
+	Max  float64
+	Bins int
+}
+
+// MapElitesArchive is a grid over behavior dimensions in which every cell keeps the fittest
+// genome whose behavior falls into it.
+type MapElitesArchive struct {
+	Dimensions []BehaviorDimension
+	Cells      map[string]*Genome // Cell key ("i,j,...") -> elite genome
+}
+
+// NewMapElitesArchive creates an empty archive over the given dimensions.
+func NewMapElitesArchive(dimensions []BehaviorDimension) (*MapElitesArchive, error) {
+	if len(dimensions) == 0 {
+		return nil, fmt.Errorf("MAP-Elites archive requires at least one behavior dimension")
+	}
+	for _, d := range dimensions {
+		if d.Bins <= 0 {
+			return nil, fmt.Errorf("behavior dimension '%s' must have a positive number of bins", d.Name)
+		}
+		if d.Max <= d.Min {
+			return nil, fmt.Errorf("behavior dimension '%s' must have max greater than min", d.Name)
+		}
+	}
+	return &MapElitesArchive{Dimensions: dimensions, Cells: make(map[string]*Genome)}, nil
+}
+
+// CellIndex returns the grid coordinates of a behavior descriptor.
+func (a *MapElitesArchive) CellIndex(behavior []float64) ([]int, error) {
+	if len(behavior) != len(a.Dimensions) {
+		return nil, fmt.Errorf("behavior has %d values but the archive has %d dimensions", len(behavior), len(a.Dimensions))
+	}
+	index := make([]int, len(behavior))
+	for i, d := range a.Dimensions {
+		if math.IsNaN(behavior[i]) {
+			return nil, fmt.Errorf("behavior value for dimension '%s' is NaN", d.Name)
+		}
+		v := clamp(behavior[i], d.Min, d.Max)
+		index[i] = min(int((v-d.Min)/(d.Max-d.Min)*float64(d.Bins)), d.Bins-1)
+	}
+	return index, nil
+}
+
+// Add inserts g into the cell of its behavior if the cell is empty or g is fitter than its elite.
+// The archive stores a copy, so g may be modified afterwards. It reports whether g was inserted.
+func (a *MapElitesArchive) Add(g *Genome) (bool, error) {
+	index, err := a.CellIndex(g.Behavior)
+	if err != nil {
+		return false, fmt.Errorf("genome %d: %w", g.Key, err)
+	}
+	key := cellKey(index)
+	if elite, ok := a.Cells[key]; ok && elite.Fitness >= g.Fitness {
+		return false, nil
+	}
+	a.Cells[key] = g.Copy()
+	return true, nil
+}
+
+// Coverage returns the fraction of cells holding an elite.
+func (a *MapElitesArchive) Coverage() float64 {
+	total := 1
+	for _, d := range a.Dimensions {
+		total *= d.Bins
+	}
+	return float64(len(a.Cells)) / float64(total)
+}
+
+// Best returns the fittest elite in the archive, or nil if it is empty.
+func (a *MapElitesArchive) Best() *Genome {
+	var best *Genome
+	for _, g := range a.Elites() {
+		if best == nil || g.Fitness > best.Fitness {
+			best = g
+		}
+	}
+	return best
+}
+
+// Elites returns the archive's genomes ordered by cell key.
+func (a *MapElitesArchive) Elites() []*Genome {
+	keys := make([]string, 0, len(a.Cells))
+	for k := range a.Cells {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	elites := make([]*Genome, len(keys))
+	for i, k := range keys {
+		elites[i] = a.Cells[k]
+	}
+	return elites
+}
+
+func cellKey(index []int) string {
+	parts := make([]string, len(index))
+	for i, v := range index {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// MapElites runs the MAP-Elites algorithm: each generation produces a batch of pop_size genomes
+// (random ones while the archive is empty, otherwise crossover and mutation of elites sampled
+// uniformly from the archive), evaluates them and inserts them into the archive.
+// The fitness function must set both Fitness and Behavior of every genome.
+type MapElites struct {
+	Config        *Config
+	Archive       *MapElitesArchive
+	Generation    int
+	NextGenomeKey int
+	Logger        Logger // Destination for diagnostic output (nil = DefaultLogger)
+}
+
+// NewMapElites creates a MAP-Elites run over the given behavior dimensions.
+func NewMapElites(config *Config, dimensions []BehaviorDimension) (*MapElites, error) {
+	archive, err := NewMapElitesArchive(dimensions)
+	if err != nil {
+		return nil, err
+	}
+	return &MapElites{
+		Config:        config,
+		Archive:       archive,
+		NextGenomeKey: config.Neat.KeyOffset + 1,
+	}, nil
+}
+
+// RunGeneration creates, evaluates and archives one batch of genomes.
+// It returns the number of genomes that entered the archive.
+func (me *MapElites) RunGeneration(fitnessFunc FitnessFunc) (int, error) {
+	me.Generation++
+	elites := me.Archive.Elites()
+	batch := make(map[int]*Genome, me.Config.Neat.PopSize)
+	for i := 0; i < me.Config.Neat.PopSize; i++ {
+		g := NewGenome(me.NextGenomeKey, &me.Config.Genome)
+		me.NextGenomeKey++
+		if len(elites) == 0 {
+			g.ConfigureNew()
+		} else {
+			parent1 := elites[rand.Intn(len(elites))]
+			parent2 := elites[rand.Intn(len(elites))]
+			g.ConfigureCrossover(parent1, parent2)
+			g.Mutate()
+		}
+		batch[g.Key] = g
+	}
+
+	if err := fitnessFunc(batch); err != nil {
+		return 0, fmt.Errorf("fitness evaluation failed in MAP-Elites generation %d: %w", me.Generation, err)
+	}
+
+	keys := make([]int, 0, len(batch))
+	for k := range batch {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	inserted := 0
+	for _, k := range keys {
+		added, err := me.Archive.Add(batch[k])
+		if err != nil {
+			return inserted, err
+		}
+		if added {
+			inserted++
+		}
+	}
+	loggerOrDefault(me.Logger).Info("MAP-Elites generation finished", "generation", me.Generation,
+		"inserted", inserted, "elites", len(me.Archive.Cells), "coverage", me.Archive.Coverage())
+	return inserted, nil
+}
+
+// mapElitesSaveData holds the parts of a MAP-Elites run saved in checkpoints.
+type mapElitesSaveData struct {
+	Archive       *MapElitesArchive
+	Generation    int
+	NextGenomeKey int
+	NodeKeyIndex  int
+}
+
+// SaveCheckpoint saves the archive and run counters to a gzip-compressed gob file, replacing it
+// atomically like Population.SaveCheckpoint.
+func (me *MapElites) SaveCheckpoint(filePath string) error {
+	saveData := mapElitesSaveData{
+		Archive:       me.Archive,
+		Generation:    me.Generation,
+		NextGenomeKey: me.NextGenomeKey,
+		NodeKeyIndex:  me.Config.Genome.Innovations().NextNodeKey(),
+	}
+	return writeCheckpointFile(filePath, gobEncoder(saveData), me.Config.Neat.CheckpointBackup)
+}
+
+// LoadMapElitesCheckpoint restores a MAP-Elites run saved with SaveCheckpoint, reloading the
+// configuration from configPath.
+func LoadMapElitesCheckpoint(checkpointPath string, configPath string) (*MapElites, error) {
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config '%s' for checkpoint: %w", configPath, err)
+	}
+	file, err := os.Open(checkpointPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint file '%s': %w", checkpointPath, err)
+	}
+	defer file.Close()
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader for checkpoint: %w", err)
+	}
+	defer gzReader.Close()
+
+	saveData := mapElitesSaveData{}
+	if err := gob.NewDecoder(gzReader).Decode(&saveData); err != nil {
+		return nil, fmt.Errorf("failed to decode MAP-Elites data from checkpoint: %w", err)
+	}
+	if saveData.Archive.Cells == nil {
+		saveData.Archive.Cells = make(map[string]*Genome)
+	}
+	for _, g := range saveData.Archive.Cells {
+		g.Config = &config.Genome
+	}
+	config.Genome.Innovations().Reserve(saveData.NodeKeyIndex - 1)
+	return &MapElites{
+		Config:        config,
+		Archive:       saveData.Archive,
+		Generation:    saveData.Generation,
+		NextGenomeKey: saveData.NextGenomeKey,
+	}, nil
+}
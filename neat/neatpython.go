@@ -0,0 +1,139 @@
+package neat
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// neatPythonSection describes a config section neat-python reads, by the name LoadConfig expects.
+type neatPythonSection struct {
+	name     string   // Section read by LoadConfig
+	suffixes []string // Suffixes of the names neat-python derives from custom classes, e.g. [MyGenome]
+	fields   any      // Struct the section is mapped to, whose ini tags are the supported options
+}
+
+var neatPythonSections = []neatPythonSection{
+	{"NEAT", nil, NeatConfig{}},
+	{"DefaultGenome", []string{"Genome"}, GenomeConfig{}},
+	{"DefaultReproduction", []string{"Reproduction"}, ReproductionConfig{}},
+	{"DefaultSpeciesSet", []string{"SpeciesSet", "Species"}, SpeciesSetConfig{}},
+	{"DefaultStagnation", []string{"Stagnation"}, StagnationConfig{}},
+}
+
+// LoadNeatPythonConfig loads a config file written for neat-python, for porting experiments
+// without editing their config. On top of LoadConfig it reads the file the way Python's
+// configparser does: option names are case-insensitive and values may continue on indented lines.
+// Sections of custom neat-python classes are read as the default ones (e.g. [MyGenome] as
+// [DefaultGenome]) when the default section is absent, and options neat-python leaves optional
+// get neat-python's defaults. Options and activation or aggregation functions this package
+// doesn't support are logged to logger (nil = DefaultLogger) and ignored, instead of failing or
+// being dropped silently, so any difference from the Python run is visible. Options specific to
+// this package may still be added to the file and are read as usual.
+func LoadNeatPythonConfig(filePath string, logger Logger) (*Config, error) {
+	logger = loggerOrDefault(logger)
+	cfg, err := ini.LoadSources(ini.LoadOptions{
+		IgnoreInlineComment:        true,
+		AllowPythonMultilineValues: true,
+		InsensitiveKeys:            true,
+	}, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config file '%s': %w", filePath, err)
+	}
+
+	known := map[string]bool{ini.DefaultSection: true, "Experimental": true, "Schedules": true}
+	for _, s := range neatPythonSections {
+		known[s.name] = true
+	}
+	for _, s := range neatPythonSections {
+		if cfg.HasSection(s.name) || len(s.suffixes) == 0 {
+			continue
+		}
+		variant, err := neatPythonVariant(cfg, s, known)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file '%s': %w", filePath, err)
+		}
+		if variant == nil {
+			continue
+		}
+		section, _ := cfg.NewSection(s.name)
+		for _, key := range variant.Keys() {
+			section.NewKey(key.Name(), key.Value())
+		}
+		known[variant.Name()] = true
+		logger.Info("Reading neat-python section as its default", "section", variant.Name(), "as", s.name)
+	}
+	for _, section := range cfg.Sections() {
+		if !known[section.Name()] {
+			logger.Warn("Unsupported neat-python section ignored", "section", section.Name())
+		}
+	}
+
+	for _, s := range neatPythonSections {
+		section := cfg.Section(s.name)
+		supported := iniKeys(s.fields)
+		for _, key := range section.KeyStrings() {
+			if !supported[key] {
+				logger.Warn("Unsupported neat-python option ignored", "section", s.name, "option", key)
+				section.DeleteKey(key)
+			}
+		}
+	}
+	genome := cfg.Section("DefaultGenome")
+	dropUnknownFunctions(genome, "activation_options", func(name string) error { _, err := GetActivation(name); return err }, logger)
+	dropUnknownFunctions(genome, "aggregation_options", func(name string) error { _, err := GetAggregation(name); return err }, logger)
+
+	return configFromIni(cfg)
+}
+
+// neatPythonVariant returns the section a neat-python file uses in place of s, if there is one:
+// the only section, not otherwise read, whose name ends with one of s's suffixes.
+func neatPythonVariant(cfg *ini.File, s neatPythonSection, known map[string]bool) (*ini.Section, error) {
+	var found *ini.Section
+	for _, section := range cfg.Sections() {
+		if known[section.Name()] {
+			continue
+		}
+		for _, suffix := range s.suffixes {
+			if strings.HasSuffix(strings.ToLower(section.Name()), strings.ToLower(suffix)) {
+				if found != nil {
+					return nil, fmt.Errorf("both [%s] and [%s] could be the [%s] section", found.Name(), section.Name(), s.name)
+				}
+				found = section
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+// iniKeys returns the option names mapped onto the fields of the struct v.
+func iniKeys(v any) map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		if name := t.Field(i).Tag.Get("ini"); name != "" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// dropUnknownFunctions removes the names that lookup rejects from the space-separated list in key,
+// e.g. custom activation functions registered with neat-python but not with RegisterActivation.
+func dropUnknownFunctions(section *ini.Section, key string, lookup func(name string) error, logger Logger) {
+	if !section.HasKey(key) {
+		return
+	}
+	var kept []string
+	for _, name := range strings.Fields(cleanIniString(section.Key(key).String())) {
+		if lookup(name) != nil {
+			logger.Warn("Unsupported neat-python function ignored", "option", key, "function", name)
+			continue
+		}
+		kept = append(kept, name)
+	}
+	section.Key(key).SetValue(strings.Join(kept, " "))
+}
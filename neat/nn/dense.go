@@ -0,0 +1,188 @@
+package nn
+
+import (
+	"fmt"
+
+	"github.com/baldhumanity/neat-go/neat"
+)
+
+// DenseLayer is one layer of a DenseNetwork: the nodes whose longest path from an input has the
+// same length. Each node reads every value computed before its layer, so the layer is a dense
+// matrix-vector product followed by bias, response and activation.
+type DenseLayer struct {
+	Start            int       // Position of the layer's first node in the value vector
+	Width            int       // Number of nodes in the layer
+	InputWidth       int       // Number of values read by the layer (all values before Start)
+	Weights          []float64 // Row-major Width x InputWidth; zero where a node has no connection
+	Bias             []float64
+	Response         []float64
+	Activations      []neat.ActivationType
+	ActivationParams [][]float64 // Per node; nil entries unless evolve_activation_param is enabled
+	Keys             []int       // Genome node key of each row
+}
+
+// DenseNetwork is an alternative phenotype for large feed-forward networks, such as HyperNEAT
+// substrates, where following connections one by one is slow. The genome is lowered into layers
+// of dense weight matrices evaluated with tight, contiguous loops. Values live in one vector:
+// the inputs first, then each layer in order. The row-major weights can be wrapped directly in
+// BLAS or gonum matrices when an external library is preferred.
+//
+// Only sum aggregation can be expressed as a matrix product, so genomes using other aggregation
+// functions are rejected. Dense evaluation pays for every zero weight, which only pays off when
+// layers are wide and well connected; use FeedForwardNetwork for small, sparse genomes.
+type DenseNetwork struct {
+	NumInputs     int
+	NumValues     int   // Length of the value vector
+	OutputIndices []int // Positions of the output nodes in the value vector
+	Layers        []DenseLayer
+}
+
+// CreateDenseNetwork lowers a feed-forward genome into a DenseNetwork. Connections, expression
+// and node attributes are handled exactly as by CreateFeedForwardNetwork.
+func CreateDenseNetwork(g *neat.Genome) (*DenseNetwork, error) {
+	net, err := CreateFeedForwardNetwork(g)
+	if err != nil {
+		return nil, err
+	}
+	for _, idx := range net.NodeEvalOrder {
+		key := net.Nodes[idx].OriginalKey
+		gn, ok := g.Nodes[key]
+		if !ok {
+			return nil, fmt.Errorf("cannot create DenseNetwork: node %d is connected but has no node gene", key)
+		}
+		if gn.Aggregation != "sum" {
+			return nil, fmt.Errorf("cannot create DenseNetwork: node %d uses aggregation '%s', only 'sum' is supported", key, gn.Aggregation)
+		}
+	}
+
+	// 1. Assign layers: inputs are layer 0, every other node sits one layer after its deepest input.
+	layerOf := make([]int, net.NumNodes)
+	numLayers := 0
+	for _, idx := range net.NodeEvalOrder {
+		layer := 1
+		for _, in := range net.Nodes[idx].Inputs {
+			layer = max(layer, layerOf[in.InputNodeIndex]+1)
+		}
+		layerOf[idx] = layer
+		numLayers = max(numLayers, layer)
+	}
+	members := make([][]int, numLayers+1)
+	for _, idx := range net.NodeEvalOrder {
+		members[layerOf[idx]] = append(members[layerOf[idx]], idx)
+	}
+
+	// 2. Lay out the value vector: inputs in configuration order, then the layers.
+	position := make([]int, net.NumNodes)
+	for i, idx := range net.InputIndices {
+		position[idx] = i
+	}
+	next := len(net.InputIndices)
+	dense := &DenseNetwork{NumInputs: len(net.InputIndices)}
+	for _, layerNodes := range members[1:] {
+		layer := DenseLayer{Start: next, Width: len(layerNodes), InputWidth: next}
+		for _, idx := range layerNodes {
+			position[idx] = next
+			next++
+		}
+		dense.Layers = append(dense.Layers, layer)
+	}
+	dense.NumValues = next
+
+	// 3. Fill in the weight matrices and node attributes.
+	for l, layerNodes := range members[1:] {
+		layer := &dense.Layers[l]
+		layer.Weights = make([]float64, layer.Width*layer.InputWidth)
+		layer.Bias = make([]float64, layer.Width)
+		layer.Response = make([]float64, layer.Width)
+		layer.Activations = make([]neat.ActivationType, layer.Width)
+		layer.ActivationParams = make([][]float64, layer.Width)
+		layer.Keys = make([]int, layer.Width)
+		for r, idx := range layerNodes {
+			node := net.Nodes[idx]
+			row := layer.Weights[r*layer.InputWidth : (r+1)*layer.InputWidth]
+			layer.Bias[r] = node.Bias
+			for _, in := range node.Inputs {
+				if in.InputNodeIndex == net.BiasIndex {
+					layer.Bias[r] += in.Weight // The bias input is constant 1, so its weight is a bias
+					continue
+				}
+				row[position[in.InputNodeIndex]] += in.Weight
+			}
+			layer.Response[r] = node.Response
+			layer.Activations[r] = node.ActivationFn
+			layer.ActivationParams[r] = node.ActivationParams
+			layer.Keys[r] = node.OriginalKey
+		}
+	}
+	dense.OutputIndices = make([]int, len(net.OutputIndices))
+	for i, idx := range net.OutputIndices {
+		dense.OutputIndices[i] = position[idx]
+	}
+	return dense, nil
+}
+
+// Activate computes the network's output for one input vector.
+func (dn *DenseNetwork) Activate(inputs []float64) ([]float64, error) {
+	outputs, err := dn.ActivateBatch([][]float64{inputs})
+	if err != nil {
+		return nil, err
+	}
+	return outputs[0], nil
+}
+
+// ActivateBatch computes the outputs for several input vectors at once. Each weight row is
+// applied to the whole batch while it is in cache, which is considerably faster than calling
+// Activate once per input for wide layers.
+func (dn *DenseNetwork) ActivateBatch(inputs [][]float64) ([][]float64, error) {
+	for i, in := range inputs {
+		if len(in) != dn.NumInputs {
+			return nil, fmt.Errorf("mismatch between input count (%d) and network input nodes (%d) in batch entry %d", len(in), dn.NumInputs, i)
+		}
+	}
+
+	// values holds one value vector per batch entry, back to back.
+	values := make([]float64, len(inputs)*dn.NumValues)
+	for s, in := range inputs {
+		copy(values[s*dn.NumValues:], in)
+	}
+	for l := range dn.Layers {
+		layer := &dn.Layers[l]
+		for r := 0; r < layer.Width; r++ {
+			row := layer.Weights[r*layer.InputWidth : (r+1)*layer.InputWidth]
+			bias, response := layer.Bias[r], layer.Response[r]
+			activation, params := layer.Activations[r], layer.ActivationParams[r]
+			for s := range inputs {
+				v := values[s*dn.NumValues : (s+1)*dn.NumValues]
+				v[layer.Start+r] = activation((dot(row, v[:layer.InputWidth])+bias)*response, params...)
+			}
+		}
+	}
+
+	outputs := make([][]float64, len(inputs))
+	for s := range inputs {
+		v := values[s*dn.NumValues : (s+1)*dn.NumValues]
+		outputs[s] = make([]float64, len(dn.OutputIndices))
+		for i, pos := range dn.OutputIndices {
+			outputs[s][i] = v[pos]
+		}
+	}
+	return outputs, nil
+}
+
+// dot returns the dot product of two equally long vectors. The loop is unrolled into four
+// independent accumulators so the compiler can keep them in registers and pipeline the multiplies.
+func dot(a, b []float64) float64 {
+	b = b[:len(a)]
+	var s0, s1, s2, s3 float64
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		s0 += a[i] * b[i]
+		s1 += a[i+1] * b[i+1]
+		s2 += a[i+2] * b[i+2]
+		s3 += a[i+3] * b[i+3]
+	}
+	for ; i < len(a); i++ {
+		s0 += a[i] * b[i]
+	}
+	return (s0 + s1) + (s2 + s3)
+}
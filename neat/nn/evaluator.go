@@ -0,0 +1,98 @@
+package nn
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/baldhumanity/neat-go/neat"
+)
+
+// Evaluator runs a batch of compiled networks over a shared input dataset and returns
+// outputs[n][i], the output vector of networks[n] for inputs[i]. It is the integration point for
+// massively parallel backends (CUDA, WebGPU, ...): DenseNetwork's row-major layer matrices can be
+// uploaded as they are, and every network sees the same inputs, so the dataset is transferred once
+// per batch. CPUEvaluator is the reference implementation.
+type Evaluator interface {
+	Evaluate(networks []*DenseNetwork, inputs [][]float64) ([][][]float64, error)
+}
+
+// CPUEvaluator evaluates networks concurrently on the CPU, each one over the whole dataset with
+// DenseNetwork.ActivateBatch.
+type CPUEvaluator struct {
+	Workers int // Max networks evaluated at once (0 = runtime.NumCPU())
+}
+
+// NewCPUEvaluator creates an evaluator using all CPUs.
+func NewCPUEvaluator() *CPUEvaluator {
+	return &CPUEvaluator{Workers: runtime.NumCPU()}
+}
+
+// Evaluate implements Evaluator.
+func (ce *CPUEvaluator) Evaluate(networks []*DenseNetwork, inputs [][]float64) ([][][]float64, error) {
+	workers := ce.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	outputs := make([][][]float64, len(networks))
+	errs := make([]error, len(networks))
+	semaphore := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for n, net := range networks {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(n int, net *DenseNetwork) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			outputs[n], errs[n] = net.ActivateBatch(inputs)
+		}(n, net)
+	}
+	wg.Wait()
+	for n, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("network %d: %w", n, err)
+		}
+	}
+	return outputs, nil
+}
+
+// DatasetScoreFunc computes a genome's fitness from its outputs for every entry of the dataset,
+// in dataset order.
+type DatasetScoreFunc func(g *neat.Genome, outputs [][]float64) (float64, error)
+
+// EvaluatorFitness returns a FitnessFunc that compiles every genome into a DenseNetwork, runs the
+// whole batch through ev in a single call and sets each genome's fitness with score.
+func EvaluatorFitness(ev Evaluator, inputs [][]float64, score DatasetScoreFunc) neat.FitnessFunc {
+	return func(genomes map[int]*neat.Genome) error {
+		keys := make([]int, 0, len(genomes))
+		for k := range genomes {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+
+		networks := make([]*DenseNetwork, len(keys))
+		for i, k := range keys {
+			net, err := CreateDenseNetwork(genomes[k])
+			if err != nil {
+				return fmt.Errorf("failed to compile genome %d: %w", k, err)
+			}
+			networks[i] = net
+		}
+		outputs, err := ev.Evaluate(networks, inputs)
+		if err != nil {
+			return err
+		}
+		if len(outputs) != len(networks) {
+			return fmt.Errorf("evaluator returned outputs for %d networks, expected %d", len(outputs), len(networks))
+		}
+		for i, k := range keys {
+			fitness, err := score(genomes[k], outputs[i])
+			if err != nil {
+				return fmt.Errorf("scoring genome %d failed: %w", k, err)
+			}
+			genomes[k].Fitness = fitness
+		}
+		return nil
+	}
+}
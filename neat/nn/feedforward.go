@@ -2,6 +2,7 @@ package nn
 
 import (
 	"fmt"
+	"math/rand"
 	"sort"
 
 	"github.com/baldhumanity/neat-go/neat" // Import the parent neat package
@@ -16,99 +17,87 @@ type InputConnection struct {
 // neuralNode represents a node during network activation, optimized for slice access.
 // It stores pre-fetched activation/aggregation functions and pre-processed input connection info.
 type neuralNode struct {
-	OriginalKey   int // Original node key (useful for debugging/reference)
-	Bias          float64
-	Response      float64
-	ActivationFn  neat.ActivationType
-	AggregationFn neat.AggregationType
-	Inputs        []InputConnection // Optimized incoming connections
+	OriginalKey      int // Original node key (useful for debugging/reference)
+	Bias             float64
+	Response         float64
+	ActivationFn     neat.ActivationType
+	ActivationName   string    // Name of the activation function, for NaN/Inf reports
+	ActivationParams []float64 // Extra activation arguments (nil unless evolve_activation_param is enabled)
+	AggregationFn    neat.AggregationType
+	AggregationName  string            // Name of the aggregation function, for activation traces
+	Inputs           []InputConnection // Optimized incoming connections
 }
 
 // FeedForwardNetwork represents a phenotype network optimized for feed-forward activation using slice indexing.
 type FeedForwardNetwork struct {
 	InputIndices  []int        // Slice indices for input nodes
 	OutputIndices []int        // Slice indices for output nodes
+	BiasIndex     int          // Slice index of the bias input, which is always 1 (-1 without bias_input)
 	NodeEvalOrder []int        // Topologically sorted list of node slice indices for evaluation (excluding inputs)
 	Nodes         []neuralNode // Slice of all nodes (indexed 0..N-1), includes inputs
 	NumNodes      int          // Total number of nodes (inputs + hidden + outputs)
+
+	// Optional NaN/Inf guard (see GuardMode). Guard is off by default.
+	Guard          GuardMode            // How non-finite node outputs are handled
+	GuardLimit     float64              // Clamp bound for GuardClamp (0 = DefaultGuardLimit)
+	OnNonFinite    func(NonFiniteEvent) // Called for every non-finite node output when Guard is on
+	NonFiniteCount int                  // Number of non-finite node outputs seen when Guard is on
+
+	// Optional activation trace for debugging. Tracing is off by default.
+	Trace     bool             // Record an ActivationTrace on every Activate call
+	LastTrace *ActivationTrace // Trace of the most recent Activate call when Trace is set
 }
 
 // CreateFeedForwardNetwork builds a runnable, optimized feed-forward network from a genome.
-// It assigns unique slice indices to each node and performs a topological sort on these indices.
+// It assigns each node a slice index in key order and performs a topological sort on these indices.
+// The work is done over sorted slices rather than maps, so building is cheap enough to repeat for
+// every genome every generation, and node inputs are ordered by key, which keeps the network's
+// outputs independent of map iteration order.
+// When connection_expression is enabled in sample mode, each call samples which connections are expressed,
+// so build a fresh network for every evaluation that should see an independent sample.
 func CreateFeedForwardNetwork(g *neat.Genome) (*FeedForwardNetwork, error) {
 	if !g.Config.FeedForward {
 		return nil, fmt.Errorf("cannot create FeedForwardNetwork for a genome configured with FeedForward=false")
 	}
 
-	// 1. Gather all unique node keys and create index mapping
-	allNodeKeysMap := make(map[int]struct{}) // Use a map as a set for uniqueness
-	inputKeysMap := make(map[int]struct{})
-	outputKeysMap := make(map[int]struct{})
+	// 1. Gather the expressed connections, sorted by target and then source node so the inputs of
+	// each node form one contiguous run.
+	conns := expressedConnections(g)
 
-	for _, k := range g.Config.InputKeys {
-		allNodeKeysMap[k] = struct{}{}
-		inputKeysMap[k] = struct{}{}
-	}
-	for _, k := range g.Config.OutputKeys {
-		allNodeKeysMap[k] = struct{}{}
-		outputKeysMap[k] = struct{}{}
-	}
+	// 2. Collect every node key (inputs, outputs, genome nodes and connection endpoints, which
+	// shouldn't add anything for a valid genome) and sort them; a node's slice index is the
+	// position of its key, found by binary search.
+	keys := make([]int, 0, len(g.Config.InputKeys)+len(g.Config.OutputKeys)+len(g.Nodes)+2*len(conns))
+	keys = append(keys, g.Config.InputKeys...)
+	keys = append(keys, g.Config.OutputKeys...)
 	for k := range g.Nodes {
-		allNodeKeysMap[k] = struct{}{}
+		keys = append(keys, k)
 	}
-	enabledConnections := make(map[neat.ConnectionKey]neat.ConnectionGene)
-	for key, gc := range g.Connections {
-		if !gc.Enabled {
-			continue
-		}
-		enabledConnections[key] = *gc.Copy()
-		// Ensure connected nodes are included, even if not in input/output/defined nodes (shouldn't happen with valid genome)
-		allNodeKeysMap[key.InNodeID] = struct{}{}
-		allNodeKeysMap[key.OutNodeID] = struct{}{}
-	}
-
-	// Sort keys for deterministic index assignment
-	allNodeKeysList := make([]int, 0, len(allNodeKeysMap))
-	for k := range allNodeKeysMap {
-		allNodeKeysList = append(allNodeKeysList, k)
+	for _, c := range conns {
+		keys = append(keys, c.key.InNodeID, c.key.OutNodeID)
 	}
-	sort.Ints(allNodeKeysList)
-
-	nodeKeyToIndex := make(map[int]int, len(allNodeKeysList))
-	indexToNodeKey := make([]int, len(allNodeKeysList)) // Optional, for debugging/reference
-	for i, key := range allNodeKeysList {
-		nodeKeyToIndex[key] = i
-		indexToNodeKey[i] = key
+	sort.Ints(keys)
+	numNodes := 0
+	for i, k := range keys {
+		if i == 0 || k != keys[numNodes-1] {
+			keys[numNodes] = k
+			numNodes++
+		}
 	}
-	numNodes := len(allNodeKeysList)
+	keys = keys[:numNodes]
+	indexOf := func(key int) int { return sort.SearchInts(keys, key) }
 
-	// 2. Initialize the Nodes slice
+	// 3. Initialize the Nodes slice
 	nodesSlice := make([]neuralNode, numNodes)
 	for key, gn := range g.Nodes {
-		idx, ok := nodeKeyToIndex[key]
-		if !ok {
-			// This should not happen if key collection was correct
-			return nil, fmt.Errorf("internal error: genome node key %d not found in index map", key)
-		}
-		actFn, err := neat.GetActivation(gn.Activation)
+		node, err := newNeuralNode(g, gn)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get activation function '%s' for node %d: %w", gn.Activation, key, err)
-		}
-		aggFn, err := neat.GetAggregation(gn.Aggregation)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get aggregation function '%s' for node %d: %w", gn.Aggregation, key, err)
-		}
-		nodesSlice[idx] = neuralNode{
-			OriginalKey:   key,
-			Bias:          gn.Bias,
-			Response:      gn.Response,
-			ActivationFn:  actFn,
-			AggregationFn: aggFn,
-			Inputs:        []InputConnection{}, // Initialize empty, populate next
+			return nil, err
 		}
+		nodesSlice[indexOf(key)] = node
 	}
-	// Initialize input nodes that might not be in g.Nodes (standard NEAT)
-	// Assume default bias=0, response=1, and lookup standard activation/aggregation functions.
+	// Initialize input nodes that are not in g.Nodes (standard NEAT) with bias=0, response=1 and
+	// the standard identity activation and sum aggregation; inputs don't activate or aggregate.
 	identityFn, err := neat.GetActivation("identity") // Use standard name
 	if err != nil {
 		return nil, fmt.Errorf("failed to get default 'identity' activation function: %w", err)
@@ -117,117 +106,101 @@ func CreateFeedForwardNetwork(g *neat.Genome) (*FeedForwardNetwork, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get default 'sum' aggregation function: %w", err)
 	}
-
-	for inputKey := range inputKeysMap {
-		if _, isInGenomeNodes := g.Nodes[inputKey]; !isInGenomeNodes {
-			idx := nodeKeyToIndex[inputKey]
+	isInput := make([]bool, numNodes)
+	inputIndices := make([]int, len(g.Config.InputKeys))
+	for i, inputKey := range g.Config.InputKeys {
+		idx := indexOf(inputKey)
+		inputIndices[i] = idx
+		isInput[idx] = true
+		if _, exists := g.Nodes[inputKey]; !exists {
 			nodesSlice[idx] = neuralNode{
 				OriginalKey:   inputKey,
-				Bias:          0.0,        // Default for input nodes
-				Response:      1.0,        // Default for input nodes
-				ActivationFn:  identityFn, // Inputs don't activate, but set a default
-				AggregationFn: sumAggFn,   // Inputs don't aggregate, but set a default
-				Inputs:        []InputConnection{},
+				Bias:          0.0,
+				Response:      1.0,
+				ActivationFn:  identityFn,
+				AggregationFn: sumAggFn,
 			}
-		} else {
-			// If an input key *is* in g.Nodes, its properties were set above.
-			// Need to ensure its Activation/Aggregation are appropriate if defined?
-			// For now, assume g.Nodes only contains hidden/output.
 		}
 	}
+	outputIndices := make([]int, len(g.Config.OutputKeys))
+	for i, key := range g.Config.OutputKeys {
+		outputIndices[i] = indexOf(key)
+	}
+	biasIndex := -1
+	if key, ok := g.Config.BiasInputKey(); ok {
+		biasIndex = indexOf(key)
+		inputIndices = inputIndices[:len(inputIndices)-1] // The bias input is the last input key
+	}
 
-	// 3. Populate Inputs for each node in the slice
-	for connKey, gc := range enabledConnections {
-		inNodeIndex, okIn := nodeKeyToIndex[connKey.InNodeID]
-		outNodeIndex, okOut := nodeKeyToIndex[connKey.OutNodeID]
-		if !okIn || !okOut {
-			// Should not happen
-			return nil, fmt.Errorf("internal error: connection key node (%d or %d) not found in index map", connKey.InNodeID, connKey.OutNodeID)
-		}
-
-		inputConn := InputConnection{
-			InputNodeIndex: inNodeIndex,
-			Weight:         gc.Weight,
+	// 4. Populate Inputs for each node: one backing array, sliced per target node. Count the
+	// successors of each node on the way for the topological sort.
+	inputs := make([]InputConnection, len(conns))
+	outDegree := make([]int, numNodes+1) // Becomes the offsets into successors below
+	inDegree := make([]int, numNodes)
+	for start := 0; start < len(conns); {
+		target := indexOf(conns[start].key.OutNodeID)
+		end := start
+		for end < len(conns) && conns[end].key.OutNodeID == conns[start].key.OutNodeID {
+			src := indexOf(conns[end].key.InNodeID)
+			inputs[end] = InputConnection{InputNodeIndex: src, Weight: conns[end].weight}
+			outDegree[src+1]++
+			end++
 		}
-		nodesSlice[outNodeIndex].Inputs = append(nodesSlice[outNodeIndex].Inputs, inputConn)
+		nodesSlice[target].Inputs = inputs[start:end:end]
+		inDegree[target] = end - start
+		start = end
 	}
 
-	// 4. Topological sort using indices (Kahn's algorithm)
-	inDegree := make([]int, numNodes)
-	graph := make([][]int, numNodes) // Adjacency list: graph[i] lists nodes that node i outputs to
-
-	for targetNodeIndex := range nodesSlice {
-		for _, inputConn := range nodesSlice[targetNodeIndex].Inputs {
-			sourceNodeIndex := inputConn.InputNodeIndex
-			inDegree[targetNodeIndex]++
-			if graph[sourceNodeIndex] == nil {
-				graph[sourceNodeIndex] = []int{}
-			}
-			graph[sourceNodeIndex] = append(graph[sourceNodeIndex], targetNodeIndex)
+	// 5. Topological sort using indices (Kahn's algorithm), always taking the lowest ready index
+	// so the evaluation order is deterministic. successors[offsets[i]:offsets[i+1]] lists the
+	// nodes that node i outputs to.
+	offsets := outDegree
+	for i := 1; i <= numNodes; i++ {
+		offsets[i] += offsets[i-1]
+	}
+	successors := make([]int, len(conns))
+	fill := make([]int, numNodes)
+	copy(fill, offsets[:numNodes])
+	for target := range nodesSlice {
+		for _, in := range nodesSlice[target].Inputs {
+			successors[fill[in.InputNodeIndex]] = target
+			fill[in.InputNodeIndex]++
 		}
 	}
 
-	// Kahn's algorithm queue (indices)
-	queue := []int{}
+	ready := make([]int, 0, numNodes) // Kept sorted; ready[0] is taken next
 	for i := 0; i < numNodes; i++ {
 		if inDegree[i] == 0 {
-			queue = append(queue, i)
+			ready = append(ready, i)
 		}
 	}
-	sort.Ints(queue) // Sort initial queue for deterministic order
-
-	fullEvalOrderIndices := []int{} // Stores the full order including inputs
-	for len(queue) > 0 {
-		// Dequeue node index
-		u := queue[0]
-		queue = queue[1:]
-		fullEvalOrderIndices = append(fullEvalOrderIndices, u)
-
-		// Process neighbors (indices)
-		neighbors := graph[u] // Nodes that 'u' outputs to
-		sort.Ints(neighbors)  // Process neighbors deterministically
-		for _, v := range neighbors {
+	finalEvalOrder := make([]int, 0, numNodes-len(g.Config.InputKeys))
+	visited := 0
+	for len(ready) > 0 {
+		u := ready[0]
+		ready = ready[1:]
+		visited++
+		if !isInput[u] {
+			finalEvalOrder = append(finalEvalOrder, u)
+		}
+		for _, v := range successors[offsets[u]:offsets[u+1]] {
 			inDegree[v]--
 			if inDegree[v] == 0 {
-				queue = append(queue, v)
+				ready = insertSorted(ready, v)
 			}
 		}
-		sort.Ints(queue) // Keep queue sorted for determinism
 	}
-
-	// Check if sort was successful (cycle detection)
-	if len(fullEvalOrderIndices) != numNodes {
+	if visited != numNodes {
 		// Cycle detected or graph issue
-		return nil, fmt.Errorf("failed topological sort: cycle detected or graph issue (expected %d nodes, got %d)", numNodes, len(fullEvalOrderIndices))
-	}
-
-	// 5. Filter evalOrder to exclude input node indices
-	finalEvalOrder := make([]int, 0, numNodes) // Capacity estimate
-	inputIndexSet := make(map[int]struct{})
-	for _, ik := range g.Config.InputKeys {
-		inputIndexSet[nodeKeyToIndex[ik]] = struct{}{}
-	}
-	for _, nodeIndex := range fullEvalOrderIndices {
-		if _, isInput := inputIndexSet[nodeIndex]; !isInput {
-			finalEvalOrder = append(finalEvalOrder, nodeIndex)
-		}
-	}
-
-	// 6. Prepare InputIndices and OutputIndices
-	inputIndices := make([]int, len(g.Config.InputKeys))
-	for i, key := range g.Config.InputKeys {
-		inputIndices[i] = nodeKeyToIndex[key]
-	}
-	outputIndices := make([]int, len(g.Config.OutputKeys))
-	for i, key := range g.Config.OutputKeys {
-		outputIndices[i] = nodeKeyToIndex[key]
+		return nil, fmt.Errorf("failed topological sort (expected %d nodes, got %d): %w", numNodes, visited, neat.ErrCycleDetected)
 	}
 
-	// 7. Construct the network
+	// 6. Construct the network
 	net := &FeedForwardNetwork{
 		InputIndices:  inputIndices,
 		OutputIndices: outputIndices,
-		NodeEvalOrder: finalEvalOrder, // Use the order excluding inputs
+		BiasIndex:     biasIndex,
+		NodeEvalOrder: finalEvalOrder, // Excludes inputs
 		Nodes:         nodesSlice,
 		NumNodes:      numNodes,
 	}
@@ -235,9 +208,107 @@ func CreateFeedForwardNetwork(g *neat.Genome) (*FeedForwardNetwork, error) {
 	return net, nil
 }
 
+// expressedConnection is an enabled connection gene as it enters the network.
+type expressedConnection struct {
+	key        neat.ConnectionKey
+	weight     float64
+	expression float64
+}
+
+// expressedConnections returns the enabled connections of g that are expressed in a network, with
+// their effective weights, sorted by target and then source node.
+func expressedConnections(g *neat.Genome) []expressedConnection {
+	conns := make([]expressedConnection, 0, len(g.Connections))
+	for key, gc := range g.Connections {
+		if gc.Enabled {
+			conns = append(conns, expressedConnection{key: key, weight: gc.Weight, expression: gc.Expression})
+		}
+	}
+	sort.Slice(conns, func(i, j int) bool {
+		if conns[i].key.OutNodeID != conns[j].key.OutNodeID {
+			return conns[i].key.OutNodeID < conns[j].key.OutNodeID
+		}
+		return conns[i].key.InNodeID < conns[j].key.InNodeID
+	})
+	if g.Config.ConnectionExpression {
+		// Sample mode drops unexpressed connections for this network instance, drawing from the
+		// genome's sample stream so seeded runs stay reproducible; expectation mode keeps every
+		// connection, scaled by its expression probability.
+		var rng *rand.Rand
+		if g.Config.ExpressionMode != "expectation" {
+			rng = g.SampleRand()
+		}
+		expressed := conns[:0]
+		for _, c := range conns {
+			if rng == nil {
+				c.weight *= c.expression
+			} else if rng.Float64() >= c.expression {
+				continue
+			}
+			expressed = append(expressed, c)
+		}
+		conns = expressed
+	}
+	return conns
+}
+
+// newNeuralNode looks up the activation and aggregation functions of a node gene.
+func newNeuralNode(g *neat.Genome, gn *neat.NodeGene) (neuralNode, error) {
+	actFn, err := neat.GetActivation(gn.Activation)
+	if err != nil {
+		return neuralNode{}, fmt.Errorf("failed to get activation function '%s' for node %d: %w", gn.Activation, gn.Key, err)
+	}
+	aggFn, err := neat.GetAggregation(gn.Aggregation)
+	if err != nil {
+		return neuralNode{}, fmt.Errorf("failed to get aggregation function '%s' for node %d: %w", gn.Aggregation, gn.Key, err)
+	}
+	var actParams []float64
+	if g.Config.EvolveActivationParam {
+		actParams = []float64{gn.ActivationParam}
+	}
+	return neuralNode{
+		OriginalKey:      gn.Key,
+		Bias:             gn.Bias,
+		Response:         gn.Response,
+		ActivationFn:     actFn,
+		ActivationName:   gn.Activation,
+		ActivationParams: actParams,
+		AggregationFn:    aggFn,
+		AggregationName:  gn.Aggregation,
+	}, nil
+}
+
+// insertSorted inserts v into the ascending slice s.
+func insertSorted(s []int, v int) []int {
+	i := sort.SearchInts(s, v)
+	s = append(s, 0)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
+
+// CachedFeedForwardNetwork returns the network of g from cache (typically Population.PhenotypeCache),
+// building it with CreateFeedForwardNetwork only when g is new or has changed. The returned network
+// is shared between calls, so don't change its Guard or Trace settings per evaluation. A nil cache, or
+// connection expression in sample mode (which needs a fresh sample per build), always builds a new network.
+func CachedFeedForwardNetwork(cache *neat.PhenotypeCache, g *neat.Genome) (*FeedForwardNetwork, error) {
+	if cache == nil || (g.Config.ConnectionExpression && g.Config.ExpressionMode != "expectation") {
+		return CreateFeedForwardNetwork(g)
+	}
+	net, err := cache.Get(g, func(g *neat.Genome) (any, error) {
+		return CreateFeedForwardNetwork(g)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return net.(*FeedForwardNetwork), nil
+}
+
 // Activate computes the network's output for a given slice of input values.
 // The input slice must match the number of input nodes configured.
 // This version uses slice indexing for potentially faster activation.
+// If Guard is set, NaN/Inf node outputs are reported and handled as the GuardMode describes.
+// If Trace is set, the computation of every node is recorded in LastTrace.
 func (net *FeedForwardNetwork) Activate(inputs []float64) ([]float64, error) {
 	if len(inputs) != len(net.InputIndices) {
 		return nil, fmt.Errorf("mismatch between input count (%d) and network input nodes (%d)", len(inputs), len(net.InputIndices))
@@ -251,10 +322,18 @@ func (net *FeedForwardNetwork) Activate(inputs []float64) ([]float64, error) {
 	for i, inputIndex := range net.InputIndices {
 		nodeValues[inputIndex] = inputs[i]
 	}
+	if net.BiasIndex >= 0 {
+		nodeValues[net.BiasIndex] = 1
+	}
 
 	// Reusable buffer for incoming connection values to reduce allocations.
 	var incInputsBuffer []float64
 
+	var trace *ActivationTrace
+	if net.Trace {
+		trace = &ActivationTrace{Inputs: append([]float64(nil), inputs...), Nodes: make([]NodeTrace, 0, len(net.NodeEvalOrder))}
+	}
+
 	// Activate nodes in topological order (indices, excluding inputs).
 	for _, nodeIndex := range net.NodeEvalOrder {
 		node := net.Nodes[nodeIndex] // Fast slice access
@@ -281,7 +360,17 @@ func (net *FeedForwardNetwork) Activate(inputs []float64) ([]float64, error) {
 		// Using direct float arithmetic is generally fast.
 		activationInput := aggregated + node.Bias
 		activationInput *= node.Response // Apply response scaling
-		outputValue := node.ActivationFn(activationInput)
+		outputValue := node.ActivationFn(activationInput, node.ActivationParams...)
+		if net.Guard != GuardOff {
+			var err error
+			if outputValue, err = net.guardOutput(&node, activationInput, outputValue); err != nil {
+				return nil, err
+			}
+		}
+
+		if trace != nil {
+			trace.Nodes = append(trace.Nodes, traceNode(&node, net.Nodes, nodeValues, aggregated, activationInput, outputValue))
+		}
 
 		// Store the computed value for this node (fast slice assignment).
 		nodeValues[nodeIndex] = outputValue
@@ -294,6 +383,10 @@ func (net *FeedForwardNetwork) Activate(inputs []float64) ([]float64, error) {
 		// Default float64 value (0.0) from nodeValues slice is correct here.
 		outputs[i] = nodeValues[outputIndex]
 	}
+	if trace != nil {
+		trace.Outputs = append([]float64(nil), outputs...)
+		net.LastTrace = trace
+	}
 
 	return outputs, nil
 }
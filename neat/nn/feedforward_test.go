@@ -0,0 +1,38 @@
+package nn
+
+import (
+	"testing"
+
+	"github.com/baldhumanity/neat-go/neat"
+)
+
+// grownGenomes returns n genomes of the test config, each mutated mutations times to grow it to a
+// mid-run size.
+func grownGenomes(tb testing.TB, n, mutations int) []*neat.Genome {
+	tb.Helper()
+	config, err := neat.LoadConfig("testdata/config.ini")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	genomes := make([]*neat.Genome, n)
+	for i := range genomes {
+		g := neat.NewGenome(i, &config.Genome)
+		g.ConfigureNew()
+		for j := 0; j < mutations; j++ {
+			g.Mutate()
+		}
+		genomes[i] = g
+	}
+	return genomes
+}
+
+func BenchmarkCreateFeedForwardNetwork(b *testing.B) {
+	genomes := grownGenomes(b, 50, 40)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := CreateFeedForwardNetwork(genomes[i%len(genomes)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package nn
+
+import (
+	"fmt"
+	"math"
+)
+
+// GuardMode selects how a network handles NaN or Inf node outputs during activation.
+type GuardMode int
+
+const (
+	GuardOff   GuardMode = iota // Non-finite values propagate unchanged (default, no overhead)
+	GuardZero                   // Non-finite outputs are replaced by 0
+	GuardClamp                  // +Inf/-Inf become ±GuardLimit and NaN becomes 0
+	GuardError                  // Activate fails with a *NonFiniteError
+)
+
+// DefaultGuardLimit is the clamp bound used by GuardClamp when GuardLimit is zero.
+const DefaultGuardLimit = 1e6
+
+// NonFiniteEvent describes a node that produced a NaN or Inf output.
+type NonFiniteEvent struct {
+	NodeKey    int     // Genome key of the node
+	Activation string  // Name of the node's activation function
+	Input      float64 // Value passed to the activation function (after bias and response)
+	Output     float64 // The non-finite output
+}
+
+// NonFiniteError is returned by Activate in GuardError mode.
+type NonFiniteError struct {
+	Event NonFiniteEvent
+}
+
+func (e *NonFiniteError) Error() string {
+	return fmt.Sprintf("node %d (%s) produced non-finite output %v for input %v",
+		e.Event.NodeKey, e.Event.Activation, e.Event.Output, e.Event.Input)
+}
+
+// guardOutput applies the network's guard mode to a node output. It returns the value to store
+// and, in GuardError mode, the error to return from Activate.
+func (net *FeedForwardNetwork) guardOutput(node *neuralNode, input, output float64) (float64, error) {
+	if !math.IsNaN(output) && !math.IsInf(output, 0) {
+		return output, nil
+	}
+	event := NonFiniteEvent{NodeKey: node.OriginalKey, Activation: node.ActivationName, Input: input, Output: output}
+	net.NonFiniteCount++
+	if net.OnNonFinite != nil {
+		net.OnNonFinite(event)
+	}
+
+	switch net.Guard {
+	case GuardZero:
+		return 0, nil
+	case GuardClamp:
+		limit := net.GuardLimit
+		if limit == 0 {
+			limit = DefaultGuardLimit
+		}
+		if math.IsNaN(output) {
+			return 0, nil
+		}
+		return math.Copysign(limit, output), nil
+	case GuardError:
+		return output, &NonFiniteError{Event: event}
+	}
+	return output, nil
+}
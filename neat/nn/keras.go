@@ -0,0 +1,219 @@
+package nn
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/baldhumanity/neat-go/neat"
+)
+
+// KerasModel is a feed-forward genome exported for Keras. Architecture is a functional model in
+// the format read by tf.keras.models.model_from_json; Weights maps each Dense layer name to its
+// [kernel, bias] arrays, to be passed to layer.set_weights. The model can then be saved or
+// converted to TensorFlow Lite with Keras' own tools:
+//
+//	model = tf.keras.models.model_from_json(open("model.json").read())
+//	for name, w in json.load(open("weights.json")).items():
+//	    model.get_layer(name).set_weights([np.array(a) for a in w])
+//
+// Each layer of the DenseNetwork becomes one Dense layer per activation function it uses, reading
+// the concatenation of the inputs and all earlier layers; a final linear layer selects the
+// outputs. Response, bias and the steepness of sigmoid and tanh are folded into the weights.
+type KerasModel struct {
+	Architecture []byte
+	Weights      []byte
+}
+
+// kerasActivation describes how a neat activation is expressed in Keras: the Keras name and the
+// factor applied to the activation input.
+type kerasActivation struct {
+	name  string
+	scale float64
+}
+
+// kerasActivationFor maps a node's activation to Keras. Activations without an exact Keras
+// equivalent are rejected.
+func kerasActivationFor(name string, params []float64) (kerasActivation, error) {
+	param := 1.0
+	if len(params) > 0 {
+		param = params[0]
+	}
+	switch name {
+	case "sigmoid":
+		return kerasActivation{"sigmoid", 4.9 * param}, nil
+	case "tanh":
+		return kerasActivation{"tanh", param}, nil
+	case "relu":
+		return kerasActivation{"relu", 1}, nil
+	case "identity":
+		return kerasActivation{"linear", 1}, nil
+	case "selu":
+		return kerasActivation{"selu", 1}, nil
+	case "elu", "swish", "silu":
+		if param != 1 {
+			return kerasActivation{}, fmt.Errorf("activation '%s' with parameter %g has no Keras equivalent", name, param)
+		}
+		if name == "silu" {
+			name = "swish"
+		}
+		return kerasActivation{name, 1}, nil
+	}
+	return kerasActivation{}, fmt.Errorf("activation '%s' has no Keras equivalent", name)
+}
+
+// kerasLayer is one layer entry of a Keras functional model config.
+type kerasLayer struct {
+	ClassName    string         `json:"class_name"`
+	Config       map[string]any `json:"config"`
+	Name         string         `json:"name"`
+	InboundNodes [][][]any      `json:"inbound_nodes"`
+}
+
+func kerasInbound(names ...string) [][][]any {
+	refs := make([][]any, len(names))
+	for i, name := range names {
+		refs[i] = []any{name, 0, 0, map[string]any{}}
+	}
+	return [][][]any{refs}
+}
+
+func kerasDense(name string, inbound string, units int, activation string, useBias bool) kerasLayer {
+	zeros := map[string]any{"class_name": "Zeros", "config": map[string]any{}}
+	return kerasLayer{
+		ClassName: "Dense",
+		Config: map[string]any{
+			"name": name, "trainable": true, "dtype": "float32", "units": units,
+			"activation": activation, "use_bias": useBias,
+			"kernel_initializer": zeros, "bias_initializer": zeros,
+			"kernel_regularizer": nil, "bias_regularizer": nil, "activity_regularizer": nil,
+			"kernel_constraint": nil, "bias_constraint": nil,
+		},
+		Name:         name,
+		InboundNodes: kerasInbound(inbound),
+	}
+}
+
+// ExportKeras converts a feed-forward genome into a Keras model. Genomes that can't be lowered
+// into a DenseNetwork (recurrent connections, aggregations other than sum) or that use activations
+// without a Keras equivalent are rejected with an error naming the problem.
+func ExportKeras(g *neat.Genome) (*KerasModel, error) {
+	dense, err := CreateDenseNetwork(g)
+	if err != nil {
+		return nil, fmt.Errorf("cannot export to Keras: %w", err)
+	}
+
+	layers := []kerasLayer{{
+		ClassName: "InputLayer",
+		Config: map[string]any{
+			"batch_input_shape": []any{nil, dense.NumInputs}, "dtype": "float32",
+			"sparse": false, "ragged": false, "name": "inputs",
+		},
+		Name:         "inputs",
+		InboundNodes: [][][]any{},
+	}}
+	weights := make(map[string][]any)
+
+	// current names the tensor holding the values computed so far; column c of it is the value
+	// vector entry columns[c].
+	current := "inputs"
+	columns := make([]int, dense.NumInputs)
+	for i := range columns {
+		columns[i] = i
+	}
+
+	for l := range dense.Layers {
+		layer := &dense.Layers[l]
+		// Split the layer by activation, keeping the order in which activations first appear.
+		var order []string
+		groups := make(map[string][]int) // Keras activation -> rows
+		scales := make([]float64, layer.Width)
+		for r, key := range layer.Keys {
+			act, err := kerasActivationFor(g.Nodes[key].Activation, layer.ActivationParams[r])
+			if err != nil {
+				return nil, fmt.Errorf("cannot export to Keras: node %d: %w", key, err)
+			}
+			if _, seen := groups[act.name]; !seen {
+				order = append(order, act.name)
+			}
+			groups[act.name] = append(groups[act.name], r)
+			scales[r] = layer.Response[r] * act.scale
+		}
+
+		inbound := []string{current}
+		var added []int
+		for _, activation := range order {
+			rows := groups[activation]
+			name := fmt.Sprintf("layer%d_%s", l+1, activation)
+			kernel := make([][]float64, len(columns))
+			for c, pos := range columns {
+				kernel[c] = make([]float64, len(rows))
+				for u, r := range rows {
+					kernel[c][u] = layer.Weights[r*layer.InputWidth+pos] * scales[r]
+				}
+			}
+			bias := make([]float64, len(rows))
+			for u, r := range rows {
+				bias[u] = layer.Bias[r] * scales[r]
+				added = append(added, layer.Start+r)
+			}
+			layers = append(layers, kerasDense(name, current, len(rows), activation, true))
+			weights[name] = []any{kernel, bias}
+			inbound = append(inbound, name)
+		}
+
+		name := fmt.Sprintf("values%d", l+1)
+		layers = append(layers, kerasLayer{
+			ClassName:    "Concatenate",
+			Config:       map[string]any{"name": name, "trainable": true, "dtype": "float32", "axis": -1},
+			Name:         name,
+			InboundNodes: kerasInbound(inbound...),
+		})
+		current = name
+		columns = append(columns, added...)
+	}
+
+	// Select the outputs from the value vector with a fixed 0/1 linear layer.
+	kernel := make([][]float64, len(columns))
+	for c, pos := range columns {
+		kernel[c] = make([]float64, len(dense.OutputIndices))
+		for o, out := range dense.OutputIndices {
+			if pos == out {
+				kernel[c][o] = 1
+			}
+		}
+	}
+	layers = append(layers, kerasDense("outputs", current, len(dense.OutputIndices), "linear", false))
+	weights["outputs"] = []any{kernel}
+
+	architecture, err := json.MarshalIndent(map[string]any{
+		"class_name": "Functional",
+		"config": map[string]any{
+			"name":          fmt.Sprintf("neat_genome_%d", g.Key),
+			"layers":        layers,
+			"input_layers":  [][]any{{"inputs", 0, 0}},
+			"output_layers": [][]any{{"outputs", 0, 0}},
+		},
+		"keras_version": "2.15.0",
+		"backend":       "tensorflow",
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	weightsJSON, err := json.Marshal(weights)
+	if err != nil {
+		return nil, err
+	}
+	return &KerasModel{Architecture: architecture, Weights: weightsJSON}, nil
+}
+
+// Save writes the architecture and weights JSON to the given files.
+func (m *KerasModel) Save(architecturePath, weightsPath string) error {
+	if err := os.WriteFile(architecturePath, m.Architecture, 0o644); err != nil {
+		return fmt.Errorf("failed to write Keras architecture: %w", err)
+	}
+	if err := os.WriteFile(weightsPath, m.Weights, 0o644); err != nil {
+		return fmt.Errorf("failed to write Keras weights: %w", err)
+	}
+	return nil
+}
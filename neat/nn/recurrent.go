@@ -0,0 +1,156 @@
+package nn
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/baldhumanity/neat-go/neat"
+)
+
+// RecurrentNetwork is a phenotype for genomes with cycles (feed_forward = False). Following
+// neat-python, every Activate call is one time step: each node is computed from the values all
+// nodes had after the previous step, so signals advance one connection per step and cycles carry
+// state from step to step. Call Reset between independent sequences, such as episodes.
+type RecurrentNetwork struct {
+	InputIndices  []int        // Slice indices for input nodes
+	OutputIndices []int        // Slice indices for output nodes
+	BiasIndex     int          // Slice index of the bias input, which is always 1 (-1 without bias_input)
+	Nodes         []neuralNode // All nodes in key order, includes inputs
+	NodeEvalOrder []int        // Indices of the non-input nodes
+
+	// Optional activation trace for debugging. Tracing is off by default.
+	Trace     bool             // Record an ActivationTrace on every Activate call
+	LastTrace *ActivationTrace // Trace of the most recent Activate call when Trace is set; inputs read previous-step values
+
+	values [2][]float64 // Node values after the previous and the current step
+	active int          // Index into values of the most recent step
+}
+
+// CreateRecurrentNetwork builds a recurrent network from a genome. It accepts feed-forward
+// genomes as well, which then need one step per layer for the inputs to reach the outputs.
+func CreateRecurrentNetwork(g *neat.Genome) (*RecurrentNetwork, error) {
+	conns := expressedConnections(g)
+
+	keys := make([]int, 0, len(g.Config.InputKeys)+len(g.Config.OutputKeys)+len(g.Nodes)+2*len(conns))
+	keys = append(keys, g.Config.InputKeys...)
+	keys = append(keys, g.Config.OutputKeys...)
+	for k := range g.Nodes {
+		keys = append(keys, k)
+	}
+	for _, c := range conns {
+		keys = append(keys, c.key.InNodeID, c.key.OutNodeID)
+	}
+	sort.Ints(keys)
+	numNodes := 0
+	for i, k := range keys {
+		if i == 0 || k != keys[numNodes-1] {
+			keys[numNodes] = k
+			numNodes++
+		}
+	}
+	keys = keys[:numNodes]
+	indexOf := func(key int) int { return sort.SearchInts(keys, key) }
+
+	net := &RecurrentNetwork{Nodes: make([]neuralNode, numNodes)}
+	isInput := make([]bool, numNodes)
+	net.InputIndices = make([]int, len(g.Config.InputKeys))
+	for i, key := range g.Config.InputKeys {
+		net.InputIndices[i] = indexOf(key)
+		isInput[net.InputIndices[i]] = true
+	}
+	net.OutputIndices = make([]int, len(g.Config.OutputKeys))
+	for i, key := range g.Config.OutputKeys {
+		net.OutputIndices[i] = indexOf(key)
+	}
+	net.BiasIndex = -1
+	if key, ok := g.Config.BiasInputKey(); ok {
+		net.BiasIndex = indexOf(key)
+		net.InputIndices = net.InputIndices[:len(net.InputIndices)-1] // The bias input is the last input key
+	}
+	for idx, key := range keys {
+		if isInput[idx] {
+			net.Nodes[idx] = neuralNode{OriginalKey: key}
+			continue
+		}
+		gn, ok := g.Nodes[key]
+		if !ok {
+			return nil, fmt.Errorf("cannot create RecurrentNetwork: node %d is connected but has no node gene", key)
+		}
+		node, err := newNeuralNode(g, gn)
+		if err != nil {
+			return nil, err
+		}
+		net.Nodes[idx] = node
+		net.NodeEvalOrder = append(net.NodeEvalOrder, idx)
+	}
+
+	inputs := make([]InputConnection, len(conns))
+	for start := 0; start < len(conns); {
+		target := indexOf(conns[start].key.OutNodeID)
+		end := start
+		for end < len(conns) && conns[end].key.OutNodeID == conns[start].key.OutNodeID {
+			inputs[end] = InputConnection{InputNodeIndex: indexOf(conns[end].key.InNodeID), Weight: conns[end].weight}
+			end++
+		}
+		net.Nodes[target].Inputs = inputs[start:end:end]
+		start = end
+	}
+
+	net.values[0] = make([]float64, numNodes)
+	net.values[1] = make([]float64, numNodes)
+	return net, nil
+}
+
+// Reset clears the state carried between steps.
+func (net *RecurrentNetwork) Reset() {
+	clear(net.values[0])
+	clear(net.values[1])
+}
+
+// Activate advances the network by one step with the given inputs and returns the output values.
+// If Trace is set, the computation of every node is recorded in LastTrace.
+func (net *RecurrentNetwork) Activate(inputs []float64) ([]float64, error) {
+	if len(inputs) != len(net.InputIndices) {
+		return nil, fmt.Errorf("mismatch between input count (%d) and network input nodes (%d)", len(inputs), len(net.InputIndices))
+	}
+	previous := net.values[net.active]
+	net.active = 1 - net.active
+	current := net.values[net.active]
+	for i, idx := range net.InputIndices {
+		previous[idx] = inputs[i]
+		current[idx] = inputs[i]
+	}
+	if net.BiasIndex >= 0 {
+		previous[net.BiasIndex] = 1
+		current[net.BiasIndex] = 1
+	}
+
+	var trace *ActivationTrace
+	if net.Trace {
+		trace = &ActivationTrace{Inputs: append([]float64(nil), inputs...), Nodes: make([]NodeTrace, 0, len(net.NodeEvalOrder))}
+	}
+	var incInputs []float64
+	for _, idx := range net.NodeEvalOrder {
+		node := &net.Nodes[idx]
+		incInputs = incInputs[:0]
+		for _, conn := range node.Inputs {
+			incInputs = append(incInputs, previous[conn.InputNodeIndex]*conn.Weight)
+		}
+		aggregated := node.AggregationFn(incInputs)
+		activationInput := (aggregated + node.Bias) * node.Response
+		current[idx] = node.ActivationFn(activationInput, node.ActivationParams...)
+		if trace != nil {
+			trace.Nodes = append(trace.Nodes, traceNode(node, net.Nodes, previous, aggregated, activationInput, current[idx]))
+		}
+	}
+
+	outputs := make([]float64, len(net.OutputIndices))
+	for i, idx := range net.OutputIndices {
+		outputs[i] = current[idx]
+	}
+	if trace != nil {
+		trace.Outputs = append([]float64(nil), outputs...)
+		net.LastTrace = trace
+	}
+	return outputs, nil
+}
@@ -0,0 +1,84 @@
+package nn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TraceInput is one weighted input of a node in an ActivationTrace.
+type TraceInput struct {
+	NodeKey  int     // Genome key of the source node
+	Value    float64 // Value of the source node that was read
+	Weight   float64 // Connection weight
+	Weighted float64 // Value * Weight, as passed to the aggregation function
+}
+
+// NodeTrace records how one node computed its output during an Activate call.
+type NodeTrace struct {
+	NodeKey       int
+	Aggregation   string       // Name of the node's aggregation function
+	Activation    string       // Name of the node's activation function
+	Inputs        []TraceInput // Incoming connections, in the order they were aggregated
+	Aggregated    float64      // Result of the aggregation function
+	Bias          float64
+	Response      float64
+	PreActivation float64 // (Aggregated + Bias) * Response, the activation function's input
+	Output        float64 // Stored node value, after the NaN/Inf guard if one is set
+}
+
+// ActivationTrace is a structured record of one Activate call, for debugging why a network
+// produces a given output. Nodes are listed in evaluation order.
+type ActivationTrace struct {
+	Inputs  []float64
+	Nodes   []NodeTrace
+	Outputs []float64
+}
+
+// Node returns the trace of the node with the given genome key, or nil if it wasn't evaluated.
+func (t *ActivationTrace) Node(key int) *NodeTrace {
+	for i := range t.Nodes {
+		if t.Nodes[i].NodeKey == key {
+			return &t.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// String formats the trace one node per line, with its weighted inputs.
+func (t *ActivationTrace) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "inputs %v\n", t.Inputs)
+	for _, n := range t.Nodes {
+		fmt.Fprintf(&b, "node %d: %s(", n.NodeKey, n.Aggregation)
+		for i, in := range n.Inputs {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%d:%.4g*%.4g", in.NodeKey, in.Value, in.Weight)
+		}
+		fmt.Fprintf(&b, ") = %.4g; %s((%.4g + %.4g) * %.4g) = %.4g -> %.4g\n",
+			n.Aggregated, n.Activation, n.Aggregated, n.Bias, n.Response, n.PreActivation, n.Output)
+	}
+	fmt.Fprintf(&b, "outputs %v\n", t.Outputs)
+	return b.String()
+}
+
+// traceNode records the computation of node, reading input values from values.
+func traceNode(node *neuralNode, nodes []neuralNode, values []float64, aggregated, preActivation, output float64) NodeTrace {
+	nt := NodeTrace{
+		NodeKey:       node.OriginalKey,
+		Aggregation:   node.AggregationName,
+		Activation:    node.ActivationName,
+		Inputs:        make([]TraceInput, len(node.Inputs)),
+		Aggregated:    aggregated,
+		Bias:          node.Bias,
+		Response:      node.Response,
+		PreActivation: preActivation,
+		Output:        output,
+	}
+	for i, conn := range node.Inputs {
+		v := values[conn.InputNodeIndex]
+		nt.Inputs[i] = TraceInput{NodeKey: nodes[conn.InputNodeIndex].OriginalKey, Value: v, Weight: conn.Weight, Weighted: v * conn.Weight}
+	}
+	return nt
+}
@@ -0,0 +1,193 @@
+package neat
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// NSGA-II support for multi-objective fitness, selected with strategy = nsga2.
+// Each genome's objectives are read from Genome.Fitnesses (all maximized). Genomes that did not
+// set Fitnesses fall back to their scalar Fitness as a single objective.
+
+// objectives returns the objective vector used for non-dominated sorting.
+func (g *Genome) objectives() []float64 {
+	if len(g.Fitnesses) > 0 {
+		return g.Fitnesses
+	}
+	return []float64{g.Fitness}
+}
+
+// Dominates reports whether objective vector a Pareto-dominates b (all objectives maximized):
+// a is no worse than b in every objective and strictly better in at least one.
+// Vectors of different length never dominate each other.
+func Dominates(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	strictlyBetter := false
+	for i := range a {
+		if a[i] < b[i] {
+			return false
+		}
+		if a[i] > b[i] {
+			strictlyBetter = true
+		}
+	}
+	return strictlyBetter
+}
+
+// NonDominatedSort partitions genomes into Pareto fronts. The first front contains the genomes
+// not dominated by any other genome, the second those dominated only by the first front, and so on.
+func NonDominatedSort(genomes []*Genome) [][]*Genome {
+	n := len(genomes)
+	dominatedBy := make([]int, n) // Number of genomes dominating genome i
+	dominates := make([][]int, n) // Indices of genomes dominated by genome i
+	objectives := make([][]float64, n)
+	for i, g := range genomes {
+		objectives[i] = g.objectives()
+	}
+
+	current := []int{}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			if Dominates(objectives[i], objectives[j]) {
+				dominates[i] = append(dominates[i], j)
+			} else if Dominates(objectives[j], objectives[i]) {
+				dominatedBy[i]++
+			}
+		}
+		if dominatedBy[i] == 0 {
+			current = append(current, i)
+		}
+	}
+
+	fronts := [][]*Genome{}
+	for len(current) > 0 {
+		front := make([]*Genome, len(current))
+		next := []int{}
+		for k, i := range current {
+			front[k] = genomes[i]
+			for _, j := range dominates[i] {
+				dominatedBy[j]--
+				if dominatedBy[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		fronts = append(fronts, front)
+		current = next
+	}
+	return fronts
+}
+
+// CrowdingDistance computes the NSGA-II crowding distance of each genome within a single front,
+// keyed by genome key. Boundary genomes for any objective get an infinite distance.
+func CrowdingDistance(front []*Genome) map[int]float64 {
+	distances := make(map[int]float64, len(front))
+	for _, g := range front {
+		distances[g.Key] = 0.0
+	}
+	if len(front) <= 2 {
+		for _, g := range front {
+			distances[g.Key] = math.Inf(1)
+		}
+		return distances
+	}
+
+	numObjectives := len(front[0].objectives())
+	sorted := make([]*Genome, len(front))
+	copy(sorted, front)
+	for m := 0; m < numObjectives; m++ {
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return objectiveAt(sorted[i], m) < objectiveAt(sorted[j], m)
+		})
+		minVal := objectiveAt(sorted[0], m)
+		maxVal := objectiveAt(sorted[len(sorted)-1], m)
+		distances[sorted[0].Key] = math.Inf(1)
+		distances[sorted[len(sorted)-1].Key] = math.Inf(1)
+		if maxVal == minVal {
+			continue
+		}
+		for i := 1; i < len(sorted)-1; i++ {
+			distances[sorted[i].Key] += (objectiveAt(sorted[i+1], m) - objectiveAt(sorted[i-1], m)) / (maxVal - minVal)
+		}
+	}
+	return distances
+}
+
+// objectiveAt returns objective m of g, or negative infinity if g has fewer objectives.
+func objectiveAt(g *Genome, m int) float64 {
+	obj := g.objectives()
+	if m >= len(obj) {
+		return math.Inf(-1)
+	}
+	return obj[m]
+}
+
+// nsga2Ranking holds the Pareto rank and crowding distance of each genome, keyed by genome key.
+type nsga2Ranking struct {
+	Rank     map[int]int
+	Crowding map[int]float64
+}
+
+// better implements the NSGA-II crowded-comparison operator.
+func (r *nsga2Ranking) better(a, b *Genome) bool {
+	if r.Rank[a.Key] != r.Rank[b.Key] {
+		return r.Rank[a.Key] < r.Rank[b.Key]
+	}
+	return r.Crowding[a.Key] > r.Crowding[b.Key]
+}
+
+// rankNSGA2 sorts genomes in place from best to worst by Pareto rank and crowding distance
+// and returns the ranking used, for later tournament selection.
+func rankNSGA2(genomes []*Genome) *nsga2Ranking {
+	ranking := &nsga2Ranking{
+		Rank:     make(map[int]int, len(genomes)),
+		Crowding: make(map[int]float64, len(genomes)),
+	}
+	for rank, front := range NonDominatedSort(genomes) {
+		for key, d := range CrowdingDistance(front) {
+			ranking.Rank[key] = rank
+			ranking.Crowding[key] = d
+		}
+	}
+	sort.SliceStable(genomes, func(i, j int) bool {
+		return ranking.better(genomes[i], genomes[j])
+	})
+	return ranking
+}
+
+// tournamentSelect picks the better of two randomly chosen candidates (binary tournament).
+func (r *nsga2Ranking) tournamentSelect(rng *rand.Rand, candidates []*Genome) *Genome {
+	a := candidates[rng.Intn(len(candidates))]
+	b := candidates[rng.Intn(len(candidates))]
+	if r.better(b, a) {
+		return b
+	}
+	return a
+}
+
+// NSGA2Reproduction is the NSGA-II variant of Reproduction, for multi-objective fitness: species
+// members are ranked by Pareto front and crowding distance instead of scalar fitness, and parents
+// are picked by binary tournament. Offspring allocation, elitism and the genome key counter and
+// ancestry are those of the embedded Reproduction. Population uses it around its Reproduction
+// when the config's strategy is nsga2.
+type NSGA2Reproduction struct {
+	*Reproduction
+}
+
+var _ ReproductionStrategy = (*NSGA2Reproduction)(nil)
+
+// Reproduce creates the next generation with NSGA-II selection within each species.
+func (r *NSGA2Reproduction) Reproduce(config *Config, speciesSet *SpeciesSet, popSize int, generation int) (map[int]*Genome, error) {
+	return r.reproduce(config, speciesSet, popSize, generation, nsga2Selection)
+}
+
+// nsga2Selection ranks members with rankNSGA2 and picks parents by binary tournament.
+func nsga2Selection(members []*Genome) func(rng *rand.Rand, parents []*Genome) *Genome {
+	return rankNSGA2(members).tournamentSelect
+}
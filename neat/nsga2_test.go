@@ -0,0 +1,41 @@
+package neat
+
+import "testing"
+
+func TestNSGA2StrategyFromConfig(t *testing.T) {
+	config := loadTestConfig(t)
+	config.Neat.PopSize = 30
+	config.Neat.NoFitnessTermination = true
+	config.Reproduction.Strategy = "nsga2"
+	p, err := NewPopulation(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetLogger(NopLogger{})
+	if _, ok := p.reproductionStrategy().(*NSGA2Reproduction); !ok {
+		t.Fatalf("strategy nsga2 reproduces with %T, want *NSGA2Reproduction", p.reproductionStrategy())
+	}
+	fitness := func(genomes map[int]*Genome) error {
+		for _, g := range genomes {
+			nodes, enabled := g.Size()
+			g.Fitnesses = []float64{float64(enabled), -float64(nodes)}
+			g.Fitness = float64(enabled)
+		}
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := p.RunGeneration(fitness); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for key := range p.Population {
+		if key >= p.Reproduction.NextGenomeKey {
+			t.Errorf("genome key %d not taken from the population's Reproduction (next key %d)", key, p.Reproduction.NextGenomeKey)
+		}
+	}
+
+	p.Config.Reproduction.Strategy = "default"
+	if p.reproductionStrategy() != ReproductionStrategy(p.Reproduction) {
+		t.Errorf("strategy default reproduces with %T, want the population's Reproduction", p.reproductionStrategy())
+	}
+}
@@ -0,0 +1,150 @@
+package neat_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/baldhumanity/neat-go/neat"
+	"github.com/baldhumanity/neat-go/neat/nn"
+)
+
+// The operator benchmarks measure the library's own operations on a population of evolved
+// genomes, so their cost can be tracked apart from any fitness function. They live in the
+// external test package to build networks with package nn.
+
+const (
+	fixtureInputs    = 8   // num_inputs of testdata/config.ini
+	fixturePopSize   = 150 // pop_size of testdata/config.ini
+	fixtureMutations = 40  // Mutations applied to each genome, growing it to a mid-run size
+)
+
+// fixture is the population the operator benchmarks work on.
+type fixture struct {
+	config  *neat.Config
+	genomes []*neat.Genome
+}
+
+// newFixture creates the benchmark population from testdata/config.ini.
+func newFixture(b *testing.B) *fixture {
+	b.Helper()
+	config, err := neat.LoadConfig("testdata/config.ini")
+	if err != nil {
+		b.Fatal(err)
+	}
+	f := &fixture{config: config}
+	for key := 0; key < fixturePopSize; key++ {
+		g := neat.NewGenome(key, &config.Genome)
+		g.ConfigureNew()
+		for i := 0; i < fixtureMutations; i++ {
+			g.Mutate()
+		}
+		g.Fitness = rand.Float64()
+		f.genomes = append(f.genomes, g)
+	}
+	return f
+}
+
+// population returns the fixture genomes keyed by genome key.
+func (f *fixture) population() map[int]*neat.Genome {
+	population := make(map[int]*neat.Genome, len(f.genomes))
+	for _, g := range f.genomes {
+		population[g.Key] = g
+	}
+	return population
+}
+
+// BenchmarkMutation measures Genome.Mutate on copies of the fixture genomes.
+func BenchmarkMutation(b *testing.B) {
+	f := newFixture(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; {
+		// Copy a batch outside the timer, so the genomes don't keep growing across iterations.
+		b.StopTimer()
+		batch := make([]*neat.Genome, min(len(f.genomes), b.N-i))
+		for j := range batch {
+			batch[j] = f.genomes[j].Copy()
+		}
+		b.StartTimer()
+		for _, g := range batch {
+			g.Mutate()
+		}
+		i += len(batch)
+	}
+}
+
+// BenchmarkCrossover measures Genome.ConfigureCrossover of pairs of fixture genomes.
+func BenchmarkCrossover(b *testing.B) {
+	f := newFixture(b)
+	n := len(f.genomes)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		child := neat.NewGenome(n+i, &f.config.Genome)
+		child.ConfigureCrossover(f.genomes[i%n], f.genomes[(i+1)%n])
+	}
+}
+
+// BenchmarkDistance measures Genome.Distance between pairs of fixture genomes.
+func BenchmarkDistance(b *testing.B) {
+	f := newFixture(b)
+	n := len(f.genomes)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.genomes[i%n].Distance(f.genomes[(i+7)%n])
+	}
+}
+
+// BenchmarkSpeciation measures SpeciesSet.Speciate of the fixture population, continuing from the
+// species of the previous iteration as it does between generations.
+func BenchmarkSpeciation(b *testing.B) {
+	f := newFixture(b)
+	population := f.population()
+	species := neat.NewSpeciesSet(&f.config.SpeciesSet)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := species.Speciate(f.config, population, i+1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkNetworkBuild measures nn.CreateFeedForwardNetwork of the fixture genomes.
+func BenchmarkNetworkBuild(b *testing.B) {
+	f := newFixture(b)
+	n := len(f.genomes)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := nn.CreateFeedForwardNetwork(f.genomes[i%n]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkActivation measures FeedForwardNetwork.Activate of networks built from the fixture
+// genomes.
+func BenchmarkActivation(b *testing.B) {
+	f := newFixture(b)
+	networks := make([]*nn.FeedForwardNetwork, len(f.genomes))
+	for i, g := range f.genomes {
+		net, err := nn.CreateFeedForwardNetwork(g)
+		if err != nil {
+			b.Fatalf("genome %d: %v", g.Key, err)
+		}
+		networks[i] = net
+	}
+	inputs := make([]float64, fixtureInputs)
+	for i := range inputs {
+		inputs[i] = rand.Float64()
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := networks[i%len(networks)].Activate(inputs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package neat
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// genomeRevisions hands out revision numbers; a single process-wide counter means no two
+// different gene sets ever share a revision, even across genomes with the same key.
+var genomeRevisions atomic.Uint64
+
+// Revision identifies the current gene content of the genome. It changes whenever the genes are
+// changed by ConfigureNew, ConfigureCrossover or Mutate; Copy keeps it, since the copy has the
+// same genes. Revisions are runtime-only: genomes loaded from a checkpoint start at revision 0.
+func (g *Genome) Revision() uint64 {
+	return g.revision
+}
+
+// MarkModified gives the genome a new revision. Call it after editing genes directly (rather than
+// through Mutate) so cached phenotypes built from the old genes are not reused.
+func (g *Genome) MarkModified() {
+	g.revision = genomeRevisions.Add(1)
+}
+
+// SampleRand returns a random source for sampling a phenotype built from the genome, such as the
+// connections expressed in sample mode of connection_expression. Each call returns a new source,
+// the next of the genome's stream: with a master seed ([NEAT] seed) the stream is derived from
+// the seed and the genome key alone, so seeded runs build the same phenotypes however genomes are
+// scheduled; without one, sources are seeded from the global generator. Copies (e.g. elites)
+// continue the stream of the original. Like Fitness, it must only be used by the goroutine that
+// owns the genome.
+func (g *Genome) SampleRand() *rand.Rand {
+	g.samples++
+	if g.Config == nil || g.Config.seed == 0 {
+		return rand.New(&splitMixSource{state: rand.Uint64()})
+	}
+	h := splitMix64(uint64(g.Config.seed))
+	h = splitMix64(h ^ uint64(g.Key))
+	return rand.New(&splitMixSource{state: splitMix64(h ^ g.samples)})
+}
+
+// splitMixSource is a rand.Source64 generating the SplitMix64 sequence. Unlike rand.NewSource,
+// it is cheap enough to create for every phenotype.
+type splitMixSource struct {
+	state uint64
+}
+
+func (s *splitMixSource) Uint64() uint64 {
+	x := splitMix64(s.state)
+	s.state += 0x9e3779b97f4a7c15
+	return x
+}
+
+func (s *splitMixSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+func (s *splitMixSource) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// PhenotypeCache keeps the phenotype (e.g. network) built from each genome so genomes that carry
+// over unchanged between generations, such as elites, are not rebuilt every generation. Entries
+// are keyed by genome key and are only reused while the genome's Revision is unchanged.
+// Cached phenotypes are shared, so they must not hold per-evaluation state that leaks between
+// evaluations. It is safe for concurrent use.
+type PhenotypeCache struct {
+	Hits   int // Phenotypes returned from the cache
+	Misses int // Phenotypes that had to be built
+
+	mu      sync.Mutex
+	entries map[int]cachedPhenotype
+}
+
+type cachedPhenotype struct {
+	revision  uint64
+	phenotype any
+}
+
+// NewPhenotypeCache creates an empty phenotype cache.
+func NewPhenotypeCache() *PhenotypeCache {
+	return &PhenotypeCache{entries: make(map[int]cachedPhenotype)}
+}
+
+// Get returns the cached phenotype of g, calling build to create it when g is not cached or has
+// changed since it was cached. Build errors are returned and not cached.
+func (c *PhenotypeCache) Get(g *Genome, build func(*Genome) (any, error)) (any, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[g.Key]
+	if ok && entry.revision == g.revision {
+		c.Hits++
+		c.mu.Unlock()
+		return entry.phenotype, nil
+	}
+	c.Misses++
+	c.mu.Unlock()
+
+	// Build outside the lock so concurrent evaluations of different genomes don't serialize.
+	phenotype, err := build(g)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[g.Key] = cachedPhenotype{revision: g.revision, phenotype: phenotype}
+	c.mu.Unlock()
+	return phenotype, nil
+}
+
+// Prune drops the phenotypes of genomes that are no longer in population.
+func (c *PhenotypeCache) Prune(population map[int]*Genome) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if _, ok := population[key]; !ok {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Len returns the number of cached phenotypes.
+func (c *PhenotypeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Clear removes all cached phenotypes.
+func (c *PhenotypeCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[int]cachedPhenotype)
+}
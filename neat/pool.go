@@ -0,0 +1,79 @@
+package neat
+
+import "sync"
+
+// Genes and genomes are allocated from free lists so that, with gene_pooling enabled, the genes of
+// discarded genomes are reused by the next generation instead of becoming garbage. Objects only
+// return to the pools through Genome.Release; everything allocated here is otherwise an ordinary
+// heap object.
+var (
+	genomePool         = sync.Pool{New: func() any { return new(Genome) }}
+	nodeGenePool       = sync.Pool{New: func() any { return new(NodeGene) }}
+	connectionGenePool = sync.Pool{New: func() any { return new(ConnectionGene) }}
+)
+
+// allocNodeGene returns a zeroed NodeGene, reusing a released one if available.
+func allocNodeGene() *NodeGene {
+	return nodeGenePool.Get().(*NodeGene)
+}
+
+// allocConnectionGene returns a zeroed ConnectionGene, reusing a released one if available.
+func allocConnectionGene() *ConnectionGene {
+	return connectionGenePool.Get().(*ConnectionGene)
+}
+
+// Release returns the genome and all its genes to the allocation pools. The genome must not be
+// used afterwards, and nothing else may still reference it or its genes: only release genomes
+// you created and are certain are unreachable, such as scratch copies. Population does this
+// itself for discarded generations when gene_pooling is enabled.
+func (g *Genome) Release() {
+	for k, ng := range g.Nodes {
+		*ng = NodeGene{}
+		nodeGenePool.Put(ng)
+		delete(g.Nodes, k)
+	}
+	for k, cg := range g.Connections {
+		*cg = ConnectionGene{}
+		connectionGenePool.Put(cg)
+		delete(g.Connections, k)
+	}
+	// Keep the emptied maps and index: their storage is reused by the next genome taken from the pool.
+	clear(g.sortedConns)
+	*g = Genome{Nodes: g.Nodes, Connections: g.Connections, sortedConns: g.sortedConns[:0]}
+	genomePool.Put(g)
+}
+
+// allocGenome returns a genome with empty gene maps, reusing a released one if available.
+func allocGenome() *Genome {
+	g := genomePool.Get().(*Genome)
+	if g.Nodes == nil {
+		g.Nodes = make(map[int]*NodeGene)
+	}
+	if g.Connections == nil {
+		g.Connections = make(map[ConnectionKey]*ConnectionGene)
+	}
+	return g
+}
+
+// releaseRetired releases the genomes of the previous generation once speciation has moved every
+// species onto the current one. Genomes still referenced by the population or a species are kept.
+func (p *Population) releaseRetired() {
+	if len(p.retired) == 0 {
+		return
+	}
+	inUse := make(map[*Genome]bool, len(p.Population))
+	for _, g := range p.Population {
+		inUse[g] = true
+	}
+	for _, sp := range p.SpeciesSet.Species {
+		if sp.Representative != nil {
+			inUse[sp.Representative] = true
+		}
+	}
+	for _, g := range p.retired {
+		if !inUse[g] {
+			g.Release()
+		}
+	}
+	p.retired = nil
+}
@@ -0,0 +1,78 @@
+package neat
+
+import (
+	"fmt"
+	"testing"
+)
+
+// grownGenome returns a genome of the test config mutated mutations times, growing it to a
+// mid-run size.
+func grownGenome(tb testing.TB, config *Config, key, mutations int) *Genome {
+	tb.Helper()
+	g := NewGenome(key, &config.Genome)
+	g.ConfigureNew()
+	for i := 0; i < mutations; i++ {
+		g.Mutate()
+	}
+	return g
+}
+
+// loadTestConfig loads testdata/config.ini.
+func loadTestConfig(tb testing.TB) *Config {
+	tb.Helper()
+	config, err := LoadConfig("testdata/config.ini")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return config
+}
+
+var genomeSink *Genome
+
+// BenchmarkGenomeAlloc compares copying a genome with and without returning the copy to the
+// pools, and whole generations with gene_pooling on and off.
+func BenchmarkGenomeAlloc(b *testing.B) {
+	g := grownGenome(b, loadTestConfig(b), 0, 40)
+	b.Run("copy/pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			g.Copy().Release()
+		}
+	})
+	b.Run("copy/unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			genomeSink = g.Copy()
+		}
+	})
+	for _, pooling := range []bool{true, false} {
+		b.Run(fmt.Sprintf("generation/gene_pooling=%v", pooling), func(b *testing.B) {
+			fitness := func(genomes map[int]*Genome) error {
+				for _, g := range genomes {
+					g.Fitness = float64(len(g.Connections))
+				}
+				return nil
+			}
+			var p *Population
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if i%20 == 0 {
+					// Start over regularly, outside the timer, so the genomes don't keep growing.
+					b.StopTimer()
+					config := loadTestConfig(b)
+					config.Neat.NoFitnessTermination = true
+					config.Neat.GenePooling = pooling
+					var err error
+					if p, err = NewPopulation(config); err != nil {
+						b.Fatal(err)
+					}
+					p.SetLogger(NopLogger{})
+					b.StartTimer()
+				}
+				if _, err := p.RunGeneration(fitness); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -3,10 +3,13 @@ package neat
 import (
 	// "compress/gzip" // Moved to checkpoint.go
 	// "encoding/gob" // Moved to checkpoint.go
+	"errors"
 	"fmt"
 	// "math/rand" // Moved to checkpoint.go
 	// "os" // Moved to checkpoint.go
 	"math"
+	"sync"
+	"sync/atomic"
 	"time" // Added import
 	// Added missing sort import
 )
@@ -22,10 +25,29 @@ type Population struct {
 	Population   map[int]*Genome // Current generation of genomes (maps genome key -> genome)
 	SpeciesSet   *SpeciesSet
 	Reproduction *Reproduction
-	Stagnation   *Stagnation
+	Stagnation   Stagnation
 	Generation   int
-	BestGenome   *Genome // Best genome found so far
-	// TODO: Add Reporters
+	BestGenome   *Genome              // Best genome found so far
+	HallOfFame   []*Genome            // All-time fittest genomes, best first (up to hall_of_fame_size)
+	Statistics   *Statistics          // Optional per-generation statistics collector (nil disables recording)
+	Reporters    *ReporterSet         // Progress reporters notified during RunGeneration
+	Logger       Logger               // Destination for diagnostic output (nil = DefaultLogger); change it with SetLogger
+	Curriculum   *CurriculumScheduler // Optional staged fitness evaluation; set with SetCurriculum
+	Ratings      *Ratings             // Optional competitive ratings, carried over between generations and saved in checkpoints
+
+	EvaluationCache *EvaluationCache // Skips evaluating genomes identical to recently evaluated ones (nil = disabled, see evaluation_cache_size)
+	PhenotypeCache  *PhenotypeCache  // Networks built from genomes, reused while a genome is unchanged (nil = disabled, see phenotype_cache)
+
+	generating         atomic.Bool      // Set while RunGeneration runs, to reject overlapping calls
+	running            sync.Mutex       // Held for the duration of RunGeneration; see Inspect
+	hooks              generationHooks  // Callbacks registered with OnGenerationStart, OnPostEvaluate, ...
+	retired            []*Genome        // Previous generation, released after the next speciation (gene_pooling)
+	restoredCurriculum *CurriculumState // Curriculum progress loaded from a checkpoint, adopted by SetCurriculum
+	configChanges      []ConfigChange   // Changes made with UpdateConfig, replayed when a checkpoint is loaded
+	timings            PhaseTimings     // Phase times of the current generation, see Timings
+
+	strategy  ReproductionStrategy // Creates the next generation (nil = Reproduction); see SetReproductionStrategy
+	speciator Speciator            // Divides the population into species (nil = speciation_method); see SetSpeciator
 }
 
 // NewPopulation creates a new Population instance.
@@ -36,114 +58,321 @@ func NewPopulation(config *Config) (*Population, error) {
 		return nil, fmt.Errorf("failed to create stagnation manager: %w", err)
 	}
 
+	reporters := NewReporterSet()
+	stagnation.Reporters = reporters
+
+	config.Genome.seed = config.Neat.Seed // The seed may have been set after LoadConfig
 	reproduction := NewReproduction(&config.Reproduction, stagnation)
+	reproduction.Reporters = reporters
+	reproduction.NextGenomeKey += config.Neat.KeyOffset
 	initialPopulation := reproduction.CreateNewPopulation(&config.Genome, config.Neat.PopSize)
-	speciesSet := NewSpeciesSet(&config.SpeciesSet)
 
 	p := &Population{
 		Config:       config,
 		Population:   initialPopulation,
-		SpeciesSet:   speciesSet,
 		Reproduction: reproduction,
 		Stagnation:   stagnation,
 		Generation:   0,
 		BestGenome:   nil,
+		Reporters:    reporters,
+	}
+	p.SpeciesSet = p.newSpeciesSet()
+	if config.Neat.EvaluationCacheSize > 0 {
+		p.EvaluationCache = NewEvaluationCache(config.Neat.EvaluationCacheSize)
+	}
+	if config.Neat.PhenotypeCache {
+		p.PhenotypeCache = NewPhenotypeCache()
 	}
 	return p, nil
 }
 
+// newSpeciesSet creates a species set whose keys start after config.Neat.KeyOffset.
+func (p *Population) newSpeciesSet() *SpeciesSet {
+	speciesSet := NewSpeciesSet(&p.Config.SpeciesSet)
+	speciesSet.Indexer += p.Config.Neat.KeyOffset
+	speciesSet.logger = p.Logger
+	return speciesSet
+}
+
+// SetLogger sets the destination for the population's diagnostic output, including messages from
+// speciation, reproduction and genome initialization. Pass NopLogger{} to silence the library,
+// or a *slog.Logger for structured output.
+func (p *Population) SetLogger(logger Logger) {
+	p.Logger = logger
+	p.Config.Genome.logger = logger
+	if p.SpeciesSet != nil {
+		p.SpeciesSet.logger = logger
+	}
+	if p.Reproduction != nil {
+		p.Reproduction.logger = logger
+	}
+}
+
+func (p *Population) log() Logger {
+	return loggerOrDefault(p.Logger)
+}
+
+// SetStagnation replaces the stagnation scheme used by the population's reproduction step.
+// Checkpoints always restore the default scheme, so call this again after LoadCheckpoint
+// when using a custom implementation.
+func (p *Population) SetStagnation(stagnation Stagnation) {
+	p.Stagnation = stagnation
+	p.Reproduction.Stagnation = stagnation
+}
+
+// SetSpeciationPolicy installs a policy adjusting the compatibility threshold per species; nil
+// restores the uniform threshold. Checkpoints don't save the policy, so call this again after
+// LoadCheckpoint.
+func (p *Population) SetSpeciationPolicy(policy SpeciationPolicy) {
+	p.SpeciesSet.SetPolicy(policy)
+}
+
+// SetSpeciator replaces the algorithm dividing the population into species; nil restores the one
+// selected by speciation_method. Checkpoints don't save the speciator, so call this again after
+// LoadCheckpoint.
+func (p *Population) SetSpeciator(speciator Speciator) {
+	p.speciator = speciator
+}
+
+// currentSpeciator returns the speciator dividing the population into species.
+func (p *Population) currentSpeciator() Speciator {
+	if p.speciator != nil {
+		return p.speciator
+	}
+	return configuredSpeciator(&p.Config.SpeciesSet)
+}
+
+// SetReproductionStrategy replaces the strategy creating each next generation; nil restores the
+// one selected by the config's strategy, Population.Reproduction or an NSGA2Reproduction around
+// it. Population.Reproduction keeps the run's genome key counter and ancestry either way. Before
+// the first generation, the initial population is also recreated with the strategy's CreateNew.
+// Checkpoints don't save the strategy, so call this again after LoadCheckpoint.
+func (p *Population) SetReproductionStrategy(strategy ReproductionStrategy) {
+	p.strategy = strategy
+	if strategy != nil && p.Generation == 0 {
+		p.Population = strategy.CreateNew(&p.Config.Genome, p.Config.Neat.PopSize)
+	}
+}
+
+// SetGenomeFactory replaces the factory building and varying the population's genomes (see
+// GenomeFactory); nil restores DefaultGenomeFactory. Before the first generation, the initial
+// population is recreated with the new factory. Checkpoints don't save the factory, so call this
+// again after LoadCheckpoint.
+func (p *Population) SetGenomeFactory(factory GenomeFactory) {
+	p.Config.Genome.SetGenomeFactory(factory)
+	if p.Generation == 0 {
+		p.Population = p.reproductionStrategy().CreateNew(&p.Config.Genome, p.Config.Neat.PopSize)
+	}
+}
+
+// reproductionStrategy returns the strategy creating the population's genomes.
+func (p *Population) reproductionStrategy() ReproductionStrategy {
+	if p.strategy != nil {
+		return p.strategy
+	}
+	if p.Config.Reproduction.Strategy == "nsga2" {
+		return &NSGA2Reproduction{Reproduction: p.Reproduction}
+	}
+	return p.Reproduction
+}
+
+// AddReporter registers a reporter to be notified of the population's progress.
+func (p *Population) AddReporter(reporter Reporter) {
+	p.Reporters.Add(reporter)
+}
+
 // RunGeneration executes a single generation of the NEAT algorithm.
 // Returns the winning genome if the fitness threshold is met this generation, otherwise nil.
+// Generations of one population can't overlap: a call made while another is running fails.
+// The wall time of each phase is recorded in Timings and Statistics and passed to the reporters.
+// With profile_labels, CPU profiles label the phases with "neat_phase" = "evaluation",
+// "speciation" or "reproduction", e.g. for go tool pprof -tagfocus neat_phase=speciation; the
+// phases replace the caller's pprof labels.
 func (p *Population) RunGeneration(fitnessFunc FitnessFunc) (*Genome, error) {
+	if !p.generating.CompareAndSwap(false, true) {
+		return nil, fmt.Errorf("RunGeneration called while another generation of this population is running")
+	}
+	defer p.generating.Store(false)
+	p.running.Lock()
+	defer p.running.Unlock()
+
 	p.Generation++
 	genStartTime := time.Now() // Need to import "time"
-	fmt.Printf("****** Generation %d ******\n", p.Generation)
+	p.timings = PhaseTimings{}
+	p.log().Info("Generation started", "generation", p.Generation)
+	if err := p.applySchedules(); err != nil {
+		return nil, fmt.Errorf("parameter schedule failed in generation %d: %w", p.Generation, err)
+	}
+	p.Reporters.StartGeneration(p.Generation)
+	if err := p.runHooks("generation start", p.hooks.generationStart); err != nil {
+		return nil, err
+	}
 
 	// 1. Evaluate Fitness
-	fmt.Println(" Evaluating fitness...")
-	if err := fitnessFunc(p.Population); err != nil {
+	// With an evaluation budget, only part of the population is evaluated and the rest inherit parental fitness.
+	evaluate, inherited := p.budgetedEvaluationSet()
+	if len(evaluate) < len(p.Population) {
+		p.log().Debug("Evaluating fitness (evaluation budget)", "evaluated", len(evaluate), "population", len(p.Population))
+	} else {
+		p.log().Debug("Evaluating fitness")
+	}
+	if p.Config.Neat.ComplexityObjective {
+		// The complexity objective is appended after evaluation, so start each evaluation from a clean vector.
+		for _, g := range evaluate {
+			g.Fitnesses = nil
+		}
+	}
+	if p.Curriculum != nil {
+		fitnessFunc = p.Curriculum.ActiveStage().Fitness
+	}
+	err := p.runPhase(PhaseEvaluation, func() error { return p.evaluateFitness(evaluate, fitnessFunc) })
+	if err != nil {
 		return nil, fmt.Errorf("fitness evaluation failed in generation %d: %w", p.Generation, err)
 	}
+	applyComplexityPressure(&p.Config.Neat, evaluate)
+	for key, fitness := range inherited {
+		p.Population[key].Fitness = fitness
+	}
+	sizes := MeasureGenomeSizes(p.Population)
+	if p.Statistics != nil {
+		p.Statistics.recordFitness(p.Generation, p.Population)
+		p.Statistics.recordSizes(p.Generation, sizes)
+	}
+	p.log().Debug("Genome sizes", "nodes", sizes.Nodes.Mean, "enabled_connections", sizes.EnabledConnections.Mean,
+		"disabled_connections", sizes.DisabledConnections.Mean, "bytes", sizes.Bytes)
+	p.Reporters.GenomeSizes(p.Generation, sizes)
 
 	// 2. Track Best Genome & Check Termination Condition
 	currentBest := p.findBestGenome()
 	bestUpdated := false
 	if p.BestGenome == nil || (currentBest != nil && currentBest.Fitness > p.BestGenome.Fitness) {
-		p.BestGenome = currentBest
+		// Snapshot the champion so later re-evaluation or mutation of the population can't change it.
+		if currentBest != nil {
+			p.BestGenome = currentBest.Copy()
+		}
 		bestUpdated = true
 		// Print only if it's truly a new overall best
 		if bestUpdated && p.BestGenome != nil {
-			fmt.Printf(" New best genome found! Key: %d, Fitness: %.4f\n", p.BestGenome.Key, p.BestGenome.Fitness)
+			p.log().Info("New best genome found", "key", p.BestGenome.Key, "fitness", p.BestGenome.Fitness)
 		}
 	}
 
+	p.updateHallOfFame()
+
 	if currentBest != nil {
-		fmt.Printf(" Best of generation %d: Key: %d, Fitness: %.4f\n", p.Generation, currentBest.Key, currentBest.Fitness)
+		p.log().Info("Best of generation", "generation", p.Generation, "key", currentBest.Key, "fitness", currentBest.Fitness)
 	}
-
-	// Check fitness threshold termination
-	if !p.Config.Neat.NoFitnessTermination && p.BestGenome != nil {
-		if p.BestGenome.Fitness >= p.Config.Neat.FitnessThreshold {
-			// Don't print threshold met here, let the main loop handle it.
-			return p.BestGenome, nil // Return winner
-		}
+	p.Reporters.PostEvaluate(p.Config, p.Population, p.SpeciesSet, currentBest)
+	p.advanceCurriculum()
+	if err := p.runHooks("post-evaluate", p.hooks.postEvaluate); err != nil {
+		return p.BestGenome, err
 	}
 
-	// Check for empty population (extinction before reproduction)
-	if len(p.Population) == 0 {
-		fmt.Println("Population extinct before speciation/reproduction.")
-		if p.Config.Neat.ResetOnExtinction {
-			fmt.Println("Resetting population due to extinction.")
-			p.Population = p.Reproduction.CreateNewPopulation(&p.Config.Genome, p.Config.Neat.PopSize)
-			p.SpeciesSet = NewSpeciesSet(&p.Config.SpeciesSet) // Reset species too
-			// Continue to next generation is handled by the main loop structure
-			return nil, nil // No winner yet, but continue
-		} else {
-			// Return current best (which might be nil or from previous gen) + error
-			return p.BestGenome, fmt.Errorf("population extinct in generation %d", p.Generation)
-		}
+	// Check fitness threshold termination (only in the final stage of a curriculum)
+	if p.thresholdMet() {
+		// Don't print threshold met here, let the main loop handle it.
+		p.Reporters.FoundSolution(p.Config, p.Generation, p.BestGenome)
+		p.finishGeneration(genStartTime)
+		return p.BestGenome, nil // Return winner
 	}
 
 	// 3. Speciate
-	fmt.Println(" Speciating...")
-	if err := p.SpeciesSet.Speciate(p.Config, p.Population, p.Generation); err != nil {
+	p.log().Debug("Speciating")
+	p.SpeciesSet.ancestors = p.Reproduction.Ancestors
+	var speciation SpeciationResult
+	err = p.runPhase(PhaseSpeciation, func() error {
+		var err error
+		speciation, err = p.currentSpeciator().Speciate(p.Config, p.SpeciesSet, p.Population, p.Generation)
+		return err
+	})
+	if err != nil {
 		// Return current best + error
 		return p.BestGenome, fmt.Errorf("speciation failed in generation %d: %w", p.Generation, err)
 	}
-	fmt.Printf(" Population divided into %d species.\n", len(p.SpeciesSet.Species))
+	p.log().Info("Population speciated", "species", speciation.Species,
+		"created", len(speciation.Created), "died", len(speciation.Died))
+	p.log().Debug("Genetic distance", "mean", speciation.MeanDistance, "stdev", speciation.StdevDistance,
+		"cache_hit_rate", speciation.CacheHitRate())
+	p.Reporters.Speciated(p.Generation, speciation)
+	for _, s := range p.SpeciesSet.Species {
+		s.updateChampion(p.Generation)
+	}
+	p.releaseRetired() // The species now only reference the current generation
+	p.pruneSplitOrigins()
+	if n := p.Config.SpeciesSet.ReindexInterval; n > 0 && p.Generation%n == 0 {
+		p.ReindexSpecies()
+	}
+	if p.Statistics != nil {
+		p.Statistics.recordSpecies(p.Generation, p.SpeciesSet)
+	}
+	if err := p.runHooks("post-speciate", p.hooks.postSpeciate); err != nil {
+		return p.BestGenome, err
+	}
 
 	// 4. Reproduce
-	fmt.Println(" Reproducing...")
-	newPopulation, err := p.Reproduction.Reproduce(p.Config, p.SpeciesSet, p.Config.Neat.PopSize, p.Generation)
+	p.log().Debug("Reproducing")
+	p.Reproduction.reseedFrom = p.extinctionSeeds()
+	var newPopulation map[int]*Genome
+	err = p.runPhase(PhaseReproduction, func() (err error) {
+		newPopulation, err = p.reproductionStrategy().Reproduce(p.Config, p.SpeciesSet, p.Config.Neat.PopSize, p.Generation)
+		return err
+	})
+	if errors.Is(err, ErrExtinction) {
+		return p.BestGenome, err
+	}
 	if err != nil {
 		// Return current best + error
 		return p.BestGenome, fmt.Errorf("reproduction failed in generation %d: %w", p.Generation, err)
 	}
-
-	// Check for extinction after reproduction
-	if len(newPopulation) == 0 {
-		fmt.Println("Population extinct after reproduction.")
-		if p.Config.Neat.ResetOnExtinction {
-			fmt.Println("Resetting population due to extinction.")
-			p.Population = p.Reproduction.CreateNewPopulation(&p.Config.Genome, p.Config.Neat.PopSize)
-			p.SpeciesSet = NewSpeciesSet(&p.Config.SpeciesSet) // Reset species too
-			return nil, nil                                    // No winner yet, but continue
-		} else {
-			// Return current best + error
-			return p.BestGenome, fmt.Errorf("population extinct in generation %d", p.Generation)
+	if p.Config.Neat.GenePooling {
+		for _, g := range p.Population {
+			p.retired = append(p.retired, g) // Still referenced by the species until the next speciation
 		}
-	} else {
-		p.Population = newPopulation
+	}
+	p.Population = newPopulation
+	if p.Ratings != nil {
+		p.Ratings.advance(p.Population, p.Reproduction.Ancestors)
+	}
+	if p.PhenotypeCache != nil {
+		p.PhenotypeCache.Prune(p.Population) // Networks of discarded genomes are never needed again
 	}
 
-	// TODO: Add Reporting Calls Here
+	p.Reporters.EndGeneration(p.Config, p.Population, p.SpeciesSet)
+	if err := p.runHooks("post-reproduce", p.hooks.postReproduce); err != nil {
+		return p.BestGenome, err
+	}
 
-	genEndTime := time.Now()
-	fmt.Printf("Generation %d finished in %s\n\n", p.Generation, genEndTime.Sub(genStartTime))
+	p.finishGeneration(genStartTime)
 
 	return nil, nil // No winner found this generation
 }
 
+// thresholdMet reports whether the best genome so far reaches the fitness threshold, which ends
+// the run unless no_fitness_termination is set or a curriculum is not yet in its final stage.
+func (p *Population) thresholdMet() bool {
+	finalStage := p.Curriculum == nil || p.Curriculum.State.Stage == len(p.Curriculum.Stages)-1
+	return !p.Config.Neat.NoFitnessTermination && finalStage && p.BestGenome != nil &&
+		p.BestGenome.Fitness >= p.Config.Neat.FitnessThreshold
+}
+
+// Inspect calls f with the population while no generation is running, waiting for a running
+// generation to finish first. Use it to read the population from other goroutines, e.g. a
+// monitoring endpoint; f must not call RunGeneration or Inspect. Fitness functions and hooks
+// already run inside the generation and must not call Inspect either.
+func (p *Population) Inspect(f func(p *Population)) {
+	p.running.Lock()
+	defer p.running.Unlock()
+	f(p)
+}
+
+// evaluateFitness runs fitnessFunc on genomes, through the evaluation cache if one is set.
+func (p *Population) evaluateFitness(genomes map[int]*Genome, fitnessFunc FitnessFunc) error {
+	if p.EvaluationCache != nil {
+		return p.EvaluationCache.evaluate(genomes, fitnessFunc)
+	}
+	return fitnessFunc(genomes)
+}
+
 // findBestGenome finds the genome with the highest fitness in the current population.
 func (p *Population) findBestGenome() *Genome {
 	var best *Genome = nil
@@ -0,0 +1,96 @@
+package neat
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestInspectDuringParallelEvaluation reads the population through Inspect and snapshots while
+// generations evaluate genomes in parallel. Run it with -race.
+func TestInspectDuringParallelEvaluation(t *testing.T) {
+	config := loadTestConfig(t)
+	config.Neat.PopSize = 50
+	config.Neat.NoFitnessTermination = true
+	config.Neat.PhenotypeCache = true
+	p, err := NewPopulation(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetLogger(NopLogger{})
+	p.Statistics = NewStatistics()
+
+	fitness := func(genomes map[int]*Genome) error {
+		var wg sync.WaitGroup
+		for _, g := range genomes {
+			wg.Add(1)
+			go func(g *Genome) {
+				defer wg.Done()
+				_, enabled := g.Size()
+				g.Fitness = float64(enabled) - 0.1*float64(len(g.Nodes))
+				g.Behavior = []float64{float64(enabled), float64(len(g.Nodes))}
+			}(g)
+		}
+		wg.Wait()
+		return nil
+	}
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			var snap *PopulationSnapshot
+			p.Inspect(func(p *Population) {
+				for _, g := range p.Population {
+					_ = g.Fitness + float64(len(g.Connections))
+				}
+				snap = p.Snapshot()
+			})
+			// The snapshot is read while the next generation runs.
+			for _, g := range snap.Population {
+				for _, cg := range g.Connections {
+					_ = cg.Weight
+				}
+			}
+			for _, s := range snap.Species {
+				_ = len(s.Members)
+			}
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		if _, err := p.RunGeneration(fitness); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(done)
+	readers.Wait()
+}
+
+func TestRunGenerationRejectsOverlap(t *testing.T) {
+	config := loadTestConfig(t)
+	config.Neat.PopSize = 10
+	config.Neat.NoFitnessTermination = true
+	p, err := NewPopulation(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetLogger(NopLogger{})
+	var nested error
+	_, err = p.RunGeneration(func(genomes map[int]*Genome) error {
+		_, nested = p.RunGeneration(func(map[int]*Genome) error { return nil })
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nested == nil {
+		t.Error("RunGeneration called during a generation succeeded, want an error")
+	}
+}
@@ -0,0 +1,134 @@
+package neat
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProgressStore persists partial evaluation progress so a long evaluation can resume after a restart.
+// Keys are derived from genome content hashes and are safe to use as file names.
+type ProgressStore interface {
+	Load(key string) (data []byte, found bool, err error)
+	Save(key string, data []byte) error
+	Delete(key string) error
+}
+
+// FileProgressStore is a ProgressStore that keeps one file per key in a directory.
+type FileProgressStore struct {
+	Dir string
+}
+
+// NewFileProgressStore creates a store in dir, creating the directory if needed.
+func NewFileProgressStore(dir string) (*FileProgressStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create progress directory '%s': %w", dir, err)
+	}
+	return &FileProgressStore{Dir: dir}, nil
+}
+
+// Load reads the data stored under key. found is false if nothing was saved.
+func (fs *FileProgressStore) Load(key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(filepath.Join(fs.Dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read progress '%s': %w", key, err)
+	}
+	return data, true, nil
+}
+
+// Save writes data under key. The file is replaced atomically so a crash never leaves partial data.
+func (fs *FileProgressStore) Save(key string, data []byte) error {
+	path := filepath.Join(fs.Dir, key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write progress '%s': %w", key, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace progress '%s': %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes the data stored under key, if any.
+func (fs *FileProgressStore) Delete(key string) error {
+	err := os.Remove(filepath.Join(fs.Dir, key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete progress '%s': %w", key, err)
+	}
+	return nil
+}
+
+// TrialCheckpoint lets a long-running trial persist its own state and notice stop requests.
+// A trial that is stopped can save its state and return Context().Err(); when the evaluation
+// is resumed, LoadState returns the saved state for the same genome and trial.
+type TrialCheckpoint struct {
+	ctx   context.Context
+	store ProgressStore
+	key   string
+}
+
+// Context returns the evaluation context; it is cancelled when the evaluation should stop.
+func (tc *TrialCheckpoint) Context() context.Context {
+	return tc.ctx
+}
+
+// LoadState returns the state saved by a previous run of this trial, if any.
+func (tc *TrialCheckpoint) LoadState() ([]byte, bool, error) {
+	if tc.store == nil {
+		return nil, false, nil
+	}
+	return tc.store.Load(tc.key)
+}
+
+// SaveState persists the trial's current state. Without a progress store this is a no-op.
+func (tc *TrialCheckpoint) SaveState(state []byte) error {
+	if tc.store == nil {
+		return nil
+	}
+	return tc.store.Save(tc.key, state)
+}
+
+// genomeContentHash returns a hex SHA-256 digest of the genome's genes and their attribute values,
+// and of its extension's ContentHash. Genomes that would behave identically hash identically,
+// regardless of their keys. It returns false if the genome has an extension that isn't a
+// HashableExtension, since its content can't be hashed then.
+func genomeContentHash(g *Genome) (string, bool) {
+	var b strings.Builder
+	if g.Extension != nil {
+		h, ok := g.Extension.(HashableExtension)
+		if !ok {
+			return "", false
+		}
+		fmt.Fprintf(&b, "x%q;", h.ContentHash())
+	}
+	nodeKeys := make([]int, 0, len(g.Nodes))
+	for k := range g.Nodes {
+		nodeKeys = append(nodeKeys, k)
+	}
+	sort.Ints(nodeKeys)
+	for _, k := range nodeKeys {
+		ng := g.Nodes[k]
+		fmt.Fprintf(&b, "n%d:%s:%s:%s:%s:%s;", k, formatHashFloat(ng.Bias), formatHashFloat(ng.Response), ng.Activation, ng.Aggregation, formatHashFloat(ng.ActivationParam))
+	}
+	for _, ck := range sortedConnectionKeys(g) {
+		cg := g.Connections[ck]
+		fmt.Fprintf(&b, "c%d>%d:%s:%t:%s;", ck.InNodeID, ck.OutNodeID, formatHashFloat(cg.Weight), cg.Enabled, formatHashFloat(cg.Expression))
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:]), true
+}
+
+// formatHashFloat formats a float exactly (shortest round-trip representation).
+func formatHashFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
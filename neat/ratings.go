@@ -0,0 +1,170 @@
+package neat
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Rating is the skill estimate of one genome. For Elo, Mu is the rating and Sigma is unused.
+// For TrueSkill, Mu is the mean skill and Sigma its uncertainty.
+type Rating struct {
+	Mu    float64
+	Sigma float64
+	Games int
+}
+
+// Ratings maintains Elo or TrueSkill ratings of genomes across generations of competitive
+// evaluation. Attach it to Population.Ratings to have it saved in checkpoints and carried over
+// between generations: genomes that leave the population are forgotten, and offspring start
+// from the mean skill of their parents with the initial uncertainty. Record game results with
+// Record, or let a Tournament do it (Tournament.Ratings); Score turns a rating into fitness or a
+// reporting metric.
+type Ratings struct {
+	System          string  // "elo" or "trueskill"
+	Initial         Rating  // Rating of genomes without ancestry
+	EloK            float64 // Elo update factor
+	Beta            float64 // TrueSkill performance noise
+	Tau             float64 // TrueSkill dynamics noise, added to Sigma before every game
+	DrawProbability float64 // TrueSkill prior probability of a draw between equal players
+	Values          map[int]Rating
+}
+
+// NewEloRatings creates Elo ratings starting at initial with update factor k.
+func NewEloRatings(k, initial float64) *Ratings {
+	return &Ratings{System: "elo", Initial: Rating{Mu: initial}, EloK: k, Values: make(map[int]Rating)}
+}
+
+// NewTrueSkillRatings creates TrueSkill ratings with the standard parameters (mu 25, sigma 25/3).
+func NewTrueSkillRatings() *Ratings {
+	return &Ratings{
+		System:          "trueskill",
+		Initial:         Rating{Mu: 25, Sigma: 25.0 / 3},
+		Beta:            25.0 / 6,
+		Tau:             25.0 / 300,
+		DrawProbability: 0.1,
+		Values:          make(map[int]Rating),
+	}
+}
+
+// Get returns the rating of genome key, or the initial rating if it has none.
+func (r *Ratings) Get(key int) Rating {
+	if rating, ok := r.Values[key]; ok {
+		return rating
+	}
+	return r.Initial
+}
+
+// Score returns the rating of genome key as a single number: the Elo rating, or the conservative
+// TrueSkill estimate Mu - 3*Sigma.
+func (r *Ratings) Score(key int) float64 {
+	rating := r.Get(key)
+	if r.System == "trueskill" {
+		return rating.Mu - 3*rating.Sigma
+	}
+	return rating.Mu
+}
+
+// Record updates the ratings of genomes a and b after a game with the given outcome for a.
+func (r *Ratings) Record(a, b int, outcome Outcome) error {
+	if r.Values == nil {
+		r.Values = make(map[int]Rating)
+	}
+	ra, rb := r.Get(a), r.Get(b)
+	switch r.System {
+	case "elo":
+		expected := 1 / (1 + math.Pow(10, (rb.Mu-ra.Mu)/400))
+		delta := r.EloK * (float64(outcome)/2 - expected)
+		ra.Mu += delta
+		rb.Mu -= delta
+	case "trueskill":
+		if outcome == Loss {
+			rb, ra = trueSkillUpdate(rb, ra, false, r.Beta, r.Tau, r.DrawProbability)
+		} else {
+			ra, rb = trueSkillUpdate(ra, rb, outcome == Draw, r.Beta, r.Tau, r.DrawProbability)
+		}
+	default:
+		return fmt.Errorf("invalid rating system '%s', must be 'elo' or 'trueskill'", r.System)
+	}
+	ra.Games++
+	rb.Games++
+	r.Values[a], r.Values[b] = ra, rb
+	return nil
+}
+
+// trueSkillUpdate applies the two-player TrueSkill update to a winner and a loser, or to two
+// players who drew.
+func trueSkillUpdate(winner, loser Rating, draw bool, beta, tau, drawProbability float64) (Rating, Rating) {
+	winner.Sigma = math.Sqrt(winner.Sigma*winner.Sigma + tau*tau)
+	loser.Sigma = math.Sqrt(loser.Sigma*loser.Sigma + tau*tau)
+	c := math.Sqrt(2*beta*beta + winner.Sigma*winner.Sigma + loser.Sigma*loser.Sigma)
+	epsilon := math.Sqrt2 * math.Erfinv(drawProbability) * math.Sqrt2 * beta / c // Draw margin over c
+	t := (winner.Mu - loser.Mu) / c
+
+	var v, w float64
+	if draw {
+		denom := math.Max(normalCDF(epsilon-t)-normalCDF(-epsilon-t), 1e-12)
+		v = (normalPDF(-epsilon-t) - normalPDF(epsilon-t)) / denom
+		w = v*v + ((epsilon-t)*normalPDF(epsilon-t)+(epsilon+t)*normalPDF(epsilon+t))/denom
+	} else {
+		v = normalPDF(t-epsilon) / math.Max(normalCDF(t-epsilon), 1e-12)
+		w = v * (v + t - epsilon)
+	}
+	w = math.Min(math.Max(w, 0), 1-1e-9) // Keep the variances positive despite rounding
+
+	ws2, ls2 := winner.Sigma*winner.Sigma, loser.Sigma*loser.Sigma
+	winner.Mu += ws2 / c * v
+	loser.Mu -= ls2 / c * v
+	winner.Sigma = math.Sqrt(ws2 * (1 - ws2/(c*c)*w))
+	loser.Sigma = math.Sqrt(ls2 * (1 - ls2/(c*c)*w))
+	return winner, loser
+}
+
+func normalPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// Ranked returns the keys of the rated genomes, best score first (ties by lower key).
+func (r *Ratings) Ranked() []int {
+	keys := make([]int, 0, len(r.Values))
+	for k := range r.Values {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		si, sj := r.Score(keys[i]), r.Score(keys[j])
+		if si != sj {
+			return si > sj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// advance carries the ratings over to a new generation: ratings of genomes no longer in
+// population are dropped, and new genomes start from the mean Mu of their rated parents.
+func (r *Ratings) advance(population map[int]*Genome, ancestors map[int][]int) {
+	values := make(map[int]Rating, len(population))
+	for key := range population {
+		if rating, ok := r.Values[key]; ok {
+			values[key] = rating
+			continue
+		}
+		rating := r.Initial
+		sum, n := 0.0, 0
+		for _, parent := range ancestors[key] {
+			if pr, ok := r.Values[parent]; ok {
+				sum += pr.Mu
+				n++
+			}
+		}
+		if n > 0 {
+			rating.Mu = sum / float64(n)
+		}
+		values[key] = rating
+	}
+	r.Values = values
+}
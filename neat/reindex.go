@@ -0,0 +1,49 @@
+package neat
+
+// SpeciesReindex records one renumbering of the species, so per-generation data keyed by species,
+// such as GenerationStats.SpeciesSizes, can be followed across it.
+type SpeciesReindex struct {
+	Generation int         // Generation whose speciation was renumbered; its statistics use the new keys
+	Keys       map[int]int // Old species key -> new key, for every species alive at the time
+}
+
+// Reindex renumbers the species consecutively from offset+1 in order of their current keys, which
+// is the order they were created in, and continues new keys after them. It returns the old to new
+// key mapping. Keys of extinct species are reused, so data recorded under old keys is only
+// meaningful together with the mapping.
+func (ss *SpeciesSet) Reindex(offset int) map[int]int {
+	keys := make(map[int]int, len(ss.Species))
+	species := make(map[int]*Species, len(ss.Species))
+	next := offset + 1
+	for _, oldKey := range sortedKeys(ss.Species) {
+		sp := ss.Species[oldKey]
+		keys[oldKey] = next
+		sp.Key = next
+		species[next] = sp
+		next++
+	}
+	for genomeKey, sid := range ss.GenomeToSpecies {
+		if newKey, ok := keys[sid]; ok {
+			ss.GenomeToSpecies[genomeKey] = newKey
+		} else {
+			delete(ss.GenomeToSpecies, genomeKey) // Species no longer exists
+		}
+	}
+	ss.Species = species
+	ss.Indexer = next
+	return keys
+}
+
+// ReindexSpecies renumbers the population's species from 1 (after key_offset) so species keys
+// stay small in long runs; species_reindex_interval does this automatically after speciation.
+// Without it species keys grow monotonically and are never reused, which keeps every key a
+// stable identity for the whole run. The mapping is recorded in Statistics.SpeciesReindexings
+// and returned. A SpeciationPolicy or reporter keeping state by species key must apply it too.
+func (p *Population) ReindexSpecies() map[int]int {
+	keys := p.SpeciesSet.Reindex(p.Config.Neat.KeyOffset)
+	if p.Statistics != nil {
+		p.Statistics.SpeciesReindexings = append(p.Statistics.SpeciesReindexings, SpeciesReindex{Generation: p.Generation, Keys: keys})
+	}
+	p.log().Info("Species reindexed", "generation", p.Generation, "species", len(keys))
+	return keys
+}
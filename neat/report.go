@@ -0,0 +1,514 @@
+package neat
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WriteRunReport writes a self-contained summary of a run to filePath.
+// The report includes the configuration used, fitness curves, species sizes over time,
+// the best genome's structure and any tables attached to the statistics.
+// Files ending in ".md" are written as Markdown; anything else is written as a single HTML page
+// with inline SVG charts. stats may be nil, in which case only the config and best genome are reported.
+func WriteRunReport(pop *Population, stats *Statistics, filePath string) error {
+	if pop == nil {
+		return fmt.Errorf("cannot write run report for a nil population")
+	}
+	if stats == nil {
+		stats = NewStatistics()
+	}
+
+	var content string
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".md", ".markdown":
+		content = renderMarkdownReport(pop, stats)
+	default:
+		content = renderHTMLReport(pop, stats)
+	}
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write run report '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// --------------------------- Markdown ---------------------------
+
+func renderMarkdownReport(pop *Population, stats *Statistics) string {
+	var b strings.Builder
+
+	b.WriteString("# NEAT Run Report\n\n")
+	writeReportSummary(&b, pop, stats, "- ", "\n")
+	b.WriteString("\n")
+
+	b.WriteString("## Configuration\n\n```ini\n")
+	b.WriteString(formatConfigINI(pop.Config))
+	b.WriteString("```\n\n")
+
+	if len(stats.Generations) > 0 {
+		b.WriteString("## Fitness\n\n")
+		b.WriteString("| Generation | Best | Mean | Stdev | Species | Best complexity | Mean complexity |\n")
+		b.WriteString("|---:|---:|---:|---:|---:|---:|---:|\n")
+		for _, gs := range stats.Generations {
+			fmt.Fprintf(&b, "| %d | %.4f | %.4f | %.4f | %d | %d | %.2f |\n",
+				gs.Generation, gs.BestFitness, gs.MeanFitness, gs.StdevFitness, len(gs.SpeciesSizes), gs.BestComplexity, gs.MeanComplexity)
+		}
+		b.WriteString("\n")
+
+		speciesKeys := reportSpeciesKeys(stats)
+		if len(speciesKeys) > 0 {
+			b.WriteString("## Species Sizes\n\n| Generation |")
+			for _, sid := range speciesKeys {
+				fmt.Fprintf(&b, " %d |", sid)
+			}
+			b.WriteString("\n|---:|")
+			for range speciesKeys {
+				b.WriteString("---:|")
+			}
+			b.WriteString("\n")
+			for _, gs := range stats.Generations {
+				fmt.Fprintf(&b, "| %d |", gs.Generation)
+				for _, sid := range speciesKeys {
+					fmt.Fprintf(&b, " %d |", gs.SpeciesSizes[sid])
+				}
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if pop.BestGenome != nil {
+		g := pop.BestGenome
+		b.WriteString("## Best Genome\n\n```mermaid\ngraph LR\n")
+		for _, k := range g.Config.InputKeys {
+			fmt.Fprintf(&b, "    n%s((in %d))\n", mermaidNodeID(k), k)
+		}
+		for _, k := range sortedNodeKeys(g) {
+			fmt.Fprintf(&b, "    n%s[%d: %s]\n", mermaidNodeID(k), k, g.Nodes[k].Activation)
+		}
+		for _, ck := range sortedConnectionKeys(g) {
+			cg := g.Connections[ck]
+			arrow := "-->"
+			if !cg.Enabled {
+				arrow = "-.->"
+			}
+			fmt.Fprintf(&b, "    n%s %s|%.2f| n%s\n", mermaidNodeID(ck.InNodeID), arrow, cg.Weight, mermaidNodeID(ck.OutNodeID))
+		}
+		b.WriteString("```\n\n")
+	}
+
+	for _, t := range stats.Tables {
+		fmt.Fprintf(&b, "## %s\n\n|", t.Title)
+		for _, h := range t.Header {
+			fmt.Fprintf(&b, " %s |", h)
+		}
+		b.WriteString("\n|")
+		for range t.Header {
+			b.WriteString("---|")
+		}
+		b.WriteString("\n")
+		for _, row := range t.Rows {
+			b.WriteString("|")
+			for _, cell := range row {
+				fmt.Fprintf(&b, " %s |", cell)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// mermaidNodeID converts a node key into an identifier Mermaid accepts (no leading minus sign).
+func mermaidNodeID(key int) string {
+	if key < 0 {
+		return fmt.Sprintf("m%d", -key)
+	}
+	return fmt.Sprintf("%d", key)
+}
+
+// --------------------------- HTML ---------------------------
+
+func renderHTMLReport(pop *Population, stats *Statistics) string {
+	var b strings.Builder
+
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>NEAT Run Report</title>\n")
+	b.WriteString("<style>body{font-family:sans-serif;margin:2em;}table{border-collapse:collapse;margin-bottom:1em;}" +
+		"td,th{border:1px solid #ccc;padding:2px 8px;text-align:right;}pre{background:#f4f4f4;padding:1em;}</style>\n")
+	b.WriteString("</head>\n<body>\n<h1>NEAT Run Report</h1>\n<ul>\n")
+	writeReportSummary(&b, pop, stats, "<li>", "</li>\n")
+	b.WriteString("</ul>\n")
+
+	b.WriteString("<h2>Configuration</h2>\n<pre>")
+	b.WriteString(html.EscapeString(formatConfigINI(pop.Config)))
+	b.WriteString("</pre>\n")
+
+	if len(stats.Generations) > 0 {
+		b.WriteString("<h2>Fitness</h2>\n")
+		b.WriteString(renderFitnessSVG(stats))
+		b.WriteString("<h2>Complexity</h2>\n")
+		b.WriteString(renderComplexitySVG(stats))
+		speciesKeys := reportSpeciesKeys(stats)
+		if len(speciesKeys) > 0 {
+			b.WriteString("<h2>Species Sizes</h2>\n")
+			b.WriteString(renderSpeciesSVG(stats, speciesKeys))
+		}
+	}
+
+	if pop.BestGenome != nil {
+		b.WriteString("<h2>Best Genome</h2>\n")
+		b.WriteString(renderGenomeSVG(pop.BestGenome))
+	}
+
+	for _, t := range stats.Tables {
+		fmt.Fprintf(&b, "<h2>%s</h2>\n<table>\n<tr>", html.EscapeString(t.Title))
+		for _, h := range t.Header {
+			fmt.Fprintf(&b, "<th>%s</th>", html.EscapeString(h))
+		}
+		b.WriteString("</tr>\n")
+		for _, row := range t.Rows {
+			b.WriteString("<tr>")
+			for _, cell := range row {
+				fmt.Fprintf(&b, "<td>%s</td>", html.EscapeString(cell))
+			}
+			b.WriteString("</tr>\n")
+		}
+		b.WriteString("</table>\n")
+	}
+
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+const (
+	reportChartWidth  = 640.0
+	reportChartHeight = 240.0
+	reportChartMargin = 40.0
+)
+
+// renderFitnessSVG draws the best and mean fitness curves as an inline SVG line chart.
+func renderFitnessSVG(stats *Statistics) string {
+	best := stats.BestFitnesses()
+	mean := stats.MeanFitnesses()
+	minY := math.Min(MinFloat(best), MinFloat(mean))
+	maxY := math.Max(MaxFloat(best), MaxFloat(mean))
+
+	var b strings.Builder
+	writeChartFrame(&b, minY, maxY)
+	fmt.Fprintf(&b, "<polyline fill=\"none\" stroke=\"#d62728\" stroke-width=\"2\" points=\"%s\"/>\n", chartPoints(best, minY, maxY))
+	fmt.Fprintf(&b, "<polyline fill=\"none\" stroke=\"#1f77b4\" stroke-width=\"2\" points=\"%s\"/>\n", chartPoints(mean, minY, maxY))
+	fmt.Fprintf(&b, "<text x=\"%.0f\" y=\"15\" fill=\"#d62728\">best</text><text x=\"%.0f\" y=\"15\" fill=\"#1f77b4\">mean</text>\n",
+		reportChartMargin, reportChartMargin+50)
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// renderComplexitySVG draws the mean genome complexity (nodes + enabled connections) per generation.
+func renderComplexitySVG(stats *Statistics) string {
+	complexity := stats.MeanComplexities()
+	minY := MinFloat(complexity)
+	maxY := MaxFloat(complexity)
+
+	var b strings.Builder
+	writeChartFrame(&b, minY, maxY)
+	fmt.Fprintf(&b, "<polyline fill=\"none\" stroke=\"#9467bd\" stroke-width=\"2\" points=\"%s\"/>\n", chartPoints(complexity, minY, maxY))
+	fmt.Fprintf(&b, "<text x=\"%.0f\" y=\"15\" fill=\"#9467bd\">mean complexity</text>\n", reportChartMargin)
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// renderSpeciesSVG draws the species sizes as a stacked area chart.
+func renderSpeciesSVG(stats *Statistics, speciesKeys []int) string {
+	n := len(stats.Generations)
+	// Cumulative totals per generation, stacked in species key order.
+	lower := make([]float64, n)
+	maxTotal := 0.0
+	for _, gs := range stats.Generations {
+		total := 0
+		for _, size := range gs.SpeciesSizes {
+			total += size
+		}
+		maxTotal = math.Max(maxTotal, float64(total))
+	}
+
+	var b strings.Builder
+	writeChartFrame(&b, 0, maxTotal)
+	for i, sid := range speciesKeys {
+		upper := make([]float64, n)
+		for j, gs := range stats.Generations {
+			upper[j] = lower[j] + float64(gs.SpeciesSizes[sid])
+		}
+		// Polygon: upper edge left-to-right, then lower edge right-to-left.
+		reversedLower := make([]float64, n)
+		for j := range lower {
+			reversedLower[n-1-j] = lower[j]
+		}
+		points := chartPoints(upper, 0, maxTotal) + " " + chartPointsReversed(reversedLower, 0, maxTotal)
+		hue := (i * 47) % 360
+		fmt.Fprintf(&b, "<polygon fill=\"hsl(%d,60%%,60%%)\" stroke=\"none\" points=\"%s\"><title>species %d</title></polygon>\n", hue, points, sid)
+		lower = upper
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// writeChartFrame opens an SVG element and draws the axes with min/max labels.
+func writeChartFrame(b *strings.Builder, minY, maxY float64) {
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\">\n",
+		reportChartWidth+2*reportChartMargin, reportChartHeight+2*reportChartMargin)
+	fmt.Fprintf(b, "<line x1=\"%.0f\" y1=\"%.0f\" x2=\"%.0f\" y2=\"%.0f\" stroke=\"black\"/>\n",
+		reportChartMargin, reportChartMargin, reportChartMargin, reportChartMargin+reportChartHeight)
+	fmt.Fprintf(b, "<line x1=\"%.0f\" y1=\"%.0f\" x2=\"%.0f\" y2=\"%.0f\" stroke=\"black\"/>\n",
+		reportChartMargin, reportChartMargin+reportChartHeight, reportChartMargin+reportChartWidth, reportChartMargin+reportChartHeight)
+	fmt.Fprintf(b, "<text x=\"2\" y=\"%.0f\" font-size=\"10\">%.2f</text>\n", reportChartMargin+4, maxY)
+	fmt.Fprintf(b, "<text x=\"2\" y=\"%.0f\" font-size=\"10\">%.2f</text>\n", reportChartMargin+reportChartHeight, minY)
+}
+
+// chartPoints maps values (one per generation) to SVG polyline coordinates.
+func chartPoints(values []float64, minY, maxY float64) string {
+	points := make([]string, len(values))
+	for i, v := range values {
+		x, y := chartCoords(i, len(values), v, minY, maxY)
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return strings.Join(points, " ")
+}
+
+// chartPointsReversed is like chartPoints but for values already in reverse generation order.
+func chartPointsReversed(values []float64, minY, maxY float64) string {
+	n := len(values)
+	points := make([]string, n)
+	for i, v := range values {
+		x, y := chartCoords(n-1-i, n, v, minY, maxY)
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return strings.Join(points, " ")
+}
+
+func chartCoords(index, count int, value, minY, maxY float64) (float64, float64) {
+	x := reportChartMargin
+	if count > 1 {
+		x += float64(index) / float64(count-1) * reportChartWidth
+	}
+	yRange := maxY - minY
+	if yRange <= 0 {
+		yRange = 1.0
+	}
+	y := reportChartMargin + reportChartHeight - (value-minY)/yRange*reportChartHeight
+	return x, y
+}
+
+// renderGenomeSVG draws the genome as a layered graph: inputs on the left, outputs on the right
+// and hidden nodes placed by their depth from the inputs. Disabled connections are dashed.
+func renderGenomeSVG(g *Genome) string {
+	layers := genomeLayers(g)
+	maxLayer := 0
+	for _, l := range layers {
+		maxLayer = max(maxLayer, l)
+	}
+	// Outputs always go in the last column.
+	for _, k := range g.Config.OutputKeys {
+		layers[k] = maxLayer + 1
+	}
+	numLayers := maxLayer + 2
+
+	byLayer := make(map[int][]int)
+	for k, l := range layers {
+		byLayer[l] = append(byLayer[l], k)
+	}
+	maxPerLayer := 1
+	for _, keys := range byLayer {
+		sort.Ints(keys)
+		maxPerLayer = max(maxPerLayer, len(keys))
+	}
+
+	const colWidth, rowHeight, radius = 120.0, 60.0, 14.0
+	width := float64(numLayers)*colWidth + 2*reportChartMargin
+	height := float64(maxPerLayer)*rowHeight + 2*reportChartMargin
+	positions := make(map[int][2]float64)
+	for l, keys := range byLayer {
+		offset := (float64(maxPerLayer) - float64(len(keys))) * rowHeight / 2
+		for i, k := range keys {
+			positions[k] = [2]float64{
+				reportChartMargin + float64(l)*colWidth + colWidth/2,
+				reportChartMargin + offset + float64(i)*rowHeight + rowHeight/2,
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\">\n", width, height)
+	for _, ck := range sortedConnectionKeys(g) {
+		cg := g.Connections[ck]
+		from, okFrom := positions[ck.InNodeID]
+		to, okTo := positions[ck.OutNodeID]
+		if !okFrom || !okTo {
+			continue
+		}
+		color := "#2ca02c"
+		if cg.Weight < 0 {
+			color = "#d62728"
+		}
+		dash := ""
+		if !cg.Enabled {
+			dash = " stroke-dasharray=\"4,4\" opacity=\"0.4\""
+		}
+		strokeWidth := clamp(math.Abs(cg.Weight), 0.5, 5.0)
+		fmt.Fprintf(&b, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\" stroke-width=\"%.1f\"%s><title>%d -&gt; %d: %.3f</title></line>\n",
+			from[0], from[1], to[0], to[1], color, strokeWidth, dash, ck.InNodeID, ck.OutNodeID, cg.Weight)
+	}
+	for k, pos := range positions {
+		label := fmt.Sprintf("%d", k)
+		title := label
+		if ng, ok := g.Nodes[k]; ok {
+			title = fmt.Sprintf("%d: %s/%s bias=%.3f response=%.3f", k, ng.Activation, ng.Aggregation, ng.Bias, ng.Response)
+		}
+		fmt.Fprintf(&b, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.0f\" fill=\"white\" stroke=\"black\"><title>%s</title></circle>\n",
+			pos[0], pos[1], radius, html.EscapeString(title))
+		fmt.Fprintf(&b, "<text x=\"%.1f\" y=\"%.1f\" font-size=\"10\" text-anchor=\"middle\">%s</text>\n", pos[0], pos[1]+4, label)
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// genomeLayers assigns each node a column: inputs are layer 0 and other nodes are placed one
+// layer after the deepest node feeding them through enabled connections. Cycles are bounded
+// by limiting the number of relaxation passes.
+func genomeLayers(g *Genome) map[int]int {
+	layers := make(map[int]int)
+	for _, k := range g.Config.InputKeys {
+		layers[k] = 0
+	}
+	for k := range g.Nodes {
+		layers[k] = 1
+	}
+	for pass := 0; pass < len(g.Nodes); pass++ {
+		changed := false
+		for ck, cg := range g.Connections {
+			if !cg.Enabled {
+				continue
+			}
+			inLayer, okIn := layers[ck.InNodeID]
+			outLayer, okOut := layers[ck.OutNodeID]
+			if okIn && okOut && outLayer <= inLayer && inLayer+1 <= len(g.Nodes) {
+				layers[ck.OutNodeID] = inLayer + 1
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return layers
+}
+
+// --------------------------- Shared helpers ---------------------------
+
+// writeReportSummary writes the headline numbers of the run, one item per line.
+func writeReportSummary(b *strings.Builder, pop *Population, stats *Statistics, prefix, suffix string) {
+	fmt.Fprintf(b, "%sGenerations run: %d%s", prefix, pop.Generation, suffix)
+	fmt.Fprintf(b, "%sPopulation size: %d%s", prefix, len(pop.Population), suffix)
+	if pop.SpeciesSet != nil {
+		fmt.Fprintf(b, "%sSpecies: %d%s", prefix, len(pop.SpeciesSet.Species), suffix)
+	}
+	if pop.BestGenome != nil {
+		fmt.Fprintf(b, "%sBest genome: %d (fitness %.4f, %d nodes, %d connections)%s",
+			prefix, pop.BestGenome.Key, pop.BestGenome.Fitness, len(pop.BestGenome.Nodes), len(pop.BestGenome.Connections), suffix)
+	}
+	if len(stats.Generations) > 0 {
+		fmt.Fprintf(b, "%sRecorded generations: %d%s", prefix, len(stats.Generations), suffix)
+	}
+}
+
+// reportSpeciesKeys returns every species key seen across the recorded generations, sorted.
+func reportSpeciesKeys(stats *Statistics) []int {
+	seen := make(map[int]struct{})
+	for _, gs := range stats.Generations {
+		for sid := range gs.SpeciesSizes {
+			seen[sid] = struct{}{}
+		}
+	}
+	keys := make([]int, 0, len(seen))
+	for sid := range seen {
+		keys = append(keys, sid)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func sortedNodeKeys(g *Genome) []int {
+	keys := make([]int, 0, len(g.Nodes))
+	for k := range g.Nodes {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func sortedConnectionKeys(g *Genome) []ConnectionKey {
+	keys := make([]ConnectionKey, 0, len(g.Connections))
+	for k := range g.Connections {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].InNodeID != keys[j].InNodeID {
+			return keys[i].InNodeID < keys[j].InNodeID
+		}
+		return keys[i].OutNodeID < keys[j].OutNodeID
+	})
+	return keys
+}
+
+// formatConfigINI renders the configuration in the same INI layout LoadConfig reads.
+func formatConfigINI(config *Config) string {
+	if config == nil {
+		return ""
+	}
+	sections := []struct {
+		Name  string
+		Value interface{}
+	}{
+		{"NEAT", config.Neat},
+		{"DefaultGenome", config.Genome},
+		{"DefaultReproduction", config.Reproduction},
+		{"DefaultSpeciesSet", config.SpeciesSet},
+		{"DefaultStagnation", config.Stagnation},
+		{"Experimental", config.Experimental},
+	}
+
+	var b strings.Builder
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s]\n", section.Name)
+		v := reflect.ValueOf(section.Value)
+		t := v.Type()
+		for j := 0; j < t.NumField(); j++ {
+			tag := t.Field(j).Tag.Get("ini")
+			if tag == "" || tag == "-" {
+				continue // Derived fields are not part of the file format.
+			}
+			field := v.Field(j)
+			var value string
+			if field.Kind() == reflect.Slice {
+				parts := make([]string, field.Len())
+				for k := 0; k < field.Len(); k++ {
+					parts[k] = fmt.Sprint(field.Index(k).Interface())
+				}
+				value = strings.Join(parts, " ")
+			} else {
+				value = fmt.Sprint(field.Interface())
+			}
+			fmt.Fprintf(&b, "%s = %s\n", tag, value)
+		}
+	}
+	return b.String()
+}
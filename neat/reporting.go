@@ -0,0 +1,199 @@
+package neat
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reporter receives notifications about the progress of a run.
+// Embed BaseReporter to implement only the methods of interest.
+type Reporter interface {
+	StartGeneration(generation int)
+	EndGeneration(config *Config, population map[int]*Genome, speciesSet *SpeciesSet)
+	PostEvaluate(config *Config, population map[int]*Genome, speciesSet *SpeciesSet, best *Genome)
+	CompleteExtinction()
+	FoundSolution(config *Config, generation int, best *Genome)
+	SpeciesStagnant(speciesKey int, species *Species)
+	SpeciesSpared(speciesKey int, species *Species, generation int)
+	Speciated(generation int, result SpeciationResult)
+	PhaseFinished(generation int, phase Phase, elapsed time.Duration)
+	GenomeSizes(generation int, sizes GenomeSizeStats)
+	Info(msg string)
+}
+
+// BaseReporter implements Reporter with no-op methods, for embedding in custom reporters.
+type BaseReporter struct{}
+
+// The BaseReporter methods intentionally do nothing.
+func (BaseReporter) StartGeneration(int)                                         {}
+func (BaseReporter) EndGeneration(*Config, map[int]*Genome, *SpeciesSet)         {}
+func (BaseReporter) PostEvaluate(*Config, map[int]*Genome, *SpeciesSet, *Genome) {}
+func (BaseReporter) CompleteExtinction()                                         {}
+func (BaseReporter) FoundSolution(*Config, int, *Genome)                         {}
+func (BaseReporter) SpeciesStagnant(int, *Species)                               {}
+func (BaseReporter) SpeciesSpared(int, *Species, int)                            {}
+func (BaseReporter) Speciated(int, SpeciationResult)                             {}
+func (BaseReporter) PhaseFinished(int, Phase, time.Duration)                     {}
+func (BaseReporter) GenomeSizes(int, GenomeSizeStats)                            {}
+func (BaseReporter) Info(string)                                                 {}
+
+// ReporterSet dispatches notifications to every registered reporter.
+// A nil *ReporterSet is valid and discards all notifications.
+type ReporterSet struct {
+	Reporters []Reporter
+}
+
+// NewReporterSet creates an empty reporter set.
+func NewReporterSet() *ReporterSet {
+	return &ReporterSet{Reporters: []Reporter{}}
+}
+
+// Add registers a reporter.
+func (rs *ReporterSet) Add(reporter Reporter) {
+	rs.Reporters = append(rs.Reporters, reporter)
+}
+
+// Remove unregisters a reporter, if present.
+func (rs *ReporterSet) Remove(reporter Reporter) {
+	for i, r := range rs.Reporters {
+		if r == reporter {
+			rs.Reporters = append(rs.Reporters[:i], rs.Reporters[i+1:]...)
+			return
+		}
+	}
+}
+
+// StartGeneration notifies every reporter of the start of a generation.
+func (rs *ReporterSet) StartGeneration(generation int) {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.StartGeneration(generation)
+	}
+}
+
+// EndGeneration notifies every reporter of the end of a generation.
+func (rs *ReporterSet) EndGeneration(config *Config, population map[int]*Genome, speciesSet *SpeciesSet) {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.EndGeneration(config, population, speciesSet)
+	}
+}
+
+// PostEvaluate notifies every reporter of a completed fitness evaluation.
+func (rs *ReporterSet) PostEvaluate(config *Config, population map[int]*Genome, speciesSet *SpeciesSet, best *Genome) {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.PostEvaluate(config, population, speciesSet, best)
+	}
+}
+
+// CompleteExtinction notifies every reporter of the extinction of all species.
+func (rs *ReporterSet) CompleteExtinction() {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.CompleteExtinction()
+	}
+}
+
+// FoundSolution notifies every reporter of a genome meeting the fitness threshold.
+func (rs *ReporterSet) FoundSolution(config *Config, generation int, best *Genome) {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.FoundSolution(config, generation, best)
+	}
+}
+
+// SpeciesStagnant notifies every reporter of a species removed for stagnation.
+func (rs *ReporterSet) SpeciesStagnant(speciesKey int, species *Species) {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.SpeciesStagnant(speciesKey, species)
+	}
+}
+
+// SpeciesSpared notifies every reporter of a stagnant species kept by species elitism. It is sent
+// every generation the species is spared, so StdOutReporter doesn't print it.
+func (rs *ReporterSet) SpeciesSpared(speciesKey int, species *Species, generation int) {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.SpeciesSpared(speciesKey, species, generation)
+	}
+}
+
+// Speciated notifies every reporter of the result of a generation's speciation.
+func (rs *ReporterSet) Speciated(generation int, result SpeciationResult) {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.Speciated(generation, result)
+	}
+}
+
+// PhaseFinished notifies every reporter of the wall time a phase of a generation took.
+func (rs *ReporterSet) PhaseFinished(generation int, phase Phase, elapsed time.Duration) {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.PhaseFinished(generation, phase, elapsed)
+	}
+}
+
+// GenomeSizes notifies every reporter of the genome sizes of an evaluated generation.
+func (rs *ReporterSet) GenomeSizes(generation int, sizes GenomeSizeStats) {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.GenomeSizes(generation, sizes)
+	}
+}
+
+// Info notifies every reporter of an informational message.
+func (rs *ReporterSet) Info(msg string) {
+	if rs == nil {
+		return
+	}
+	for _, r := range rs.Reporters {
+		r.Info(msg)
+	}
+}
+
+// --------------------------- StdOutReporter ---------------------------
+
+// StdOutReporter prints stagnation and informational messages to standard output.
+type StdOutReporter struct {
+	BaseReporter
+}
+
+// NewStdOutReporter creates a reporter that prints to standard output.
+func NewStdOutReporter() *StdOutReporter {
+	return &StdOutReporter{}
+}
+
+func (r *StdOutReporter) CompleteExtinction() {
+	fmt.Println("All species extinct.")
+}
+
+func (r *StdOutReporter) SpeciesStagnant(speciesKey int, species *Species) {
+	fmt.Printf("Species %d with %d members is stagnated: removing it\n", speciesKey, len(species.Members))
+}
+
+func (r *StdOutReporter) Info(msg string) {
+	fmt.Println(msg)
+}
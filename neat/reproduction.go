@@ -7,14 +7,32 @@ import (
 	"sort"
 )
 
+// ReproductionStrategy creates a population's genomes: the initial population, and each next
+// generation from the evaluated, speciated current one. *Reproduction, NEAT's scheme of offspring
+// allocation by species fitness with elitism, crossover and mutation, is the default, and
+// *NSGA2Reproduction its multi-objective variant (strategy = nsga2); install a custom strategy
+// (e.g. steady-state replacement) with Population.SetReproductionStrategy. Genome keys must be
+// unique within a run: take them from the population's Reproduction.NextKey to avoid clashes
+// with genomes it has created.
+type ReproductionStrategy interface {
+	CreateNew(genomeConfig *GenomeConfig, popSize int) map[int]*Genome
+	Reproduce(config *Config, speciesSet *SpeciesSet, popSize int, generation int) (map[int]*Genome, error)
+}
+
+var _ ReproductionStrategy = (*Reproduction)(nil)
+
 // Reproduction handles the creation of new genomes, either from scratch or through crossover and mutation.
 type Reproduction struct {
 	Config *ReproductionConfig
 	// GenomeIndexer func() int // Function removed, state stored in NextGenomeKey
 	NextGenomeKey int           // State for the next genome key
 	Ancestors     map[int][]int // Map genome key -> parent keys (for tracking lineage)
-	// Reporters   *reporting.ReporterSet // TODO: Add reporters later
-	Stagnation *Stagnation // Reference to stagnation info for filtering
+	AncestorBorn  map[int]int   // Map genome key -> generation that created it, for bounding Ancestors
+	Reporters     *ReporterSet  // Receives species stagnation notifications (nil discards them)
+	Stagnation    Stagnation    // Stagnation scheme used to filter species before reproduction
+	logger        Logger        // Destination for diagnostic output (nil = DefaultLogger)
+	reseedFrom    []*Genome     // Genomes a population reset after extinction is bred from (set by Population)
+	generation    int           // Generation being reproduced, recorded as the birth generation of new genomes
 }
 
 // nextGenomeKeyGenerator returns a function that generates sequential genome keys starting from 1.
@@ -36,17 +54,55 @@ func (r *Reproduction) getNextKey() int {
 	return key
 }
 
+// NextKey returns a new genome key, unique within the run, for genomes created outside
+// Reproduction, e.g. by a custom ReproductionStrategy.
+func (r *Reproduction) NextKey() int {
+	return r.getNextKey()
+}
+
 // NewReproduction creates a new reproduction manager.
-func NewReproduction(config *ReproductionConfig, stagnation *Stagnation) *Reproduction {
+func NewReproduction(config *ReproductionConfig, stagnation Stagnation) *Reproduction {
 	return &Reproduction{
 		Config: config,
 		// GenomeIndexer: nextGenomeKeyGenerator(), // Removed
 		NextGenomeKey: 1, // Start genome keys at 1
 		Ancestors:     make(map[int][]int),
+		AncestorBorn:  make(map[int]int),
 		Stagnation:    stagnation,
 	}
 }
 
+// recordAncestry stores the parents of a new genome, unless ancestry tracking is disabled.
+func (r *Reproduction) recordAncestry(key int, parents []int) {
+	if r.Config.NoAncestry {
+		return
+	}
+	if r.Ancestors == nil {
+		r.Ancestors = make(map[int][]int)
+	}
+	if r.AncestorBorn == nil {
+		r.AncestorBorn = make(map[int]int)
+	}
+	r.Ancestors[key] = parents
+	r.AncestorBorn[key] = r.generation
+}
+
+// pruneAncestry removes the lineage of genomes created more than ancestry_generations generations
+// ago, keeping entries of genomes still in population.
+func (r *Reproduction) pruneAncestry(population map[int]*Genome) {
+	keep := max(r.Config.AncestryGenerations, 1)
+	for key := range r.Ancestors {
+		if _, alive := population[key]; alive {
+			continue
+		}
+		if born, ok := r.AncestorBorn[key]; ok && born > r.generation-keep {
+			continue
+		}
+		delete(r.Ancestors, key)
+		delete(r.AncestorBorn, key)
+	}
+}
+
 // CreateNewPopulation creates an initial population of genomes.
 func (r *Reproduction) CreateNewPopulation(genomeConfig *GenomeConfig, popSize int) map[int]*Genome {
 	newGenomes := make(map[int]*Genome, popSize)
@@ -55,13 +111,82 @@ func (r *Reproduction) CreateNewPopulation(genomeConfig *GenomeConfig, popSize i
 		g := NewGenome(key, genomeConfig)
 		g.ConfigureNew() // Initialize nodes and connections based on config
 		newGenomes[key] = g
-		r.Ancestors[key] = []int{} // No parents for initial population
+		r.recordAncestry(key, []int{}) // No parents for initial population
 	}
 	return newGenomes
 }
 
+// CreateNew implements ReproductionStrategy with CreateNewPopulation.
+func (r *Reproduction) CreateNew(genomeConfig *GenomeConfig, popSize int) map[int]*Genome {
+	return r.CreateNewPopulation(genomeConfig, popSize)
+}
+
+// survivorCount returns how many of a species' n members (best first) form its parent pool:
+// survival_threshold of them, rounded up, bounded by min_survivors and max_survivors and by n.
+func survivorCount(config *ReproductionConfig, n int) int {
+	// The epsilon keeps float error from rounding e.g. 0.3*10 = 3.0000000000000004 up to 4.
+	count := int(math.Ceil(config.SurvivalThreshold*float64(n) - 1e-9))
+	count = max(count, max(config.MinSurvivors, 1)) // A species with members always has a parent
+	if config.MaxSurvivors > 0 {
+		count = min(count, config.MaxSurvivors)
+	}
+	return min(count, n)
+}
+
+// ReseedPopulation creates a population of popSize genomes in which round(fraction*popSize) genomes
+// descend from seeds: each seed is copied once and the remaining slots are filled with mutated
+// copies, cycling through the seeds in order. The rest are new random genomes, as from
+// CreateNewPopulation. All genomes get fresh keys and zero fitness.
+func (r *Reproduction) ReseedPopulation(genomeConfig *GenomeConfig, popSize int, seeds []*Genome, fraction float64) map[int]*Genome {
+	numSeeded := 0
+	if len(seeds) > 0 {
+		numSeeded = min(int(math.Round(fraction*float64(popSize))), popSize)
+	}
+	newGenomes := make(map[int]*Genome, popSize)
+	for i := 0; i < numSeeded; i++ {
+		seed := seeds[i%len(seeds)]
+		key := r.getNextKey()
+		g := seed.Copy()
+		g.Key = key
+		g.Config = genomeConfig
+		g.Fitness = 0
+		g.Fitnesses = nil
+		if i >= len(seeds) {
+			g.Mutate()
+		}
+		newGenomes[key] = g
+		r.recordAncestry(key, []int{seed.Key})
+	}
+	for key, g := range r.CreateNewPopulation(genomeConfig, popSize-numSeeded) {
+		newGenomes[key] = g
+	}
+	return newGenomes
+}
+
+// parentSelection orders a species' members, which arrive sorted by key, best first in place and
+// returns the function picking a parent from the survivors at the front.
+type parentSelection func(members []*Genome) func(rng *rand.Rand, parents []*Genome) *Genome
+
+// fitnessSelection ranks members by fitness, ties broken by the lower genome key as the sort is
+// stable, and picks parents uniformly.
+func fitnessSelection(members []*Genome) func(rng *rand.Rand, parents []*Genome) *Genome {
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].Fitness > members[j].Fitness
+	})
+	return func(rng *rand.Rand, parents []*Genome) *Genome {
+		return parents[rng.Intn(len(parents))]
+	}
+}
+
 // Reproduce creates the next generation of genomes based on the current species and their fitness.
 func (r *Reproduction) Reproduce(overallConfig *Config, speciesSet *SpeciesSet, popSize int, generation int) (map[int]*Genome, error) {
+	return r.reproduce(overallConfig, speciesSet, popSize, generation, fitnessSelection)
+}
+
+// reproduce creates the next generation, ranking species members and picking parents with
+// selection.
+func (r *Reproduction) reproduce(overallConfig *Config, speciesSet *SpeciesSet, popSize int, generation int, selection parentSelection) (map[int]*Genome, error) {
+	r.generation = generation
 
 	// --- Step 1: Evaluate Stagnation ---
 	stagnationInfo, err := r.Stagnation.Update(speciesSet, generation)
@@ -69,13 +194,16 @@ func (r *Reproduction) Reproduce(overallConfig *Config, speciesSet *SpeciesSet,
 		return nil, fmt.Errorf("failed to update stagnation: %w", err)
 	}
 
+	// With innovation reuse, identical splits within this generation share a node key.
+	overallConfig.Genome.Innovations().BeginGeneration(overallConfig.Experimental.InnovationReuse)
+	overallConfig.Genome.Innovations().TrackSplitOrigins(overallConfig.Experimental.StructuralAlignment)
+
 	// --- Step 2: Filter Species & Calculate Adjusted Fitness ---
 	allFitnesses := []float64{}
 	remainingSpecies := []*Species{}
 	for _, info := range stagnationInfo {
 		if info.IsStagnant {
-			// TODO: Report species stagnant (using reporter system later)
-			// fmt.Printf("Info: Species %d removed due to stagnation.\n", info.SpeciesID)
+			r.Reporters.SpeciesStagnant(info.SpeciesID, info.Species)
 		} else {
 			sp := info.Species
 			memberFitnesses := sp.GetFitnesses()
@@ -90,11 +218,24 @@ func (r *Reproduction) Reproduce(overallConfig *Config, speciesSet *SpeciesSet,
 	}
 
 	if len(remainingSpecies) == 0 {
-		// TODO: Handle extinction (reset population?)
-		fmt.Println("Error: All species became extinct!")
-		// Based on config.Neat.ResetOnExtinction, might need to create a new population here.
-		// For now, return empty.
-		return make(map[int]*Genome), nil
+		return r.handleExtinction(overallConfig, speciesSet, popSize, generation)
+	}
+
+	// Spawn allocation summarizes member fitness with spawn_fitness_func, falling back to the
+	// stagnation scheme's species_fitness_func.
+	spawnStatName := r.Config.SpawnFitnessFunc
+	if spawnStatName == "" {
+		spawnStatName = overallConfig.Stagnation.SpeciesFitnessFunc
+	}
+	spawnStat := StatFunctions[spawnStatName]
+
+	// With fitness scaling, allocation uses species fitness recomputed from scaled member fitness.
+	scaledFitnesses, scaledMembers, err := scaledSpeciesFitness(r.Config, remainingSpecies, spawnStat)
+	if err != nil {
+		return nil, err
+	}
+	if scaledMembers != nil {
+		allFitnesses = scaledMembers
 	}
 
 	// Calculate adjusted fitness based on fitness sharing
@@ -103,10 +244,15 @@ func (r *Reproduction) Reproduce(overallConfig *Config, speciesSet *SpeciesSet,
 	fitnessRange := math.Max(1.0, maxFitness-minFitness) // Avoid division by zero, ensure range >= 1.0
 
 	adjustedFitnessSum := 0.0
-	for _, sp := range remainingSpecies {
-		// Use the species fitness calculated during stagnation update
-		meanSpeciesFitness := sp.Fitness
-		adjustedFitness := (meanSpeciesFitness - minFitness) / fitnessRange
+	for i, sp := range remainingSpecies {
+		// Use the species fitness calculated during stagnation update unless a separate statistic is configured
+		speciesFitness := sp.Fitness
+		if scaledFitnesses != nil {
+			speciesFitness = scaledFitnesses[i]
+		} else if r.Config.SpawnFitnessFunc != "" {
+			speciesFitness = spawnStat(sp.GetFitnesses())
+		}
+		adjustedFitness := (speciesFitness - minFitness) / fitnessRange
 		sp.AdjustedFitness = adjustedFitness
 		adjustedFitnessSum += adjustedFitness
 	}
@@ -124,11 +270,10 @@ func (r *Reproduction) Reproduce(overallConfig *Config, speciesSet *SpeciesSet,
 	// (ensures elite slots don't artificially inflate perceived spawn capacity)
 	spawnMinSize := max(minSpeciesSize, r.Config.Elitism)
 
-	spawnAmounts := computeSpawnAmounts(adjustedFitnesses, adjustedFitnessSum, previousSizes, popSize, spawnMinSize)
+	spawnAmounts := computeSpawnAmounts(adjustedFitnesses, adjustedFitnessSum, previousSizes, popSize, spawnMinSize, overallConfig.Experimental.NewSpawnAllocation, loggerOrDefault(r.logger))
 
 	// --- Step 4: Create New Population ---
 	newPopulation := make(map[int]*Genome)
-	newAncestors := make(map[int][]int)
 
 	for i, sp := range remainingSpecies {
 		spawn := spawnAmounts[i]
@@ -138,22 +283,22 @@ func (r *Reproduction) Reproduce(overallConfig *Config, speciesSet *SpeciesSet,
 			continue // Should not happen if spawnMinSize >= 1, but safety check
 		}
 
-		// Sort old members by fitness (descending) for elitism and parent selection.
+		// Rank old members, best first, for elitism and parent selection. They start out in key
+		// order, so the ranking doesn't depend on map iteration.
 		oldMembers := make([]*Genome, 0, len(sp.Members))
 		for _, g := range sp.Members {
 			oldMembers = append(oldMembers, g)
 		}
-		sort.Slice(oldMembers, func(i, j int) bool {
-			return oldMembers[i].Fitness > oldMembers[j].Fitness
-		})
+		sort.Slice(oldMembers, func(i, j int) bool { return oldMembers[i].Key < oldMembers[j].Key })
+		selectParent := selection(oldMembers)
 
 		// Transfer elites.
 		elitesTaken := 0
 		if r.Config.Elitism > 0 {
 			for j := 0; j < r.Config.Elitism && j < len(oldMembers); j++ {
-				eliteGenome := oldMembers[j]
-				newPopulation[eliteGenome.Key] = eliteGenome           // Transfer directly
-				newAncestors[eliteGenome.Key] = []int{eliteGenome.Key} // Mark as its own ancestor for tracking
+				// Copy so the new generation never shares gene maps with the old one.
+				eliteGenome := oldMembers[j].Copy()
+				newPopulation[eliteGenome.Key] = eliteGenome // Elites keep their original lineage entry
 				elitesTaken++
 			}
 		}
@@ -163,29 +308,22 @@ func (r *Reproduction) Reproduce(overallConfig *Config, speciesSet *SpeciesSet,
 		}
 
 		// Determine parents for remaining spawn.
-		survivalCutoff := int(math.Ceil(r.Config.SurvivalThreshold * float64(len(oldMembers))))
-		survivalCutoff = max(survivalCutoff, 2) // Need at least two parents
-		if survivalCutoff > len(oldMembers) {
-			survivalCutoff = len(oldMembers)
-		}
-		if survivalCutoff < 1 && len(oldMembers) > 0 {
-			survivalCutoff = 1
-		} // Handle edge case where threshold is 0 but members exist
-
-		parents := oldMembers[:survivalCutoff]
+		parents := oldMembers[:survivorCount(r.Config, len(oldMembers))]
 
 		if len(parents) == 0 {
 			// This should only happen if a species survives stagnation/filtering but has 0 members
 			// or if survival threshold is extremely low. Skip spawning for this species.
-			fmt.Printf("Warning: No parents available for species %d despite spawn > 0.\n", sp.Key)
+			loggerOrDefault(r.logger).Warn("No parents available for species despite spawn > 0", "species", sp.Key)
 			continue
 		}
 
-		// Produce offspring.
+		// Produce offspring. Parents are sampled from a per-species stream so that other species
+		// appearing or disappearing doesn't change which parents this species picks.
+		rng := speciesRand(overallConfig.Neat.Seed, generation, sp.Key)
 		for j := 0; j < spawn; j++ {
-			// Select parents randomly from the surviving pool.
-			parent1 := parents[rand.Intn(len(parents))]
-			parent2 := parents[rand.Intn(len(parents))]
+			// Select parents from the surviving pool.
+			parent1 := selectParent(rng, parents)
+			parent2 := selectParent(rng, parents)
 
 			// Create child genome.
 			childKey := r.getNextKey() // Use method now
@@ -194,21 +332,47 @@ func (r *Reproduction) Reproduce(overallConfig *Config, speciesSet *SpeciesSet,
 			child.Mutate()
 
 			newPopulation[childKey] = child
-			newAncestors[childKey] = []int{parent1.Key, parent2.Key}
+			r.recordAncestry(childKey, []int{parent1.Key, parent2.Key})
 		}
 	}
-	r.Ancestors = newAncestors // Update ancestor tracking for the new generation
+	r.pruneAncestry(newPopulation) // Drop lineage older than ancestry_generations
+	for _, g := range newPopulation {
+		g.advanceGeneration() // Elites too, so they age and decay like their offspring
+	}
 
 	// Final check: if population size is drastically different from target, log warning?
 	if len(newPopulation) != popSize {
-		fmt.Printf("Warning: New population size (%d) differs from target (%d).\n", len(newPopulation), popSize)
+		loggerOrDefault(r.logger).Warn("New population size differs from target", "size", len(newPopulation), "target", popSize)
 	}
 
 	return newPopulation, nil
 }
 
+// speciesRand returns the random source used for a species' parent sampling in a generation.
+// With a master seed, the sub-seed is derived from the seed, generation and species key alone;
+// without one (seed 0), the source is seeded from the global generator.
+func speciesRand(seed int64, generation, speciesKey int) *rand.Rand {
+	if seed == 0 {
+		return rand.New(rand.NewSource(rand.Int63()))
+	}
+	h := splitMix64(uint64(seed))
+	h = splitMix64(h ^ uint64(generation))
+	h = splitMix64(h ^ uint64(speciesKey))
+	return rand.New(rand.NewSource(int64(h)))
+}
+
+// splitMix64 is the SplitMix64 finalizer, used to scramble seed components.
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
 // computeSpawnAmounts calculates the number of offspring each species should produce.
-func computeSpawnAmounts(adjustedFitnesses []float64, adjustedFitnessSum float64, previousSizes []int, popSize int, minSpeciesSize int) []int {
+// With largestRemainder set, the damped amounts are scaled to popSize by largestRemainderAllocation
+// instead of rounding followed by random corrections.
+func computeSpawnAmounts(adjustedFitnesses []float64, adjustedFitnessSum float64, previousSizes []int, popSize int, minSpeciesSize int, largestRemainder bool, logger Logger) []int {
 	spawnAmounts := make([]int, len(adjustedFitnesses))
 
 	for i, af := range adjustedFitnesses {
@@ -238,6 +402,10 @@ func computeSpawnAmounts(adjustedFitnesses []float64, adjustedFitnessSum float64
 		spawnAmounts[i] = max(minSpeciesSize, spawn) // Ensure minimum size again after adjustment
 	}
 
+	if largestRemainder {
+		return largestRemainderAllocation(spawnAmounts, popSize, minSpeciesSize)
+	}
+
 	// Normalize spawn amounts to match the target population size.
 	totalSpawn := 0
 	for _, sa := range spawnAmounts {
@@ -247,7 +415,7 @@ func computeSpawnAmounts(adjustedFitnesses []float64, adjustedFitnessSum float64
 	if totalSpawn == 0 {
 		// Avoid division by zero if somehow totalSpawn is 0
 		// Assign minimum to all species? This case shouldn't happen if minSpeciesSize >= 1.
-		fmt.Println("Warning: Total spawn calculated as 0. Assigning minimum size to all species.")
+		logger.Warn("Total spawn calculated as 0, assigning minimum size to all species")
 		for i := range spawnAmounts {
 			spawnAmounts[i] = minSpeciesSize
 		}
@@ -294,9 +462,57 @@ func computeSpawnAmounts(adjustedFitnesses []float64, adjustedFitnessSum float64
 		}
 		// If diff still not zero (e.g., couldn't reduce enough due to min size), log warning.
 		if diff != 0 {
-			fmt.Printf("Warning: Could not exactly match pop_size after spawn normalization. Final size may differ slightly.\n")
+			logger.Warn("Could not exactly match pop_size after spawn normalization, final size may differ slightly", "difference", diff)
 		}
 	}
 
 	return finalSpawnAmounts
 }
+
+// largestRemainderAllocation scales amounts to sum to popSize: every species gets the floor of its
+// share (at least minSpeciesSize) and the leftover slots go to the largest fractional remainders.
+// Ties are broken by index, so the result is deterministic. The total exceeds popSize only when
+// the minimum sizes alone do.
+func largestRemainderAllocation(amounts []int, popSize int, minSpeciesSize int) []int {
+	allocation := make([]int, len(amounts))
+	if len(amounts) == 0 {
+		return allocation
+	}
+	total := 0
+	for _, a := range amounts {
+		total += a
+	}
+	remainders := make([]float64, len(amounts))
+	allocated := 0
+	for i, a := range amounts {
+		share := float64(popSize) / float64(len(amounts))
+		if total > 0 {
+			share = float64(a) * float64(popSize) / float64(total)
+		}
+		allocation[i] = max(minSpeciesSize, int(math.Floor(share)))
+		remainders[i] = share - math.Floor(share)
+		allocated += allocation[i]
+	}
+
+	order := allIndices(len(amounts))
+	sort.SliceStable(order, func(i, j int) bool { return remainders[order[i]] > remainders[order[j]] })
+	for i := 0; allocated < popSize; i = (i + 1) % len(order) {
+		allocation[order[i]]++
+		allocated++
+	}
+	// Minimum sizes may have pushed the total over popSize; take back from the largest species.
+	for allocated > popSize {
+		largest := 0
+		for i := range allocation {
+			if allocation[i] > allocation[largest] {
+				largest = i
+			}
+		}
+		if allocation[largest] <= minSpeciesSize {
+			break
+		}
+		allocation[largest]--
+		allocated--
+	}
+	return allocation
+}
@@ -0,0 +1,139 @@
+package neat
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// RunOption configures Population.Run.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	maxGenerations  int
+	resumeFrom      string
+	checkpointPath  string
+	checkpointEvery int
+}
+
+// MaxGenerations ends the run once the generation counter reaches n. The counter includes the
+// generations run before a resumed checkpoint, so a run of 300 generations interrupted at 120
+// runs 180 more when resumed. 0 (the default) runs until the fitness threshold is met.
+func MaxGenerations(n int) RunOption {
+	return func(o *runOptions) { o.maxGenerations = n }
+}
+
+// ResumeFrom continues the run saved in the checkpoint at path, if the file exists; otherwise the
+// run starts from the population as it is. The checkpoint is loaded around the population's
+// config, which must come from the same config file, and replaces its genomes, species, generation
+// counter, best genome and hall of fame. Reporters, hooks, the logger, the stagnation scheme, the
+// speciation policy and the curriculum set on the population are kept; recorded statistics are
+// restored into Population.Statistics, so they cover the whole run.
+func ResumeFrom(path string) RunOption {
+	return func(o *runOptions) { o.resumeFrom = path }
+}
+
+// CheckpointEvery saves a checkpoint to path, replacing the previous one, after every n-th
+// generation and when the run ends, so an interrupted run can continue with ResumeFrom(path).
+func CheckpointEvery(n int, path string) RunOption {
+	return func(o *runOptions) { o.checkpointEvery, o.checkpointPath = n, path }
+}
+
+// Run evolves the population with fitnessFunc until the fitness threshold is met, the generation
+// counter reaches MaxGenerations or every species dies out (ErrExtinction). It returns the winner
+// if the fitness threshold was met and nil otherwise; Population.BestGenome holds the best genome
+// either way. A population that already meets its termination criteria, e.g. one resumed from the
+// checkpoint of a finished run, returns immediately without running another generation.
+func (p *Population) Run(fitnessFunc FitnessFunc, opts ...RunOption) (*Genome, error) {
+	var o runOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxGenerations < 0 {
+		return nil, fmt.Errorf("max generations cannot be negative")
+	}
+	if o.checkpointPath != "" && o.checkpointEvery <= 0 {
+		return nil, fmt.Errorf("checkpoint interval must be positive")
+	}
+	if o.resumeFrom != "" {
+		if err := p.resume(o.resumeFrom); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		if p.thresholdMet() {
+			return p.BestGenome, p.runCheckpoint(&o, true)
+		}
+		if o.maxGenerations > 0 && p.Generation >= o.maxGenerations {
+			return nil, p.runCheckpoint(&o, true)
+		}
+		winner, err := p.RunGeneration(fitnessFunc)
+		if err != nil {
+			return nil, err
+		}
+		if winner != nil {
+			return winner, p.runCheckpoint(&o, true)
+		}
+		if err := p.runCheckpoint(&o, false); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// runCheckpoint saves the run's checkpoint if one is due: after every checkpointEvery-th
+// generation, and at the end of the run.
+func (p *Population) runCheckpoint(o *runOptions, final bool) error {
+	if o.checkpointPath == "" || (!final && p.Generation%o.checkpointEvery != 0) {
+		return nil
+	}
+	return p.SaveCheckpoint(o.checkpointPath)
+}
+
+// resume replaces the population's evolutionary state with the checkpoint at path, if it exists.
+func (p *Population) resume(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		p.log().Info("No checkpoint to resume from, starting a new run", "path", path)
+		return nil
+	}
+	loaded, err := loadCheckpoint(path, p.Config)
+	if err != nil {
+		return err
+	}
+
+	policy := p.SpeciesSet.policy
+	p.Population = loaded.Population
+	p.SpeciesSet = loaded.SpeciesSet
+	p.SpeciesSet.logger = p.Logger
+	p.SpeciesSet.policy = policy
+	p.Reproduction = loaded.Reproduction
+	p.Reproduction.Reporters = p.Reporters
+	p.Reproduction.Stagnation = p.Stagnation
+	p.Reproduction.logger = p.Logger
+	p.Generation = loaded.Generation
+	p.BestGenome = loaded.BestGenome
+	p.HallOfFame = loaded.HallOfFame
+	p.configChanges = loaded.configChanges
+	p.retired = nil
+	if loaded.Ratings != nil {
+		p.Ratings = loaded.Ratings
+	}
+	switch {
+	case loaded.Statistics == nil:
+	case p.Statistics != nil:
+		*p.Statistics = *loaded.Statistics // Keep the caller's pointer valid
+	default:
+		p.Statistics = loaded.Statistics
+	}
+	if loaded.restoredCurriculum != nil {
+		p.restoredCurriculum = loaded.restoredCurriculum
+		if p.Curriculum != nil {
+			if err := p.SetCurriculum(p.Curriculum); err != nil {
+				return err
+			}
+		}
+	}
+	p.log().Info("Resumed from checkpoint", "path", path, "generation", p.Generation)
+	return nil
+}
@@ -0,0 +1,144 @@
+package neat
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FitnessScalingFunc transforms the raw fitness of every reproducing genome into the values used
+// for spawn allocation. It must return one value per input, in the same order.
+type FitnessScalingFunc func(fitnesses []float64, config *ReproductionConfig) []float64
+
+// fitnessScalings maps fitness_scaling names to their transforms. "none" leaves fitness unchanged.
+var fitnessScalings = map[string]FitnessScalingFunc{
+	"none":      nil,
+	"rank":      rankScaling,
+	"sigma":     sigmaScaling,
+	"boltzmann": boltzmannScaling,
+}
+
+var fitnessScalingMu sync.RWMutex
+
+// RegisterFitnessScaling adds a custom fitness scaling under name (case-insensitive), making it
+// usable as fitness_scaling. Register scalings before loading the config that refers to them.
+// Existing names cannot be replaced.
+func RegisterFitnessScaling(name string, fn FitnessScalingFunc) error {
+	if err := validateFunctionName(name); err != nil {
+		return fmt.Errorf("invalid fitness scaling name: %w", err)
+	}
+	if fn == nil {
+		return fmt.Errorf("fitness scaling '%s' is nil", name)
+	}
+	name = strings.ToLower(name)
+	fitnessScalingMu.Lock()
+	defer fitnessScalingMu.Unlock()
+	if _, exists := fitnessScalings[name]; exists {
+		return fmt.Errorf("fitness scaling '%s' is already registered", name)
+	}
+	fitnessScalings[name] = fn
+	return nil
+}
+
+// lookupFitnessScaling returns the scaling registered under name and whether it exists.
+func lookupFitnessScaling(name string) (FitnessScalingFunc, bool) {
+	fitnessScalingMu.RLock()
+	defer fitnessScalingMu.RUnlock()
+	fn, ok := fitnessScalings[name]
+	return fn, ok
+}
+
+// fitnessScalingNames returns the registered scaling names, sorted.
+func fitnessScalingNames() []string {
+	fitnessScalingMu.RLock()
+	defer fitnessScalingMu.RUnlock()
+	names := make([]string, 0, len(fitnessScalings))
+	for name := range fitnessScalings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rankScaling replaces each fitness by its rank (1 = lowest). Tied fitnesses share their mean rank,
+// so only the ordering of genomes matters, not how far apart their fitnesses are.
+func rankScaling(fitnesses []float64, _ *ReproductionConfig) []float64 {
+	order := make([]int, len(fitnesses))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return fitnesses[order[a]] < fitnesses[order[b]] })
+
+	scaled := make([]float64, len(fitnesses))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && fitnesses[order[end]] == fitnesses[order[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2 // Mean of the 1-based ranks start+1 .. end
+		for _, i := range order[start:end] {
+			scaled[i] = rank
+		}
+		start = end
+	}
+	return scaled
+}
+
+// sigmaScaling maps fitness f to max(0, 1 + (f - mean) / (2 * stdev)), which keeps selection
+// pressure constant as the spread of the population's fitness changes. Without spread, every
+// genome gets 1.
+func sigmaScaling(fitnesses []float64, _ *ReproductionConfig) []float64 {
+	mean := Mean(fitnesses)
+	stdev := Stdev(fitnesses)
+	scaled := make([]float64, len(fitnesses))
+	for i, f := range fitnesses {
+		if stdev == 0 {
+			scaled[i] = 1
+			continue
+		}
+		scaled[i] = math.Max(0, 1+(f-mean)/(2*stdev))
+	}
+	return scaled
+}
+
+// boltzmannScaling maps fitness f to exp((f - max) / boltzmann_temperature). Subtracting the
+// maximum keeps the values in (0, 1] without changing their ratios.
+func boltzmannScaling(fitnesses []float64, config *ReproductionConfig) []float64 {
+	maxFitness := MaxFloat(fitnesses)
+	scaled := make([]float64, len(fitnesses))
+	for i, f := range fitnesses {
+		scaled[i] = math.Exp((f - maxFitness) / config.BoltzmannTemperature)
+	}
+	return scaled
+}
+
+// scaledSpeciesFitness applies the configured fitness scaling to the members of all species and
+// summarizes each species' scaled member fitnesses with speciesFitness. It returns the per-species
+// values, in the order of species, and every scaled member fitness. With scaling "none" it returns nil.
+func scaledSpeciesFitness(config *ReproductionConfig, species []*Species, speciesFitness func([]float64) float64) ([]float64, []float64, error) {
+	scale, _ := lookupFitnessScaling(config.FitnessScaling)
+	if scale == nil {
+		return nil, nil, nil
+	}
+	raw := []float64{}
+	counts := make([]int, len(species))
+	for i, sp := range species {
+		fitnesses := sp.GetFitnesses()
+		raw = append(raw, fitnesses...)
+		counts[i] = len(fitnesses)
+	}
+	scaled := scale(raw, config)
+	if len(scaled) != len(raw) {
+		return nil, nil, fmt.Errorf("fitness scaling '%s' returned %d values for %d genomes", config.FitnessScaling, len(scaled), len(raw))
+	}
+
+	perSpecies := make([]float64, len(species))
+	offset := 0
+	for i, n := range counts {
+		perSpecies[i] = speciesFitness(scaled[offset : offset+n])
+		offset += n
+	}
+	return perSpecies, scaled, nil
+}
@@ -0,0 +1,149 @@
+package neat
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Schedule makes an adjustable parameter (see AdjustableConfigKeys) follow a curve over the
+// generations of a run, e.g. to anneal weight_mutate_power. Kind selects the curve:
+//
+//   - "linear": from Start at generation 1 to End at generation 1+Generations, then End.
+//   - "exponential": geometric interpolation from Start to End over Generations, then End.
+//     Start and End must be non-zero and of the same sign.
+//   - "step": Start, multiplied by Factor every Generations generations.
+//
+// Values are clamped to the parameter's allowed range, and rounded for integer parameters.
+type Schedule struct {
+	Key         string  // ini key of the scheduled parameter
+	Kind        string  // "linear", "exponential" or "step"
+	Start       float64 // Value at generation 1
+	End         float64 // Final value (linear, exponential)
+	Generations int     // Length of the ramp (linear, exponential) or of each step (step)
+	Factor      float64 // Multiplier applied at every step (step)
+}
+
+// Validate checks that the schedule names an adjustable parameter and describes a valid curve.
+func (s Schedule) Validate() error {
+	if _, ok := adjustableParams[s.Key]; !ok {
+		return configErrorf(s.Key, "'%s' cannot be scheduled, must be one of %s", s.Key, strings.Join(AdjustableConfigKeys(), ", "))
+	}
+	if s.Generations <= 0 {
+		return configErrorf(s.Key, "schedule for %s needs a positive number of generations", s.Key)
+	}
+	switch s.Kind {
+	case "linear":
+	case "exponential":
+		if s.Start == 0 || s.End == 0 || (s.Start < 0) != (s.End < 0) {
+			return configErrorf(s.Key, "exponential schedule for %s needs non-zero start and end of the same sign", s.Key)
+		}
+	case "step":
+		if s.Factor < 0 {
+			return configErrorf(s.Key, "step schedule for %s cannot have a negative factor", s.Key)
+		}
+	default:
+		return configErrorf(s.Key, "invalid schedule kind '%s' for %s, must be one of linear, exponential, step", s.Kind, s.Key)
+	}
+	return nil
+}
+
+// Value returns the scheduled value for the given generation (1 for the first), clamped to the
+// parameter's range and rounded for integer parameters.
+func (s Schedule) Value(generation int) float64 {
+	t := float64(max(generation-1, 0))
+	progress := math.Min(t/float64(s.Generations), 1)
+	var v float64
+	switch s.Kind {
+	case "linear":
+		v = s.Start + (s.End-s.Start)*progress
+	case "exponential":
+		v = s.Start * math.Pow(s.End/s.Start, progress)
+	case "step":
+		v = s.Start * math.Pow(s.Factor, math.Floor(t/float64(s.Generations)))
+	}
+	param := adjustableParams[s.Key]
+	v = math.Min(math.Max(v, param.min), param.max)
+	if param.int != nil {
+		v = math.Round(v)
+	}
+	return v
+}
+
+// String formats the schedule as it is written in the [Schedules] config section.
+func (s Schedule) String() string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+	if s.Kind == "step" {
+		return fmt.Sprintf("step %s %s %d", f(s.Start), f(s.Factor), s.Generations)
+	}
+	return fmt.Sprintf("%s %s %s %d", s.Kind, f(s.Start), f(s.End), s.Generations)
+}
+
+// ParseSchedule parses a schedule for key written as in the [Schedules] config section:
+// "linear <start> <end> <generations>", "exponential <start> <end> <generations>" or
+// "step <start> <factor> <every>".
+func ParseSchedule(key, value string) (Schedule, error) {
+	fields := strings.Fields(strings.ToLower(cleanIniString(value)))
+	if len(fields) != 4 {
+		return Schedule{}, configErrorf(key, "invalid schedule '%s' for %s, expected '<kind> <a> <b> <generations>'", value, key)
+	}
+	a, errA := strconv.ParseFloat(fields[1], 64)
+	b, errB := strconv.ParseFloat(fields[2], 64)
+	n, errN := strconv.Atoi(fields[3])
+	if errA != nil || errB != nil || errN != nil {
+		return Schedule{}, configErrorf(key, "invalid schedule '%s' for %s, expected two numbers and a whole number of generations", value, key)
+	}
+	s := Schedule{Key: key, Kind: fields[0], Start: a, End: b, Generations: n}
+	if s.Kind == "step" {
+		s.End, s.Factor = 0, b
+	}
+	return s, s.Validate()
+}
+
+// loadScheduleConfig reads the [Schedules] section: one schedule per adjustable parameter.
+func loadScheduleConfig(section *ini.Section, config *Config) error {
+	for _, key := range section.Keys() {
+		s, err := ParseSchedule(key.Name(), key.Value())
+		if err != nil {
+			return err
+		}
+		config.Schedules = append(config.Schedules, s)
+	}
+	return nil
+}
+
+// AddSchedule validates s and appends it to the population's schedules, replacing any schedule
+// of the same parameter. Like the [Schedules] config section it takes effect at the start of the
+// next generation, overriding values set with UpdateConfig; schedules added this way are not
+// saved in checkpoints, so add them again after loading one.
+func (p *Population) AddSchedule(s Schedule) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	for i := range p.Config.Schedules {
+		if p.Config.Schedules[i].Key == s.Key {
+			p.Config.Schedules[i] = s
+			return nil
+		}
+	}
+	p.Config.Schedules = append(p.Config.Schedules, s)
+	return nil
+}
+
+// applySchedules sets every scheduled parameter to its value for the current generation.
+func (p *Population) applySchedules() error {
+	if len(p.Config.Schedules) == 0 {
+		return nil
+	}
+	patch := make(ConfigPatch, len(p.Config.Schedules))
+	for _, s := range p.Config.Schedules {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		patch[s.Key] = s.Value(p.Generation)
+	}
+	return p.UpdateConfig(patch)
+}
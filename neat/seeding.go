@@ -0,0 +1,81 @@
+package neat
+
+import "fmt"
+
+// NewPopulationFromGenomes creates a population bred from existing genomes instead of random ones,
+// e.g. winners of an earlier run or hand-designed topologies. Every seed is copied once and the
+// remaining pop_size slots are filled with mutated copies, cycling through the seeds in order.
+// The seeds themselves are not modified; the copies get fresh keys, zero fitness and config.
+func NewPopulationFromGenomes(config *Config, seeds []*Genome) (*Population, error) {
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("at least one seed genome is required")
+	}
+	if len(seeds) > config.Neat.PopSize {
+		return nil, fmt.Errorf("%d seed genomes exceed pop_size (%d)", len(seeds), config.Neat.PopSize)
+	}
+	for _, seed := range seeds {
+		if err := checkSeedGenome(seed, &config.Genome); err != nil {
+			return nil, err
+		}
+		config.Genome.Innovations().ReserveGenome(seed)
+	}
+
+	p, err := NewPopulation(config)
+	if err != nil {
+		return nil, err
+	}
+	// Replace the random initial population, keeping the key sequence NewPopulation started.
+	p.Reproduction.NextGenomeKey = config.Neat.KeyOffset + 1
+	p.Reproduction.Ancestors = make(map[int][]int)
+	p.Reproduction.AncestorBorn = make(map[int]int)
+	p.Population = p.Reproduction.ReseedPopulation(&config.Genome, config.Neat.PopSize, seeds, 1)
+	return p, nil
+}
+
+// InjectGenome adds a copy of g to the current population, to be evaluated and speciated with it
+// in the next generation. The population exceeds pop_size until the next reproduction, which
+// returns it to pop_size. The copy gets a fresh key and zero fitness and is returned.
+func (p *Population) InjectGenome(g *Genome) (*Genome, error) {
+	if err := checkSeedGenome(g, &p.Config.Genome); err != nil {
+		return nil, err
+	}
+	p.Config.Genome.Innovations().ReserveGenome(g)
+	key := p.Reproduction.getNextKey()
+	injected := g.Copy()
+	injected.Key = key
+	injected.Config = &p.Config.Genome
+	injected.Fitness = 0
+	injected.Fitnesses = nil
+	p.Population[key] = injected
+	p.Reproduction.recordAncestry(key, []int{}) // Foreign genomes have no parents in this run
+	return injected, nil
+}
+
+// checkSeedGenome verifies that g fits the genome config: it has every output node and its
+// connections only use the configured inputs and its own nodes.
+func checkSeedGenome(g *Genome, gc *GenomeConfig) error {
+	if g == nil {
+		return fmt.Errorf("seed genome is nil")
+	}
+	for _, key := range gc.OutputKeys {
+		if _, ok := g.Nodes[key]; !ok {
+			return fmt.Errorf("seed genome %d lacks output node %d", g.Key, key)
+		}
+	}
+	for key := range g.Nodes {
+		if key < 0 {
+			return fmt.Errorf("seed genome %d has a node gene for input %d", g.Key, key)
+		}
+	}
+	for ck := range g.Connections {
+		for _, key := range []int{ck.InNodeID, ck.OutNodeID} {
+			if gc.IsInputKey(key) {
+				continue
+			}
+			if _, ok := g.Nodes[key]; !ok {
+				return fmt.Errorf("seed genome %d: connection %v references unknown node %d", g.Key, ck, key)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package neat
+
+import "math"
+
+// signatureFilter skips exact distance computations during speciation using MinHash signatures
+// of the genomes' connection keys. The fraction of signature entries two genomes share estimates
+// the Jaccard similarity of their connection sets, and with it the disjoint term of their
+// distance; that term alone can rule a species out, so the full distance is only computed for
+// pairs whose estimate, less the margin, can still beat the threshold or the best match so far.
+// Assignments stay the same except when the estimate is off by more than the margin.
+type signatureFilter struct {
+	size       int
+	margin     float64
+	config     *GenomeConfig
+	signatures map[*Genome][]uint64
+}
+
+// newSignatureFilter returns the filter configured by speciation_signature_size, or nil if disabled.
+func newSignatureFilter(config *SpeciesSetConfig, genomeConfig *GenomeConfig) *signatureFilter {
+	if config.SignatureSize <= 0 {
+		return nil
+	}
+	return &signatureFilter{
+		size:       config.SignatureSize,
+		margin:     config.SignatureMargin,
+		config:     genomeConfig,
+		signatures: make(map[*Genome][]uint64),
+	}
+}
+
+// signature returns the MinHash signature of g: for each of size hash functions, the smallest hash
+// of any of its connection keys.
+func (f *signatureFilter) signature(g *Genome) []uint64 {
+	if sig, ok := f.signatures[g]; ok {
+		return sig
+	}
+	sig := make([]uint64, f.size)
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for key := range g.Connections {
+		h := mix64(uint64(uint32(key.InNodeID))<<32 | uint64(uint32(key.OutNodeID)))
+		for i := range sig {
+			if v := mix64(h + uint64(i)*0x9e3779b97f4a7c15); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	f.signatures[g] = sig
+	return sig
+}
+
+// mix64 is the splitmix64 finalizer, a cheap well-distributed 64-bit hash.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// lowerBound estimates the disjoint term of the distance between a and b, which the full distance
+// is never below, and subtracts the margin for the estimation error.
+func (f *signatureFilter) lowerBound(a, b *Genome) float64 {
+	na, nb := len(a.Connections), len(b.Connections)
+	if na == 0 && nb == 0 {
+		return 0
+	}
+	sigA, sigB := f.signature(a), f.signature(b)
+	same := 0
+	for i := range sigA {
+		if sigA[i] == sigB[i] {
+			same++
+		}
+	}
+	j := float64(same) / float64(f.size) // Estimated |A∩B| / |A∪B|
+	// |A|+|B| = |A∪B| + |A∩B| = |A∪B|(1+j), and the disjoint genes are |A∪B|(1-j).
+	disjoint := float64(na+nb) * (1 - j) / (1 + j)
+	return f.config.CompatibilityDisjointCoefficient*disjoint/float64(max(max(na, nb), 1)) - f.margin
+}
+
+// mayBeWithin reports whether the distance between a and b may be below limit, i.e. whether it
+// has to be computed.
+func (f *signatureFilter) mayBeWithin(a, b *Genome, limit float64) bool {
+	return f == nil || f.lowerBound(a, b) < limit
+}
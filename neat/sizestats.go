@@ -0,0 +1,71 @@
+package neat
+
+import "unsafe"
+
+// SizeRange summarizes a count over the genomes of a population.
+type SizeRange struct {
+	Min  int
+	Mean float64
+	Max  int
+}
+
+// GenomeSizeStats summarizes the size of the genomes of a population, to make bloat visible
+// before it slows a run down.
+type GenomeSizeStats struct {
+	Nodes               SizeRange
+	EnabledConnections  SizeRange
+	DisabledConnections SizeRange
+	// Approximate memory held by the genomes in bytes: genes, gene maps and per-genome slices,
+	// but not the networks built from them or memory shared between genomes, such as the config.
+	Bytes int64
+}
+
+// Per-entry overhead of a Go map, roughly: a control byte and the spare slots kept free by its
+// load factor.
+const mapEntryOverhead = 8
+
+// MemoryFootprint returns the approximate number of bytes held by the genome, counted as in
+// GenomeSizeStats.Bytes.
+func (g *Genome) MemoryFootprint() int64 {
+	bytes := int64(unsafe.Sizeof(*g))
+	nodeEntry := int64(unsafe.Sizeof(0)+unsafe.Sizeof(g)) + mapEntryOverhead
+	bytes += int64(len(g.Nodes)) * (nodeEntry + int64(unsafe.Sizeof(NodeGene{})))
+	connEntry := int64(unsafe.Sizeof(ConnectionKey{})+unsafe.Sizeof(g)) + mapEntryOverhead
+	bytes += int64(len(g.Connections)) * (connEntry + int64(unsafe.Sizeof(ConnectionGene{})))
+	bytes += int64(cap(g.sortedConns)) * int64(unsafe.Sizeof(g))
+	bytes += int64(cap(g.Fitnesses)+cap(g.Behavior)) * int64(unsafe.Sizeof(0.0))
+	return bytes
+}
+
+// MeasureGenomeSizes returns the size statistics of population.
+func MeasureGenomeSizes(population map[int]*Genome) GenomeSizeStats {
+	var stats GenomeSizeStats
+	if len(population) == 0 {
+		return stats
+	}
+	first := true
+	for _, g := range population {
+		_, enabled := g.Size()
+		disabled := len(g.Connections) - enabled
+		stats.Nodes.add(len(g.Nodes), first)
+		stats.EnabledConnections.add(enabled, first)
+		stats.DisabledConnections.add(disabled, first)
+		stats.Bytes += g.MemoryFootprint()
+		first = false
+	}
+	n := float64(len(population))
+	stats.Nodes.Mean /= n
+	stats.EnabledConnections.Mean /= n
+	stats.DisabledConnections.Mean /= n
+	return stats
+}
+
+// add includes v in the range, accumulating its sum in Mean; first resets the range to v.
+func (r *SizeRange) add(v int, first bool) {
+	if first {
+		*r = SizeRange{Min: v, Max: v}
+	}
+	r.Min = min(r.Min, v)
+	r.Max = max(r.Max, v)
+	r.Mean += float64(v)
+}
@@ -0,0 +1,104 @@
+package neat
+
+import "sort"
+
+// PopulationSnapshot is an independent copy of a population's state, for analysis and
+// visualization in background goroutines while evolution continues. Nothing in it is shared with
+// the population: genomes are copies referencing a private copy of the GenomeConfig.
+//
+// Between generations, Population holds the next generation, not yet evaluated, while Species
+// (and Stats) describe the evaluated generation that produced it. From an OnPostSpeciate hook,
+// both describe the same generation.
+type PopulationSnapshot struct {
+	Generation      int
+	Population      map[int]*Genome   // Current genomes, keyed by genome key
+	Species         []SpeciesSnapshot // Sorted by species key
+	GenomeToSpecies map[int]int       // Genome key -> species key of the last speciation
+	BestGenome      *Genome           // Best genome found so far (nil before the first evaluation)
+	HallOfFame      []*Genome
+	Stats           *GenerationStats // Last generation recorded by Population.Statistics (nil without statistics)
+}
+
+// SpeciesSnapshot is a copy of a species as of the last speciation.
+type SpeciesSnapshot struct {
+	Key             int
+	Created         int
+	LastImproved    int
+	Fitness         float64
+	AdjustedFitness float64
+	BestFitness     float64
+	FitnessHistory  []float64
+	Representative  *Genome
+	Members         map[int]*Genome
+
+	Champion           *Genome // All-time fittest genome of the species
+	ChampionGeneration int
+}
+
+// Snapshot returns a copy of the population's current state. Like SaveCheckpoint, it must be
+// called between generations or from a hook; other goroutines take snapshots through Inspect:
+//
+//	var snap *neat.PopulationSnapshot
+//	p.Inspect(func(p *neat.Population) { snap = p.Snapshot() })
+func (p *Population) Snapshot() *PopulationSnapshot {
+	config := p.Config.Genome.Copy()
+	copyGenome := func(g *Genome) *Genome {
+		if g == nil {
+			return nil
+		}
+		c := g.Copy()
+		c.Config = config
+		return c
+	}
+
+	snap := &PopulationSnapshot{
+		Generation:      p.Generation,
+		Population:      make(map[int]*Genome, len(p.Population)),
+		GenomeToSpecies: make(map[int]int),
+		BestGenome:      copyGenome(p.BestGenome),
+	}
+	for key, g := range p.Population {
+		snap.Population[key] = copyGenome(g)
+	}
+	for _, g := range p.HallOfFame {
+		snap.HallOfFame = append(snap.HallOfFame, copyGenome(g))
+	}
+
+	if p.SpeciesSet != nil {
+		for gid, sid := range p.SpeciesSet.GenomeToSpecies {
+			snap.GenomeToSpecies[gid] = sid
+		}
+		for _, s := range p.SpeciesSet.Species {
+			ss := SpeciesSnapshot{
+				Key:             s.Key,
+				Created:         s.Created,
+				LastImproved:    s.LastImproved,
+				Fitness:         s.Fitness,
+				AdjustedFitness: s.AdjustedFitness,
+				BestFitness:     s.BestFitness,
+				FitnessHistory:  append([]float64(nil), s.FitnessHistory...),
+				Representative:  copyGenome(s.Representative),
+				Members:         make(map[int]*Genome, len(s.Members)),
+
+				Champion:           copyGenome(s.Champion),
+				ChampionGeneration: s.ChampionGeneration,
+			}
+			for gid, g := range s.Members {
+				ss.Members[gid] = copyGenome(g)
+			}
+			snap.Species = append(snap.Species, ss)
+		}
+		sort.Slice(snap.Species, func(i, j int) bool { return snap.Species[i].Key < snap.Species[j].Key })
+	}
+
+	if p.Statistics != nil && len(p.Statistics.Generations) > 0 {
+		stats := p.Statistics.Generations[len(p.Statistics.Generations)-1]
+		sizes := make(map[int]int, len(stats.SpeciesSizes))
+		for sid, n := range stats.SpeciesSizes {
+			sizes[sid] = n
+		}
+		stats.SpeciesSizes = sizes
+		snap.Stats = &stats
+	}
+	return snap
+}
@@ -0,0 +1,40 @@
+package neat
+
+// SpeciationResult describes the outcome of a speciation, for callers and reporters.
+type SpeciationResult struct {
+	Species int         // Number of species after speciation
+	Created []int       // Keys of the species founded, sorted
+	Died    []int       // Keys of the species that had no members left, sorted
+	Sizes   map[int]int // Map species key -> number of members
+
+	// Statistics of the genetic distances computed while speciating; zero if the speciator
+	// computed none or doesn't measure them (only ThresholdSpeciator does).
+	MeanDistance  float64
+	StdevDistance float64
+	CacheHits     int // Distances taken from the distance cache
+	CacheMisses   int // Distances that had to be computed
+}
+
+// CacheHitRate returns the fraction of distance lookups answered by the distance cache (0 if
+// there were none).
+func (r SpeciationResult) CacheHitRate() float64 {
+	if n := r.CacheHits + r.CacheMisses; n > 0 {
+		return float64(r.CacheHits) / float64(n)
+	}
+	return 0
+}
+
+// addDistances sets the distance statistics of the result from the distance cache of a
+// speciation.
+func (r *SpeciationResult) addDistances(cache *GenomeDistanceCache) {
+	r.CacheHits, r.CacheMisses = cache.Hits, cache.Misses
+	if len(cache.Distances) == 0 {
+		return
+	}
+	distances := make([]float64, 0, len(cache.Distances))
+	for _, d := range cache.Distances {
+		distances = append(distances, d)
+	}
+	r.MeanDistance = Mean(distances)
+	r.StdevDistance = Stdev(distances)
+}
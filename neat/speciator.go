@@ -0,0 +1,156 @@
+package neat
+
+import (
+	"fmt"
+	"math"
+)
+
+// Speciator divides a population into species, updating a SpeciesSet in place with
+// SpeciesSet.Assign: species that continue keep their key and history, new ones take keys from
+// SpeciesSet.NextKey. The speciator is chosen by speciation_method ("threshold", "cluster" or
+// "behavior"); install a custom one with Population.SetSpeciator. Speciate returns the result of
+// SpeciesSet.Assign, with distance statistics added if the speciator measures them.
+type Speciator interface {
+	Speciate(config *Config, speciesSet *SpeciesSet, population map[int]*Genome, generation int) (SpeciationResult, error)
+}
+
+// ThresholdSpeciator is the default speciator, NEAT's: a genome joins the species with the
+// closest representative within compatibility_threshold, or founds a new species (see
+// SpeciesSet.Speciate).
+type ThresholdSpeciator struct{}
+
+// Speciate calls speciesSet.Speciate.
+func (ThresholdSpeciator) Speciate(config *Config, speciesSet *SpeciesSet, population map[int]*Genome, generation int) (SpeciationResult, error) {
+	return speciesSet.Speciate(config, population, generation)
+}
+
+// ClusterSpeciator divides the population into Clusters species by k-medoids clustering of the
+// genetic distance (see ClusterGenomes), so the number of species is fixed instead of depending on
+// the threshold. Clusters, largest first, continue the species whose representative is closest to
+// their medoid, if within compatibility_threshold; the medoids become the representatives.
+type ClusterSpeciator struct {
+	Clusters int
+}
+
+// Speciate clusters the population and assigns the clusters to species.
+func (cs ClusterSpeciator) Speciate(config *Config, speciesSet *SpeciesSet, population map[int]*Genome, generation int) (SpeciationResult, error) {
+	clusters, err := ClusterGenomes(population, cs.Clusters)
+	if err != nil {
+		return SpeciationResult{}, err
+	}
+	representatives := make(map[int]*Genome, len(clusters))
+	members := make(map[int][]int, len(clusters))
+	for _, c := range clusters {
+		sid, ok := closestSpecies(speciesSet, representatives, c.Medoid)
+		if !ok {
+			sid = speciesSet.NextKey()
+		}
+		representatives[sid] = c.Medoid
+		for _, g := range c.Members {
+			members[sid] = append(members[sid], g.Key)
+		}
+	}
+	return speciesSet.Assign(representatives, members, population, generation), nil
+}
+
+// BehaviorSpeciator groups genomes by what they do rather than by their genes: the threshold
+// algorithm over the Euclidean distance between Genome.Behavior descriptors, with
+// compatibility_threshold as the distance threshold. Each existing species is represented by the
+// genome behaving most like its previous representative, and the remaining genomes join the
+// species with the closest representative within the threshold or found a new one. The fitness
+// function must set Behavior, of the same length, for every genome.
+type BehaviorSpeciator struct{}
+
+// Speciate assigns the population to species by behavior.
+func (BehaviorSpeciator) Speciate(config *Config, speciesSet *SpeciesSet, population map[int]*Genome, generation int) (SpeciationResult, error) {
+	keys := sortedKeys(population)
+	dims := -1
+	for _, key := range keys {
+		n := len(population[key].Behavior)
+		if n == 0 || (dims >= 0 && n != dims) {
+			return SpeciationResult{}, fmt.Errorf("behavior speciation: genome %d has a behavior descriptor of length %d", key, n)
+		}
+		dims = n
+	}
+
+	representatives := make(map[int]*Genome)
+	members := make(map[int][]int)
+	assigned := make(map[int]bool, len(population))
+	for _, sid := range sortedKeys(speciesSet.Species) {
+		old := speciesSet.Species[sid].Representative
+		if old == nil || len(old.Behavior) != dims {
+			continue
+		}
+		var rep *Genome
+		minDist := math.Inf(1)
+		for _, key := range keys {
+			if d := behaviorDistance(old, population[key]); !assigned[key] && d < minDist {
+				rep, minDist = population[key], d
+			}
+		}
+		if rep == nil {
+			break // Every genome represents a species
+		}
+		representatives[sid] = rep
+		members[sid] = []int{rep.Key}
+		assigned[rep.Key] = true
+	}
+
+	threshold := speciesSet.Config.CompatibilityThreshold
+	for _, key := range keys {
+		if assigned[key] {
+			continue
+		}
+		g := population[key]
+		sid, minDist := -1, math.Inf(1)
+		for _, s := range sortedKeys(representatives) {
+			if d := behaviorDistance(representatives[s], g); d < threshold && d < minDist {
+				sid, minDist = s, d
+			}
+		}
+		if sid == -1 {
+			sid = speciesSet.NextKey()
+			representatives[sid] = g
+		}
+		members[sid] = append(members[sid], key)
+	}
+	return speciesSet.Assign(representatives, members, population, generation), nil
+}
+
+// behaviorDistance returns the Euclidean distance between the behavior descriptors of a and b.
+func behaviorDistance(a, b *Genome) float64 {
+	sum := 0.0
+	for i := range a.Behavior {
+		d := a.Behavior[i] - b.Behavior[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// closestSpecies returns the existing species, not yet in taken, whose representative is
+// genetically closest to g, if it is within compatibility_threshold.
+func closestSpecies(speciesSet *SpeciesSet, taken map[int]*Genome, g *Genome) (int, bool) {
+	sid, minDist := -1, speciesSet.Config.CompatibilityThreshold
+	for _, key := range sortedKeys(speciesSet.Species) {
+		rep := speciesSet.Species[key].Representative
+		if _, ok := taken[key]; ok || rep == nil {
+			continue
+		}
+		if d := rep.Distance(g); d < minDist {
+			sid, minDist = key, d
+		}
+	}
+	return sid, sid != -1
+}
+
+// configuredSpeciator returns the speciator selected by speciation_method.
+func configuredSpeciator(config *SpeciesSetConfig) Speciator {
+	switch config.SpeciationMethod {
+	case "cluster":
+		return ClusterSpeciator{Clusters: config.SpeciationClusters}
+	case "behavior":
+		return BehaviorSpeciator{}
+	default:
+		return ThresholdSpeciator{}
+	}
+}
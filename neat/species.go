@@ -1,8 +1,8 @@
 package neat
 
 import (
-	"fmt"
 	"math"
+	"math/rand"
 	"sort"
 )
 
@@ -15,7 +15,13 @@ type Species struct {
 	Members         map[int]*Genome // Genomes belonging to this species (maps genome key -> genome).
 	Fitness         float64         // Calculated fitness for the species (e.g., mean fitness of members).
 	AdjustedFitness float64         // Fitness adjusted by sharing.
-	FitnessHistory  []float64       // History of fitness values for stagnation detection.
+	FitnessHistory  []float64       // Recent fitness values, bounded by fitness_history_length.
+	BestFitness     float64         // Highest species fitness ever recorded, used for stagnation detection.
+
+	// Champion is a copy of the fittest genome the species has had, and ChampionGeneration the
+	// generation it was evaluated in (nil and 0 until the species' first evaluated generation).
+	Champion           *Genome
+	ChampionGeneration int
 }
 
 // NewSpecies creates a new species.
@@ -26,6 +32,7 @@ func NewSpecies(key, generation int) *Species {
 		LastImproved:   generation,
 		Members:        make(map[int]*Genome),
 		FitnessHistory: []float64{},
+		BestFitness:    math.Inf(-1),
 	}
 }
 
@@ -35,6 +42,21 @@ func (s *Species) Update(representative *Genome, members map[int]*Genome) {
 	s.Members = members
 }
 
+// updateChampion replaces the champion with the fittest member, if it is fitter, after members
+// evaluated in generation have been assigned to the species.
+func (s *Species) updateChampion(generation int) {
+	var best *Genome
+	for _, g := range s.Members {
+		if best == nil || g.Fitness > best.Fitness || (g.Fitness == best.Fitness && g.Key < best.Key) {
+			best = g
+		}
+	}
+	if best != nil && (s.Champion == nil || best.Fitness > s.Champion.Fitness) {
+		s.Champion = best.Copy() // Members are mutated or released in later generations
+		s.ChampionGeneration = generation
+	}
+}
+
 // GetFitnesses returns a slice containing the fitness values of all members.
 func (s *Species) GetFitnesses() []float64 {
 	fitnesses := make([]float64, 0, len(s.Members))
@@ -89,12 +111,32 @@ func (dc *GenomeDistanceCache) Distance(genome1, genome2 *Genome) float64 {
 
 // --------------------------- SpeciesSet ---------------------------
 
+// SpeciationPolicy customizes the compatibility threshold of individual species, e.g. to loosen it
+// for old, small species so they keep recruiting. A genome joins a species when its distance to
+// the representative is below compatibility_threshold times the species' multiplier. Species
+// founded during the current speciation use a multiplier of 1.
+// Use Population.SetSpeciationPolicy to install one.
+type SpeciationPolicy interface {
+	ThresholdMultiplier(species *Species, generation int) float64
+}
+
+// SpeciationPolicyFunc adapts a function to the SpeciationPolicy interface.
+type SpeciationPolicyFunc func(species *Species, generation int) float64
+
+// ThresholdMultiplier calls f.
+func (f SpeciationPolicyFunc) ThresholdMultiplier(species *Species, generation int) float64 {
+	return f(species, generation)
+}
+
 // SpeciesSet manages the collection of species within a population.
 type SpeciesSet struct {
 	Species         map[int]*Species  // Map species key -> Species
 	GenomeToSpecies map[int]int       // Map genome key -> species key
-	Indexer         int               // Counter for assigning new species keys (start at 1)
+	Indexer         int               // Counter for assigning new species keys (start at 1); keys are never reused unless reindexed
 	Config          *SpeciesSetConfig // Reference to speciation config
+	ancestors       map[int][]int     // Parents of the genomes being speciated, for sticky speciation
+	logger          Logger            // Destination for diagnostic output (nil = DefaultLogger)
+	policy          SpeciationPolicy  // Per-species threshold multipliers (nil = the same threshold for all)
 	// Reporters      *reporting.ReporterSet // TODO: Add reporters later
 }
 
@@ -108,16 +150,50 @@ func NewSpeciesSet(config *SpeciesSetConfig) *SpeciesSet {
 	}
 }
 
-// Speciate partitions the population into species based on genetic distance.
-func (ss *SpeciesSet) Speciate(config *Config, population map[int]*Genome, generation int) error {
+// SetPolicy installs a speciation policy; nil restores the uniform compatibility threshold.
+func (ss *SpeciesSet) SetPolicy(policy SpeciationPolicy) {
+	ss.policy = policy
+}
+
+// parentSpecies returns the species the first parent of genome gid belonged to last generation.
+// Genomes that were themselves speciated last generation (elites) count as their own parent.
+func (ss *SpeciesSet) parentSpecies(gid int) (int, bool) {
+	if sid, ok := ss.GenomeToSpecies[gid]; ok {
+		return sid, true
+	}
+	parents := ss.ancestors[gid]
+	if len(parents) == 0 {
+		return 0, false
+	}
+	sid, ok := ss.GenomeToSpecies[parents[0]]
+	return sid, ok
+}
+
+// Speciate partitions the population into species based on genetic distance, and returns the
+// species created and died out, their sizes and statistics of the distances computed.
+func (ss *SpeciesSet) Speciate(config *Config, population map[int]*Genome, generation int) (SpeciationResult, error) {
 	if len(population) == 0 {
-		ss.Species = make(map[int]*Species) // Reset if population is empty
-		ss.GenomeToSpecies = make(map[int]int)
-		return nil
+		// Reset if population is empty
+		return ss.Assign(nil, nil, population, generation), nil
 	}
 
 	compatibilityThreshold := ss.Config.CompatibilityThreshold
 	distanceCache := NewGenomeDistanceCache(&config.Genome) // Need GenomeConfig for distance calcs
+	filter := newSignatureFilter(ss.Config, &config.Genome) // nil unless speciation_signature_size is set
+	// Index genomes that weren't bred by reproduction (e.g. loaded or edited), so every distance
+	// below takes the merge path.
+	for _, g := range population {
+		if _, ok := g.indexedConnections(); !ok {
+			g.indexConnections()
+		}
+	}
+	for _, s := range ss.Species {
+		if rep := s.Representative; rep != nil {
+			if _, ok := rep.indexedConnections(); !ok {
+				rep.indexConnections()
+			}
+		}
+	}
 
 	// --- Step 1: Prepare ---
 	unspeciated := make(map[int]*Genome, len(population))
@@ -127,6 +203,36 @@ func (ss *SpeciesSet) Speciate(config *Config, population map[int]*Genome, gener
 	newRepresentatives := make(map[int]*Genome) // species key -> new representative genome
 	newMembers := make(map[int][]int)           // species key -> list of member genome keys
 
+	// --- Step 1b: Keep Survivors in Their Species (incremental speciation) ---
+	// Genomes carried over unchanged from the previous generation keep their species. A species keeps its
+	// representative if it survived, otherwise the survivor closest to it takes over.
+	if ss.Config.IncrementalSpeciation {
+		survivorKeys := make([]int, 0)
+		for gid := range population {
+			if _, ok := ss.GenomeToSpecies[gid]; ok {
+				survivorKeys = append(survivorKeys, gid)
+			}
+		}
+		sort.Ints(survivorKeys)
+		for _, gid := range survivorKeys {
+			sid := ss.GenomeToSpecies[gid]
+			s, ok := ss.Species[sid]
+			if !ok || s.Representative == nil {
+				continue
+			}
+			g := population[gid]
+			newMembers[sid] = append(newMembers[sid], gid)
+			delete(unspeciated, gid)
+			rep, hasRep := newRepresentatives[sid]
+			switch {
+			case gid == s.Representative.Key:
+				newRepresentatives[sid] = g
+			case !hasRep || (rep.Key != s.Representative.Key && distanceCache.Distance(s.Representative, g) < distanceCache.Distance(s.Representative, rep)):
+				newRepresentatives[sid] = g
+			}
+		}
+	}
+
 	// --- Step 2: Assign Representatives for Existing Species ---
 	// Find the genome in the current population closest to the *old* representative.
 	// This genome becomes the new representative for the next generation.
@@ -136,40 +242,32 @@ func (ss *SpeciesSet) Speciate(config *Config, population map[int]*Genome, gener
 		if len(unspeciated) == 0 {
 			break
 		}
-
-		candidates := []struct {
-			Genome *Genome
-			Dist   float64
-		}{}
+		if _, kept := newRepresentatives[sid]; kept {
+			continue // Represented by a survivor (incremental speciation).
+		}
 
 		// If the old representative is still in the population, consider it.
 		// Otherwise, the species might die out if no members are close enough.
 		if s.Representative == nil {
 			// This shouldn't happen if species are managed correctly
-			fmt.Printf("Warning: Species %d has no representative. Skipping.\n", sid)
+			loggerOrDefault(ss.logger).Warn("Species has no representative, skipping", "species", sid)
 			continue
 		}
 
+		// The genome closest to the old representative becomes the new representative.
+		var newRep *Genome
+		minDist := math.Inf(1)
 		for _, g := range unspeciated {
-			d := distanceCache.Distance(s.Representative, g)
-			candidates = append(candidates, struct {
-				Genome *Genome
-				Dist   float64
-			}{g, d})
+			if !filter.mayBeWithin(s.Representative, g, minDist) {
+				continue
+			}
+			if d := distanceCache.Distance(s.Representative, g); newRep == nil || d < minDist {
+				newRep, minDist = g, d
+			}
 		}
-
-		if len(candidates) == 0 {
-			// No unspeciated genomes left to check against this species' rep
+		if newRep == nil {
 			continue
 		}
-
-		// Sort candidates by distance to the old representative.
-		sort.Slice(candidates, func(i, j int) bool {
-			return candidates[i].Dist < candidates[j].Dist
-		})
-
-		// The closest genome becomes the new representative.
-		newRep := candidates[0].Genome
 		newRepresentatives[sid] = newRep
 		newMembers[sid] = []int{newRep.Key}
 		delete(unspeciated, newRep.Key)
@@ -186,18 +284,47 @@ func (ss *SpeciesSet) Speciate(config *Config, population map[int]*Genome, gener
 		return remainingGenomes[i].Key < remainingGenomes[j].Key
 	})
 
+	// Thresholds of the existing species, adjusted by the speciation policy.
+	thresholds := make(map[int]float64, len(newRepresentatives))
+	for sid := range newRepresentatives {
+		thresholds[sid] = compatibilityThreshold
+		if ss.policy != nil {
+			thresholds[sid] *= ss.policy.ThresholdMultiplier(ss.Species[sid], generation)
+		}
+	}
+	threshold := func(sid int) float64 {
+		if t, ok := thresholds[sid]; ok {
+			return t
+		}
+		return compatibilityThreshold // Founded this generation
+	}
+
 	for _, g := range remainingGenomes {
 		gid := g.Key
 
 		bestSpecies := -1
 		minDist := math.Inf(1)
 
-		// Find the existing species (based on *new* representatives) this genome is closest to.
-		for sid, rep := range newRepresentatives {
-			d := distanceCache.Distance(rep, g)
-			if d < compatibilityThreshold && d < minDist {
-				minDist = d
-				bestSpecies = sid
+		// With sticky speciation, stay in the first parent's species while still compatible with it.
+		if config.Experimental.StickySpeciation {
+			if sid, ok := ss.parentSpecies(gid); ok {
+				if rep, ok := newRepresentatives[sid]; ok && distanceCache.Distance(rep, g) < threshold(sid) {
+					bestSpecies = sid
+				}
+			}
+		}
+
+		// Otherwise, find the existing species (based on *new* representatives) this genome is closest to.
+		if bestSpecies == -1 {
+			for sid, rep := range newRepresentatives {
+				if !filter.mayBeWithin(rep, g, math.Min(threshold(sid), minDist)) {
+					continue
+				}
+				d := distanceCache.Distance(rep, g)
+				if d < threshold(sid) && d < minDist {
+					minDist = d
+					bestSpecies = sid
+				}
 			}
 		}
 
@@ -206,22 +333,38 @@ func (ss *SpeciesSet) Speciate(config *Config, population map[int]*Genome, gener
 			newMembers[bestSpecies] = append(newMembers[bestSpecies], gid)
 		} else {
 			// No suitable species found, create a new one.
-			newSID := ss.Indexer
-			ss.Indexer++
+			newSID := ss.NextKey()
 			newRepresentatives[newSID] = g
 			newMembers[newSID] = []int{gid}
 		}
 	}
 
 	// --- Step 4: Update SpeciesSet ---
+	for sid, representative := range newRepresentatives {
+		if membersList := newMembers[sid]; len(membersList) > 0 {
+			newRepresentatives[sid] = ss.selectRepresentative(representative, membersList, population, distanceCache)
+		}
+	}
+	result := ss.Assign(newRepresentatives, newMembers, population, generation)
+	result.addDistances(distanceCache)
+	return result, nil
+}
+
+// Assign replaces the species with the given assignment, for speciation algorithms: members maps
+// each species key to the keys of its genomes in population and representatives to its
+// representative for the next speciation. Existing species with members are continued, keys not
+// in Species found new species (take them from NextKey), and species without members die out.
+// The result lists the species created and died out and their sizes.
+func (ss *SpeciesSet) Assign(representatives map[int]*Genome, members map[int][]int, population map[int]*Genome, generation int) SpeciationResult {
 	newSpeciesMap := make(map[int]*Species)
 	newGenomeToSpeciesMap := make(map[int]int)
+	result := SpeciationResult{Sizes: make(map[int]int)}
 
-	for sid, representative := range newRepresentatives {
-		membersList := newMembers[sid]
+	for sid, representative := range representatives {
+		membersList := members[sid]
 		if len(membersList) == 0 {
 			// This species died out (no representative assigned or members found)
-			fmt.Printf("Info: Species %d died out.\n", sid)
+			loggerOrDefault(ss.logger).Debug("Species died out", "species", sid)
 			continue
 		}
 
@@ -229,7 +372,8 @@ func (ss *SpeciesSet) Speciate(config *Config, population map[int]*Genome, gener
 		if s == nil {
 			// It's a newly created species
 			s = NewSpecies(sid, generation)
-			fmt.Printf("Info: Created new species %d represented by genome %d\n", sid, representative.Key)
+			result.Created = append(result.Created, sid)
+			loggerOrDefault(ss.logger).Debug("Created new species", "species", sid, "representative", representative.Key)
 		}
 
 		memberMap := make(map[int]*Genome)
@@ -240,26 +384,63 @@ func (ss *SpeciesSet) Speciate(config *Config, population map[int]*Genome, gener
 
 		s.Update(representative, memberMap)
 		newSpeciesMap[sid] = s
+		result.Sizes[sid] = len(memberMap)
+	}
+	for _, sid := range sortedKeys(ss.Species) {
+		if _, ok := newSpeciesMap[sid]; !ok {
+			result.Died = append(result.Died, sid)
+		}
 	}
+	sort.Ints(result.Created)
+	result.Species = len(newSpeciesMap)
 
 	ss.Species = newSpeciesMap
 	ss.GenomeToSpecies = newGenomeToSpeciesMap
+	return result
+}
 
-	// Report distance cache performance (optional)
-	// fmt.Printf("Distance Cache: Hits=%d, Misses=%d\n", distanceCache.Hits, distanceCache.Misses)
+// NextKey returns a new species key, unique within the run until the species are reindexed.
+func (ss *SpeciesSet) NextKey() int {
+	key := ss.Indexer
+	ss.Indexer++
+	return key
+}
 
-	// Report mean/stdev genetic distance (optional)
-	if len(distanceCache.Distances) > 0 {
-		allDistances := make([]float64, 0, len(distanceCache.Distances))
-		for _, d := range distanceCache.Distances {
-			allDistances = append(allDistances, d)
+// selectRepresentative picks the representative of a species for the next speciation according
+// to representative_selection. With "closest", the genome chosen during speciation is kept.
+func (ss *SpeciesSet) selectRepresentative(current *Genome, memberKeys []int, population map[int]*Genome, distanceCache *GenomeDistanceCache) *Genome {
+	keys := append([]int(nil), memberKeys...)
+	sort.Ints(keys)
+	switch ss.Config.RepresentativeSelection {
+	case "champion":
+		champion := population[keys[0]]
+		for _, gid := range keys[1:] {
+			if g := population[gid]; g.Fitness > champion.Fitness {
+				champion = g
+			}
+		}
+		return champion
+	case "random":
+		return population[keys[rand.Intn(len(keys))]]
+	case "medoid":
+		var medoid *Genome
+		best := math.Inf(1)
+		for _, gid := range keys {
+			total := 0.0
+			for _, other := range keys {
+				if other != gid {
+					total += distanceCache.Distance(population[gid], population[other])
+				}
+			}
+			if total < best {
+				best = total
+				medoid = population[gid]
+			}
 		}
-		meanDist := Mean(allDistances)
-		stdevDist := Stdev(allDistances)
-		fmt.Printf("Mean genetic distance: %.3f, Stdev: %.3f\n", meanDist, stdevDist)
+		return medoid
+	default:
+		return current
 	}
-
-	return nil
 }
 
 // GetSpeciesID returns the species ID for a given genome ID.
@@ -277,3 +458,33 @@ func (ss *SpeciesSet) GetSpecies(genomeID int) (*Species, bool) {
 	s, exists := ss.Species[sid]
 	return s, exists
 }
+
+// Champions returns the fittest member of every species, keyed by species key.
+// Ties are broken in favor of the lower genome key. Species without members are omitted.
+func (ss *SpeciesSet) Champions() map[int]*Genome {
+	champions := make(map[int]*Genome, len(ss.Species))
+	for sid, s := range ss.Species {
+		var champion *Genome
+		for _, g := range s.Members {
+			if champion == nil || g.Fitness > champion.Fitness || (g.Fitness == champion.Fitness && g.Key < champion.Key) {
+				champion = g
+			}
+		}
+		if champion != nil {
+			champions[sid] = champion
+		}
+	}
+	return champions
+}
+
+// AllTimeChampions returns the all-time champion (Species.Champion) of every current species,
+// keyed by species key. Unlike Champions, the genomes may come from earlier generations.
+func (ss *SpeciesSet) AllTimeChampions() map[int]*Genome {
+	champions := make(map[int]*Genome, len(ss.Species))
+	for sid, s := range ss.Species {
+		if s.Champion != nil {
+			champions[sid] = s.Champion
+		}
+	}
+	return champions
+}
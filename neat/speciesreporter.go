@@ -0,0 +1,101 @@
+package neat
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// SpeciesReporter records the size of every species at the end of each generation, the data
+// behind a speciation stack plot (see visualize.PlotSpecies). Species that don't exist in a
+// generation have size 0 there.
+type SpeciesReporter struct {
+	BaseReporter
+	Generations []int         // Recorded generations, in order
+	Sizes       []map[int]int // Species key -> member count, one map per recorded generation
+
+	generation int
+}
+
+// NewSpeciesReporter creates an empty species reporter.
+func NewSpeciesReporter() *SpeciesReporter {
+	return &SpeciesReporter{}
+}
+
+func (r *SpeciesReporter) StartGeneration(generation int) {
+	r.generation = generation
+}
+
+func (r *SpeciesReporter) EndGeneration(config *Config, population map[int]*Genome, speciesSet *SpeciesSet) {
+	if speciesSet == nil {
+		return
+	}
+	sizes := make(map[int]int, len(speciesSet.Species))
+	for sid, s := range speciesSet.Species {
+		sizes[sid] = len(s.Members)
+	}
+	r.Generations = append(r.Generations, r.generation)
+	r.Sizes = append(r.Sizes, sizes)
+}
+
+// SpeciesKeys returns the keys of all species seen, in increasing order.
+func (r *SpeciesReporter) SpeciesKeys() []int {
+	seen := make(map[int]bool)
+	for _, sizes := range r.Sizes {
+		for sid := range sizes {
+			seen[sid] = true
+		}
+	}
+	keys := make([]int, 0, len(seen))
+	for sid := range seen {
+		keys = append(keys, sid)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// Matrix returns the recorded sizes as a table: rows follow Generations, columns follow
+// SpeciesKeys.
+func (r *SpeciesReporter) Matrix() [][]int {
+	keys := r.SpeciesKeys()
+	rows := make([][]int, len(r.Sizes))
+	for i, sizes := range r.Sizes {
+		rows[i] = make([]int, len(keys))
+		for j, sid := range keys {
+			rows[i][j] = sizes[sid]
+		}
+	}
+	return rows
+}
+
+// WriteCSV writes the sizes to filePath, one row per generation and one column per species,
+// as neat-python's save_species_count does (with a leading generation column and a header).
+func (r *SpeciesReporter) WriteCSV(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create species CSV '%s': %w", filePath, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	keys := r.SpeciesKeys()
+	header := []string{"generation"}
+	for _, sid := range keys {
+		header = append(header, "species_"+strconv.Itoa(sid))
+	}
+	w.Write(header)
+	for i, row := range r.Matrix() {
+		record := []string{strconv.Itoa(r.Generations[i])}
+		for _, size := range row {
+			record = append(record, strconv.Itoa(size))
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write species CSV '%s': %w", filePath, err)
+	}
+	return f.Close()
+}
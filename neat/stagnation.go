@@ -6,50 +6,81 @@ import (
 	"sort"
 )
 
-// Stagnation manages the detection of stagnant species.
-type Stagnation struct {
+// Stagnation decides which species should be removed for failing to improve.
+// Implementations are called once per generation from Reproduction.Reproduce and must set
+// Species.Fitness for every species, since spawn amounts are derived from it.
+// The default scheme is DefaultStagnation, which removes the species its StagnationPolicy marks
+// (none with stagnation_policy "none").
+// Use Population.SetStagnation to install an alternative implementation.
+type Stagnation interface {
+	Update(speciesSet *SpeciesSet, generation int) ([]StagnationInfo, error)
+}
+
+// StagnationInfo holds the results of the stagnation update for a single species.
+type StagnationInfo struct {
+	SpeciesID  int
+	Species    *Species
+	IsStagnant bool
+}
+
+// --------------------------- DefaultStagnation ---------------------------
+
+// DefaultStagnation updates the fitness of every species and removes those its Policy marks as
+// stagnant, except for the species spared by species elitism: the species_elitism fittest species
+// are always kept, and species are only removed while more than species_elitism remain, as in
+// neat-python. stagnation_policy selects the Policy:
+//   - "rank-elitism" (default): no improvement for max_stagnation generations (MaxStagnationPolicy).
+//   - "absolute": the same, but without species elitism.
+//   - "none": no species is ever removed (NeverStagnantPolicy).
+//   - "max-age": species older than max_species_age generations (MaxAgePolicy).
+//   - "improvement-rate": fitness rising by less than min_improvement_rate per generation over
+//     improvement_window generations (ImprovementRatePolicy).
+type DefaultStagnation struct {
 	Config             *StagnationConfig
 	SpeciesFitnessFunc func([]float64) float64
-	// Reporters         *reporting.ReporterSet // TODO: Add reporters later
+	Policy             StagnationPolicy // nil = the one selected by stagnation_policy
+	Reporters          *ReporterSet     // Receives SpeciesSpared notifications (nil discards them)
 }
 
-// NewStagnation creates a new stagnation manager.
-func NewStagnation(config *StagnationConfig) (*Stagnation, error) {
-	fn, ok := StatFunctions[config.SpeciesFitnessFunc]
-	if !ok {
-		return nil, fmt.Errorf("invalid species_fitness_func in config: %s", config.SpeciesFitnessFunc)
+// NewStagnation creates the default stagnation manager.
+func NewStagnation(config *StagnationConfig) (*DefaultStagnation, error) {
+	fn, err := speciesFitnessFunc(config)
+	if err != nil {
+		return nil, err
 	}
 
-	return &Stagnation{
+	return &DefaultStagnation{
 		Config:             config,
 		SpeciesFitnessFunc: fn,
+		Policy:             newStagnationPolicy(config),
 	}, nil
 }
 
-// StagnationInfo holds the results of the stagnation update for a single species.
-type StagnationInfo struct {
-	SpeciesID  int
-	Species    *Species
-	IsStagnant bool
-}
-
-// Update checks for stagnant species within the species set.
-// It updates species fitness history and marks species for removal based on stagnation criteria.
-func (s *Stagnation) Update(speciesSet *SpeciesSet, generation int) ([]StagnationInfo, error) {
-	if len(speciesSet.Species) == 0 {
-		return []StagnationInfo{}, nil
+// speciesFitnessFunc looks up the configured species fitness function.
+func speciesFitnessFunc(config *StagnationConfig) (func([]float64) float64, error) {
+	fn, ok := StatFunctions[config.SpeciesFitnessFunc]
+	if !ok {
+		return nil, fmt.Errorf("invalid species_fitness_func in config: %s", config.SpeciesFitnessFunc)
 	}
+	return fn, nil
+}
 
-	speciesData := []struct {
-		ID      int
-		Species *Species
-	}{}
+// speciesEntry pairs a species with its key for sorting.
+type speciesEntry struct {
+	ID      int
+	Species *Species
+}
 
-	// Calculate fitness for each species and update history
+// updateSpeciesFitness computes each species' fitness with fitnessFunc, appends it to the
+// fitness history and updates LastImproved and BestFitness. The history is then trimmed according to
+// fitness_history_length and reset_history_on_improvement. The species are returned in unspecified order.
+func updateSpeciesFitness(speciesSet *SpeciesSet, config *StagnationConfig, fitnessFunc func([]float64) float64, generation int) []speciesEntry {
+	data := make([]speciesEntry, 0, len(speciesSet.Species))
 	for sid, sp := range speciesSet.Species {
+		// BestFitness survives history trimming, so it is the reference for improvement.
 		previousMaxFitness := math.Inf(-1)
 		if len(sp.FitnessHistory) > 0 {
-			previousMaxFitness = MaxFloat(sp.FitnessHistory) // Use MaxFloat from math_util
+			previousMaxFitness = math.Max(sp.BestFitness, MaxFloat(sp.FitnessHistory))
 		}
 
 		memberFitnesses := sp.GetFitnesses()
@@ -57,7 +88,7 @@ func (s *Stagnation) Update(speciesSet *SpeciesSet, generation int) ([]Stagnatio
 			// Handle species with no members (should ideally not happen after speciation)
 			sp.Fitness = math.Inf(-1) // Assign lowest possible fitness
 		} else {
-			sp.Fitness = s.SpeciesFitnessFunc(memberFitnesses)
+			sp.Fitness = fitnessFunc(memberFitnesses)
 		}
 
 		sp.FitnessHistory = append(sp.FitnessHistory, sp.Fitness)
@@ -65,70 +96,60 @@ func (s *Stagnation) Update(speciesSet *SpeciesSet, generation int) ([]Stagnatio
 
 		if sp.Fitness > previousMaxFitness {
 			sp.LastImproved = generation
+			sp.BestFitness = sp.Fitness
+			if config.ResetHistoryOnImprovement {
+				// The new champion starts a fresh window; older values can no longer exceed it.
+				sp.FitnessHistory = []float64{sp.Fitness}
+			}
+		}
+		if limit := config.historyLimit(); limit > 0 && len(sp.FitnessHistory) > limit {
+			// Copy the window so the dropped prefix doesn't stay reachable through the backing array.
+			window := make([]float64, limit)
+			copy(window, sp.FitnessHistory[len(sp.FitnessHistory)-limit:])
+			sp.FitnessHistory = window
 		}
 
-		speciesData = append(speciesData, struct {
-			ID      int
-			Species *Species
-		}{sid, sp})
+		data = append(data, speciesEntry{sid, sp})
 	}
+	return data
+}
+
+// Update checks for stagnant species within the species set.
+// It updates species fitness history and marks species for removal based on stagnation criteria.
+func (s *DefaultStagnation) Update(speciesSet *SpeciesSet, generation int) ([]StagnationInfo, error) {
+	if len(speciesSet.Species) == 0 {
+		return []StagnationInfo{}, nil
+	}
+
+	// Calculate fitness for each species and update history
+	speciesData := updateSpeciesFitness(speciesSet, s.Config, s.SpeciesFitnessFunc, generation)
 
 	// Sort species by fitness (ascending - least fit first)
 	sort.Slice(speciesData, func(i, j int) bool {
 		return speciesData[i].Species.Fitness < speciesData[j].Species.Fitness
 	})
 
+	// Species elitism spares the fittest species (last in ascending order) and keeps the species
+	// count from dropping to species_elitism or below.
+	policy := s.Policy
+	if policy == nil {
+		policy = newStagnationPolicy(s.Config)
+	}
+	elitism := s.Config.SpeciesElitism
+	if s.Config.Policy == "absolute" {
+		elitism = 0
+	}
 	result := make([]StagnationInfo, len(speciesData))
-	numSpecies := len(speciesData)
-	numNonStagnant := numSpecies
-
-	// Determine stagnation, applying species elitism
+	remaining := len(speciesData)
 	for i, data := range speciesData {
 		sp := data.Species
-		stagnantTime := generation - sp.LastImproved
-		isStagnant := false
-
-		// Check if basic stagnation criteria is met
-		if stagnantTime >= s.Config.MaxStagnation {
-			// Check if elitism spares this species
-			// Elitism protects the top `species_elitism` fittest species.
-			// Since we sorted ascending, the last `species_elitism` are the fittest.
-			if (numSpecies - i) > s.Config.SpeciesElitism {
-				// This species is not among the elite, mark as stagnant
-				isStagnant = true
-				numNonStagnant--
-			}
-		}
-
-		// Additional check from neat-python (seems redundant given the above but included for parity):
-		// Override stagnant state if marking this species as stagnant would
-		// result in the total number of species dropping below the limit.
-		if numNonStagnant <= s.Config.SpeciesElitism && isStagnant {
-			// This logic seems complex and potentially conflicts with the above check.
-			// Let's stick to the simpler logic: only mark stagnant if not in the elite group.
-			// The python code structure is slightly different here.
-			// Re-evaluating the python code: it seems `num_non_stagnant` is used *before* deciding
-			// `is_stagnant` based on elitism rank. Let's try that.
-
-			/* Revised Logic Attempt (closer to Python order): */
-			isStagnantStandard := stagnantTime >= s.Config.MaxStagnation
+		isStagnant := policy.Stagnant(sp, generation)
+		if isStagnant && (len(speciesData)-i <= elitism || remaining <= elitism) {
 			isStagnant = false
-			if numNonStagnant > s.Config.SpeciesElitism && isStagnantStandard {
-				// Only consider it stagnant if removing it wouldn't drop below elite count
-				isStagnant = true
-			}
-			// Double-check: Ensure elite species are never marked stagnant, regardless of time.
-			if (numSpecies - i) <= s.Config.SpeciesElitism {
-				isStagnant = false
-			}
-
-			if isStagnantStandard && !isStagnant {
-				// We are sparing this species due to elitism
-				fmt.Printf("Info: Species %d spared from stagnation due to elitism (Fitness: %.3f, Stagnant for: %d gen)\n", sp.Key, sp.Fitness, stagnantTime)
-			} else if isStagnant {
-				numNonStagnant-- // Decrement count only if truly marked stagnant
-			}
-			/* --- */
+			s.Reporters.SpeciesSpared(sp.Key, sp, generation)
+		}
+		if isStagnant {
+			remaining--
 		}
 
 		result[i] = StagnationInfo{
@@ -0,0 +1,38 @@
+package neat
+
+import "testing"
+
+func TestStagnationPolicyNoneKeepsEverySpecies(t *testing.T) {
+	config := loadTestConfig(t)
+	config.Stagnation.Policy = "none"
+	config.Stagnation.SpeciesElitism = 0
+	stagnation, err := NewStagnation(&config.Stagnation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stagnation.Policy.(NeverStagnantPolicy); !ok {
+		t.Fatalf("stagnation_policy none uses %T, want NeverStagnantPolicy", stagnation.Policy)
+	}
+	speciesSet := NewSpeciesSet(&config.SpeciesSet)
+	for key := 1; key <= 3; key++ {
+		sp := NewSpecies(key, 0)
+		g := grownGenome(t, config, key, 0)
+		sp.Members = map[int]*Genome{key: g}
+		sp.FitnessHistory = []float64{1} // Fitter than the members have been since
+		sp.BestFitness = 1
+		speciesSet.Species[key] = sp
+	}
+	generation := 10 * (config.Stagnation.MaxStagnation + 1)
+	info, err := stagnation.Update(speciesSet, generation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info) != 3 {
+		t.Fatalf("Update reported %d species, want 3", len(info))
+	}
+	for _, si := range info {
+		if si.IsStagnant {
+			t.Errorf("species %d marked stagnant after %d generations without improvement", si.SpeciesID, generation)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package neat
+
+// StagnationPolicy decides whether a species has stagnated, once DefaultStagnation has updated its
+// fitness, FitnessHistory, LastImproved and BestFitness for the generation. Stagnated species are
+// removed unless species elitism spares them. The policy is chosen by stagnation_policy; to use a
+// custom one, set DefaultStagnation.Policy and install the stagnation with
+// Population.SetStagnation.
+type StagnationPolicy interface {
+	Stagnant(species *Species, generation int) bool
+}
+
+// MaxStagnationPolicy marks species that have not improved for MaxStagnation generations, as in
+// neat-python (stagnation_policy "rank-elitism", or "absolute" without species elitism).
+type MaxStagnationPolicy struct {
+	MaxStagnation int
+}
+
+// Stagnant reports whether the species last improved at least MaxStagnation generations ago.
+func (p MaxStagnationPolicy) Stagnant(species *Species, generation int) bool {
+	return generation-species.LastImproved >= p.MaxStagnation
+}
+
+// NeverStagnantPolicy never marks a species (stagnation_policy "none").
+type NeverStagnantPolicy struct{}
+
+// Stagnant always returns false.
+func (NeverStagnantPolicy) Stagnant(*Species, int) bool {
+	return false
+}
+
+// MaxAgePolicy marks species that are MaxAge generations old whether or not they still improve
+// (stagnation_policy "max-age"), so no niche occupies the population indefinitely.
+type MaxAgePolicy struct {
+	MaxAge int
+}
+
+// Stagnant reports whether the species was created at least MaxAge generations ago.
+func (p MaxAgePolicy) Stagnant(species *Species, generation int) bool {
+	return generation-species.Created >= p.MaxAge
+}
+
+// ImprovementRatePolicy marks species whose fitness has risen by less than MinRate per generation
+// over the last Window generations (stagnation_policy "improvement-rate"). Unlike
+// MaxStagnationPolicy, a species creeping up by tiny improvements still stagnates. Species younger
+// than Window generations are never marked. The rate is measured on Species.FitnessHistory, so
+// Window is effectively bounded by fitness_history_length.
+type ImprovementRatePolicy struct {
+	Window  int
+	MinRate float64
+}
+
+// Stagnant reports whether the species' fitness rose by less than MinRate per generation.
+func (p ImprovementRatePolicy) Stagnant(species *Species, generation int) bool {
+	history := species.FitnessHistory
+	if generation-species.Created < p.Window || len(history) < 2 {
+		return false // Too young, or the history was just reset by an improvement
+	}
+	span := min(p.Window, len(history)-1)
+	rate := (history[len(history)-1] - history[len(history)-1-span]) / float64(span)
+	return rate < p.MinRate
+}
+
+// newStagnationPolicy returns the policy selected by stagnation_policy.
+func newStagnationPolicy(config *StagnationConfig) StagnationPolicy {
+	switch config.Policy {
+	case "none":
+		return NeverStagnantPolicy{}
+	case "max-age":
+		return MaxAgePolicy{MaxAge: config.MaxSpeciesAge}
+	case "improvement-rate":
+		return ImprovementRatePolicy{Window: config.improvementWindow(), MinRate: config.MinImprovementRate}
+	default: // "rank-elitism", "absolute"
+		return MaxStagnationPolicy{MaxStagnation: config.MaxStagnation}
+	}
+}
@@ -0,0 +1,131 @@
+package neat
+
+// GenerationStats holds the summary values recorded for a single generation.
+type GenerationStats struct {
+	Generation     int
+	PopulationSize int
+	BestFitness    float64
+	MeanFitness    float64
+	StdevFitness   float64
+	BestGenomeKey  int
+	BestComplexity int         // Nodes + enabled connections of the generation's best genome
+	MeanComplexity float64     // Mean nodes + enabled connections across the population
+	SpeciesSizes   map[int]int // Map species key -> number of members (empty if speciation did not run)
+	Timings        PhaseTimings
+	Sizes          GenomeSizeStats // Node and connection counts and memory of the evaluated population
+}
+
+// ReportTable is a free-form table attached to the statistics for inclusion in run reports
+// (e.g., a truth table for the winning network or task-specific metrics).
+type ReportTable struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// Statistics collects per-generation data during a run.
+// Attach it to a Population via the Statistics field so RunGeneration records each generation,
+// then pass it to WriteRunReport at the end of the run.
+type Statistics struct {
+	Generations      []GenerationStats
+	Tables           []ReportTable
+	StageTransitions []StageTransition // Curriculum promotions, in order
+	ConfigChanges    []ConfigChange    // Parameters changed with Population.UpdateConfig, in order
+	// Species renumberings (see Population.ReindexSpecies), in order
+	SpeciesReindexings []SpeciesReindex
+}
+
+// NewStatistics creates an empty statistics collector.
+func NewStatistics() *Statistics {
+	return &Statistics{
+		Generations: []GenerationStats{},
+		Tables:      []ReportTable{},
+	}
+}
+
+// AddTable attaches a table to the statistics so it is included in the run report.
+func (s *Statistics) AddTable(title string, header []string, rows [][]string) {
+	s.Tables = append(s.Tables, ReportTable{Title: title, Header: header, Rows: rows})
+}
+
+// BestFitnesses returns the best fitness of each recorded generation, in order.
+func (s *Statistics) BestFitnesses() []float64 {
+	values := make([]float64, len(s.Generations))
+	for i, gs := range s.Generations {
+		values[i] = gs.BestFitness
+	}
+	return values
+}
+
+// MeanFitnesses returns the mean fitness of each recorded generation, in order.
+func (s *Statistics) MeanFitnesses() []float64 {
+	values := make([]float64, len(s.Generations))
+	for i, gs := range s.Generations {
+		values[i] = gs.MeanFitness
+	}
+	return values
+}
+
+// MeanComplexities returns the mean genome complexity of each recorded generation, in order.
+func (s *Statistics) MeanComplexities() []float64 {
+	values := make([]float64, len(s.Generations))
+	for i, gs := range s.Generations {
+		values[i] = gs.MeanComplexity
+	}
+	return values
+}
+
+// recordFitness appends a new generation entry based on the evaluated population.
+func (s *Statistics) recordFitness(generation int, population map[int]*Genome) {
+	fitnesses := make([]float64, 0, len(population))
+	complexities := make([]float64, 0, len(population))
+	gs := GenerationStats{
+		Generation:     generation,
+		PopulationSize: len(population),
+		BestGenomeKey:  -1,
+		SpeciesSizes:   make(map[int]int),
+	}
+	for _, g := range population {
+		fitnesses = append(fitnesses, g.Fitness)
+		complexities = append(complexities, float64(g.Complexity()))
+		if gs.BestGenomeKey == -1 || g.Fitness > gs.BestFitness {
+			gs.BestFitness = g.Fitness
+			gs.BestGenomeKey = g.Key
+			gs.BestComplexity = g.Complexity()
+		}
+	}
+	gs.MeanComplexity = Mean(complexities)
+	gs.MeanFitness = Mean(fitnesses)
+	gs.StdevFitness = Stdev(fitnesses)
+	s.Generations = append(s.Generations, gs)
+}
+
+// recordSizes stores the genome sizes for the most recently recorded generation.
+func (s *Statistics) recordSizes(generation int, sizes GenomeSizeStats) {
+	if len(s.Generations) == 0 || s.Generations[len(s.Generations)-1].Generation != generation {
+		return
+	}
+	s.Generations[len(s.Generations)-1].Sizes = sizes
+}
+
+// recordTimings stores the phase timings for the most recently recorded generation.
+func (s *Statistics) recordTimings(generation int, timings PhaseTimings) {
+	if len(s.Generations) == 0 || s.Generations[len(s.Generations)-1].Generation != generation {
+		return // Fitness was not recorded for this generation (yet)
+	}
+	s.Generations[len(s.Generations)-1].Timings = timings
+}
+
+// recordSpecies stores the species sizes for the most recently recorded generation.
+func (s *Statistics) recordSpecies(generation int, speciesSet *SpeciesSet) {
+	if len(s.Generations) == 0 {
+		return
+	}
+	gs := &s.Generations[len(s.Generations)-1]
+	if gs.Generation != generation {
+		return // Fitness was not recorded for this generation; don't attach species to the wrong entry.
+	}
+	for sid, sp := range speciesSet.Species {
+		gs.SpeciesSizes[sid] = len(sp.Members)
+	}
+}
@@ -0,0 +1,139 @@
+package neat
+
+import "errors"
+
+// ErrGenerationNotStored is returned by Store methods for generations that were never saved.
+var ErrGenerationNotStored = errors.New("generation not found in store")
+
+// SpeciesRecord is the state of one species at the end of a generation.
+type SpeciesRecord struct {
+	Key             int
+	Size            int
+	Created         int
+	LastImproved    int
+	Fitness         float64
+	AdjustedFitness float64
+	BestFitness     float64
+	ChampionKey     int // Key of the fittest member
+	ChampionFitness float64
+}
+
+// GenerationRecord is what a Store keeps for one generation.
+type GenerationRecord struct {
+	Generation     int
+	PopulationSize int
+	BestFitness    float64
+	MeanFitness    float64
+	StdevFitness   float64
+	Champion       *CompactGenome  // Fittest genome of the generation
+	Species        []SpeciesRecord // Species after speciation, by key (empty for the generation that ended the run)
+}
+
+// Store persists per-generation records of a run, so champions and species statistics can be
+// queried after the run without keeping a checkpoint of every generation. Implementations live
+// in their own packages (see boltstore) so neat does not depend on a database.
+type Store interface {
+	// SaveGeneration stores the record, replacing any earlier record of the same generation.
+	// genomes, if not nil, is the evaluated population, stored for LoadGenomes.
+	SaveGeneration(record *GenerationRecord, genomes []*CompactGenome) error
+	// LoadGeneration returns the record of a generation, or ErrGenerationNotStored.
+	LoadGeneration(generation int) (*GenerationRecord, error)
+	// LoadGenomes returns the population stored with a generation, sorted by key, or
+	// ErrGenerationNotStored if the generation was saved without genomes.
+	LoadGenomes(generation int) ([]*CompactGenome, error)
+	// Generations returns the stored generations in increasing order.
+	Generations() ([]int, error)
+	Close() error
+}
+
+// StoreReporter saves a GenerationRecord of every generation to Store, optionally with the whole
+// evaluated population. Records are saved once the generation's species are known, or right away
+// for a generation that ends the run by solving the task or going extinct. Write failures are
+// logged rather than aborting the run. Closing the store is left to the caller.
+type StoreReporter struct {
+	BaseReporter
+	Store       Store
+	FullGenomes bool   // Also save every genome of each generation
+	Logger      Logger // Destination for write failures (nil = DefaultLogger)
+
+	generation int
+	pending    *GenerationRecord // Evaluated but not yet saved
+	genomes    []*CompactGenome
+}
+
+// NewStoreReporter creates a reporter saving champions and species statistics to store.
+func NewStoreReporter(store Store) *StoreReporter {
+	return &StoreReporter{Store: store}
+}
+
+func (r *StoreReporter) StartGeneration(generation int) {
+	r.generation = generation
+	r.pending, r.genomes = nil, nil
+}
+
+func (r *StoreReporter) PostEvaluate(config *Config, population map[int]*Genome, speciesSet *SpeciesSet, best *Genome) {
+	fitnesses := make([]float64, 0, len(population))
+	for _, g := range population {
+		fitnesses = append(fitnesses, g.Fitness)
+	}
+	record := &GenerationRecord{
+		Generation:     r.generation,
+		PopulationSize: len(population),
+		MeanFitness:    Mean(fitnesses),
+		StdevFitness:   Stdev(fitnesses),
+	}
+	if best != nil {
+		record.BestFitness = best.Fitness
+		record.Champion = best.Compact()
+	}
+	r.genomes = nil
+	if r.FullGenomes {
+		r.genomes = make([]*CompactGenome, 0, len(population))
+		for _, key := range sortedGenomeKeys(population) {
+			r.genomes = append(r.genomes, population[key].Compact())
+		}
+	}
+	r.pending = record
+}
+
+func (r *StoreReporter) EndGeneration(config *Config, population map[int]*Genome, speciesSet *SpeciesSet) {
+	if r.pending != nil && speciesSet != nil {
+		champions := speciesSet.Champions()
+		for _, sid := range sortedKeys(speciesSet.Species) {
+			sp := speciesSet.Species[sid]
+			sr := SpeciesRecord{
+				Key:             sid,
+				Size:            len(sp.Members),
+				Created:         sp.Created,
+				LastImproved:    sp.LastImproved,
+				Fitness:         sp.Fitness,
+				AdjustedFitness: sp.AdjustedFitness,
+				BestFitness:     sp.BestFitness,
+			}
+			if champion := champions[sid]; champion != nil {
+				sr.ChampionKey, sr.ChampionFitness = champion.Key, champion.Fitness
+			}
+			r.pending.Species = append(r.pending.Species, sr)
+		}
+	}
+	r.flush()
+}
+
+func (r *StoreReporter) FoundSolution(config *Config, generation int, best *Genome) {
+	r.flush()
+}
+
+func (r *StoreReporter) CompleteExtinction() {
+	r.flush()
+}
+
+// flush saves the pending record, if any.
+func (r *StoreReporter) flush() {
+	if r.pending == nil {
+		return
+	}
+	if err := r.Store.SaveGeneration(r.pending, r.genomes); err != nil {
+		loggerOrDefault(r.Logger).Warn("Failed to store generation", "generation", r.pending.Generation, "error", err)
+	}
+	r.pending, r.genomes = nil, nil
+}
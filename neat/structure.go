@@ -0,0 +1,40 @@
+package neat
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StructureHash returns a hex SHA-256 digest of the genome's topology: its node keys and enabled
+// connections. Weights, biases, activation functions and disabled connections are ignored, so
+// genomes differing only in parameters share a hash. Hashes are stable across runs and processes.
+func (g *Genome) StructureHash() string {
+	var b strings.Builder
+	for _, k := range sortedNodeKeys(g) {
+		fmt.Fprintf(&b, "n%d;", k)
+	}
+	for _, ck := range sortedConnectionKeys(g) {
+		if g.Connections[ck].Enabled {
+			fmt.Fprintf(&b, "c%d>%d;", ck.InNodeID, ck.OutNodeID)
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// GroupByStructure groups genome keys by StructureHash. Each group's keys are sorted.
+// The number of groups divided by the number of genomes measures structural diversity.
+func GroupByStructure(genomes map[int]*Genome) map[string][]int {
+	groups := make(map[string][]int)
+	for key, g := range genomes {
+		hash := g.StructureHash()
+		groups[hash] = append(groups[hash], key)
+	}
+	for _, keys := range groups {
+		sort.Ints(keys)
+	}
+	return groups
+}
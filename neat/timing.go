@@ -0,0 +1,84 @@
+package neat
+
+import (
+	"context"
+	"runtime/pprof"
+	"time"
+)
+
+// Phase is a timed part of a generation.
+type Phase string
+
+const (
+	PhaseEvaluation   Phase = "evaluation"   // Fitness evaluation, including the evaluation cache
+	PhaseSpeciation   Phase = "speciation"   // SpeciesSet.Speciate
+	PhaseReproduction Phase = "reproduction" // Reproduction.Reproduce, including stagnation
+	PhaseCheckpoint   Phase = "checkpoint"   // Population.SaveCheckpoint
+)
+
+// PhaseTimings holds the wall time spent in each phase of a generation.
+type PhaseTimings struct {
+	Evaluation   time.Duration
+	Speciation   time.Duration
+	Reproduction time.Duration
+	Checkpoint   time.Duration // Checkpoints saved after the generation, e.g. by Run's CheckpointEvery
+	Total        time.Duration // All of RunGeneration, including reporters and hooks but not checkpoints
+}
+
+// add adds elapsed to the time of phase.
+func (t *PhaseTimings) add(phase Phase, elapsed time.Duration) {
+	switch phase {
+	case PhaseEvaluation:
+		t.Evaluation += elapsed
+	case PhaseSpeciation:
+		t.Speciation += elapsed
+	case PhaseReproduction:
+		t.Reproduction += elapsed
+	case PhaseCheckpoint:
+		t.Checkpoint += elapsed
+	}
+}
+
+// runPhase runs f, the given phase of the current generation, and records its wall time. With
+// profile_labels, f runs labelled "neat_phase" = phase for pprof. Goroutines started by f inherit
+// the label, so CPU profiles attribute the time spent by parallel fitness evaluation to the
+// evaluation phase as well.
+func (p *Population) runPhase(phase Phase, f func() error) error {
+	start := time.Now()
+	var err error
+	if p.Config.Neat.ProfileLabels {
+		pprof.Do(context.Background(), pprof.Labels("neat_phase", string(phase)), func(context.Context) {
+			err = f()
+		})
+	} else {
+		err = f()
+	}
+	p.recordPhase(phase, time.Since(start))
+	return err
+}
+
+// recordPhase adds elapsed to the timings of the current generation and reports it.
+func (p *Population) recordPhase(phase Phase, elapsed time.Duration) {
+	p.timings.add(phase, elapsed)
+	if p.Statistics != nil {
+		p.Statistics.recordTimings(p.Generation, p.timings)
+	}
+	p.Reporters.PhaseFinished(p.Generation, phase, elapsed)
+}
+
+// finishGeneration records the total time of the generation started at start and logs the time
+// of each phase.
+func (p *Population) finishGeneration(start time.Time) {
+	p.timings.Total = time.Since(start)
+	if p.Statistics != nil {
+		p.Statistics.recordTimings(p.Generation, p.timings)
+	}
+	p.log().Info("Generation finished", "generation", p.Generation, "duration", p.timings.Total,
+		"evaluation", p.timings.Evaluation, "speciation", p.timings.Speciation, "reproduction", p.timings.Reproduction)
+}
+
+// Timings returns the wall time spent in each phase of the last generation so far, including
+// checkpoints saved since it ended.
+func (p *Population) Timings() PhaseTimings {
+	return p.timings
+}
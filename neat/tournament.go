@@ -0,0 +1,255 @@
+package neat
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Outcome is the result of a game for its first player.
+type Outcome int
+
+const (
+	Loss Outcome = iota
+	Draw
+	Win
+)
+
+// GameFunc plays a game between a and b and returns the outcome for a. It is called concurrently,
+// so each call must build its own networks and game state.
+type GameFunc func(a, b *Genome) (Outcome, error)
+
+// Tournament turns game outcomes among the genomes of a generation into fitness. Pairing
+// "round-robin" (default) plays every pair of genomes; "swiss" plays Rounds rounds in which
+// genomes with similar scores so far meet, never twice, which needs far fewer games for large
+// populations. With an odd number of genomes, the lowest-ranked genome without a bye sits out
+// each Swiss round and scores a win (with points scoring).
+//
+// Scoring "points" (default) sums WinPoints, DrawPoints and LossPoints over a genome's games;
+// "elo" starts every genome at EloInitial and applies the Elo update with factor EloK after each
+// game, in a deterministic order, and uses the final rating as fitness. If Ratings is set, every
+// game also updates those persistent ratings, and scoring "rating" uses Ratings.Score as fitness,
+// so skill estimates accumulate across generations. The games of a round run in parallel.
+type Tournament struct {
+	Game       GameFunc
+	Pairing    string   // "round-robin" (default) or "swiss"
+	Rounds     int      // Swiss rounds (0 = ceil(log2(genomes)))
+	BothSides  bool     // Play every pairing twice, each genome moving first once (for games with a first-move advantage)
+	Scoring    string   // "points" (default), "elo" or "rating"
+	WinPoints  float64  // Points for a win (default 1)
+	DrawPoints float64  // Points for a draw (default 0.5)
+	LossPoints float64  // Points for a loss (default 0)
+	EloK       float64  // Elo update factor (default 32)
+	EloInitial float64  // Initial Elo rating (default 1500)
+	Workers    int      // Max games played at once (0 = runtime.NumCPU())
+	Games      int      // Total number of games played
+	Ratings    *Ratings // Optional persistent ratings updated with every game, e.g. Population.Ratings
+}
+
+// NewTournament creates a round-robin tournament scored with 1/0.5/0 points, using all CPUs.
+func NewTournament(game GameFunc) *Tournament {
+	return &Tournament{
+		Game:       game,
+		Pairing:    "round-robin",
+		Scoring:    "points",
+		WinPoints:  1,
+		DrawPoints: 0.5,
+		EloK:       32,
+		EloInitial: 1500,
+		Workers:    runtime.NumCPU(),
+	}
+}
+
+// game is one scheduled game and its outcome for a.
+type game struct {
+	a, b    *Genome
+	outcome Outcome
+	err     error
+}
+
+// Play runs the tournament among genomes and returns each genome's score by genome key.
+// It does not modify the genomes.
+func (t *Tournament) Play(genomes map[int]*Genome) (map[int]float64, error) {
+	if t.Game == nil {
+		return nil, fmt.Errorf("tournament requires a game function")
+	}
+	scoring := t.Scoring
+	if scoring == "" {
+		scoring = "points"
+	}
+	if scoring != "points" && scoring != "elo" && scoring != "rating" {
+		return nil, fmt.Errorf("invalid tournament scoring '%s', must be 'points', 'elo' or 'rating'", t.Scoring)
+	}
+	if scoring == "rating" && t.Ratings == nil {
+		return nil, fmt.Errorf("tournament scoring 'rating' requires Ratings")
+	}
+	keys := sortedGenomeKeys(genomes)
+	scores := make(map[int]float64, len(keys))
+	for _, k := range keys {
+		if scoring == "elo" {
+			scores[k] = t.EloInitial
+		} else {
+			scores[k] = 0
+		}
+	}
+
+	switch t.Pairing {
+	case "", "round-robin":
+		var pairs [][2]*Genome
+		for i, ka := range keys {
+			for _, kb := range keys[i+1:] {
+				pairs = append(pairs, [2]*Genome{genomes[ka], genomes[kb]})
+			}
+		}
+		if err := t.playRound(pairs, scores, scoring); err != nil {
+			return nil, err
+		}
+	case "swiss":
+		if err := t.playSwiss(genomes, keys, scores, scoring); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid tournament pairing '%s', must be 'round-robin' or 'swiss'", t.Pairing)
+	}
+	if scoring == "rating" {
+		for _, k := range keys {
+			scores[k] = t.Ratings.Score(k)
+		}
+	}
+	return scores, nil
+}
+
+// playSwiss plays the Swiss rounds, pairing genomes of similar score that haven't met yet.
+func (t *Tournament) playSwiss(genomes map[int]*Genome, keys []int, scores map[int]float64, scoring string) error {
+	rounds := t.Rounds
+	if rounds <= 0 {
+		rounds = int(math.Ceil(math.Log2(float64(max(len(keys), 2)))))
+	}
+	met := make(map[ConnectionKey]bool)
+	hadBye := make(map[int]bool)
+	for round := 0; round < rounds; round++ {
+		ranked := append([]int(nil), keys...)
+		sort.SliceStable(ranked, func(i, j int) bool { return scores[ranked[i]] > scores[ranked[j]] })
+		if len(ranked)%2 == 1 {
+			bye := len(ranked) - 1
+			for i := len(ranked) - 1; i >= 0; i-- {
+				if !hadBye[ranked[i]] {
+					bye = i
+					break
+				}
+			}
+			hadBye[ranked[bye]] = true
+			if scoring == "points" {
+				scores[ranked[bye]] += t.WinPoints
+				if t.BothSides {
+					scores[ranked[bye]] += t.WinPoints // A bye stands for both games of the pairing
+				}
+			}
+			ranked = append(ranked[:bye], ranked[bye+1:]...)
+		}
+
+		// Pair each unpaired genome with the highest-ranked one it hasn't met, or the next one if
+		// it has met them all.
+		var pairs [][2]*Genome
+		paired := make(map[int]bool, len(ranked))
+		for i, ka := range ranked {
+			if paired[ka] {
+				continue
+			}
+			opponent := -1
+			for _, kb := range ranked[i+1:] {
+				if paired[kb] {
+					continue
+				}
+				if opponent == -1 {
+					opponent = kb
+				}
+				if !met[ConnectionKey{InNodeID: min(ka, kb), OutNodeID: max(ka, kb)}] {
+					opponent = kb
+					break
+				}
+			}
+			if opponent == -1 {
+				continue
+			}
+			paired[ka], paired[opponent] = true, true
+			met[ConnectionKey{InNodeID: min(ka, opponent), OutNodeID: max(ka, opponent)}] = true
+			pairs = append(pairs, [2]*Genome{genomes[ka], genomes[opponent]})
+		}
+		if err := t.playRound(pairs, scores, scoring); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// playRound plays the games of pairs in parallel and adds their results to scores, in order.
+func (t *Tournament) playRound(pairs [][2]*Genome, scores map[int]float64, scoring string) error {
+	games := make([]game, 0, 2*len(pairs))
+	for _, p := range pairs {
+		games = append(games, game{a: p[0], b: p[1]})
+		if t.BothSides {
+			games = append(games, game{a: p[1], b: p[0]})
+		}
+	}
+
+	workers := t.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	semaphore := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := range games {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(gm *game) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			gm.outcome, gm.err = t.Game(gm.a, gm.b)
+		}(&games[i])
+	}
+	wg.Wait()
+	t.Games += len(games)
+
+	for _, gm := range games {
+		if gm.err != nil {
+			return fmt.Errorf("game of genome %d against genome %d failed: %w", gm.a.Key, gm.b.Key, gm.err)
+		}
+		if gm.outcome < Loss || gm.outcome > Win {
+			return fmt.Errorf("game of genome %d against genome %d returned invalid outcome %d", gm.a.Key, gm.b.Key, gm.outcome)
+		}
+		if t.Ratings != nil {
+			if err := t.Ratings.Record(gm.a.Key, gm.b.Key, gm.outcome); err != nil {
+				return err
+			}
+		}
+		if scoring == "elo" {
+			ra, rb := scores[gm.a.Key], scores[gm.b.Key]
+			expected := 1 / (1 + math.Pow(10, (rb-ra)/400))
+			actual := float64(gm.outcome) / 2 // Loss 0, draw 0.5, win 1
+			scores[gm.a.Key] = ra + t.EloK*(actual-expected)
+			scores[gm.b.Key] = rb - t.EloK*(actual-expected)
+			continue
+		}
+		points := [...]float64{t.LossPoints, t.DrawPoints, t.WinPoints}
+		scores[gm.a.Key] += points[gm.outcome]
+		scores[gm.b.Key] += points[Win-gm.outcome]
+	}
+	return nil
+}
+
+// FitnessFunc returns a FitnessFunc that sets each genome's fitness to its tournament score.
+func (t *Tournament) FitnessFunc() FitnessFunc {
+	return func(genomes map[int]*Genome) error {
+		scores, err := t.Play(genomes)
+		if err != nil {
+			return err
+		}
+		for k, g := range genomes {
+			g.Fitness = scores[k]
+		}
+		return nil
+	}
+}
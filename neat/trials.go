@@ -0,0 +1,292 @@
+package neat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// TrialFunc runs a single evaluation trial of a genome and returns its score.
+// It is called concurrently for the same genome, so each call must build its own
+// environment and network (e.g., via nn.CreateFeedForwardNetwork) rather than sharing state.
+type TrialFunc func(g *Genome, trial int) (float64, error)
+
+// ResumableTrialFunc is a TrialFunc for trials long enough to need their own checkpoints.
+// The trial should periodically save its state through cp, resume from cp.LoadState when
+// state is present, and return cp.Context().Err() promptly once the context is cancelled.
+type ResumableTrialFunc func(g *Genome, trial int, cp *TrialCheckpoint) (float64, error)
+
+// TrialBoundFunc returns the highest fitness a genome could still reach given the scores of the
+// trials completed so far and the total number of trials. It enables early exit: once the bound
+// falls below the elite cutoff, the remaining trials are skipped.
+type TrialBoundFunc func(completed []float64, trials int) float64
+
+// MeanUpperBound returns a TrialBoundFunc for mean aggregation where a single trial scores at most maxScore.
+func MeanUpperBound(maxScore float64) TrialBoundFunc {
+	return func(completed []float64, trials int) float64 {
+		remaining := trials - len(completed)
+		return (Sum(completed) + float64(remaining)*maxScore) / float64(trials)
+	}
+}
+
+// TrialEvaluator evaluates each genome over several trials, running the trials of a single
+// genome concurrently and aggregating their scores into the genome's fitness.
+// Genomes themselves are evaluated one after another; this targets small populations with many
+// expensive trials rather than parallelism across genomes.
+//
+// If UpperBound is set, trials run in batches of Workers and a genome stops early once its bound
+// shows it cannot reach the fitness of the EliteCount-th best genome evaluated so far in the
+// generation. Such genomes receive the aggregate of the trials they completed.
+//
+// If Progress is set, the score of every completed trial is persisted under the genome's content
+// hash, and ResumableTrial functions can persist their in-trial state. Cancelling Context stops the
+// evaluation; running it again for the same genome (even after a process restart) skips completed
+// trials and resumes the interrupted ones. A genome's progress is deleted once it is fully evaluated.
+type TrialEvaluator struct {
+	Trials         int                // Number of trials per genome
+	Workers        int                // Max trials running at once (0 = runtime.NumCPU())
+	Aggregation    string             // Name of the StatFunctions entry used to combine trial scores (default "mean")
+	Trial          TrialFunc          // User-provided trial function
+	ResumableTrial ResumableTrialFunc // Checkpointing trial function, used instead of Trial when set
+	UpperBound     TrialBoundFunc     // Optional fitness bound enabling early exit (nil runs every trial)
+	EliteCount     int                // Rank of the genome whose fitness is the early-exit cutoff (0 = 1, the best genome)
+	TrialsSkipped  int                // Total number of trials skipped by early exit
+	Progress       ProgressStore      // Optional store for partial progress (nil disables resuming)
+	Context        context.Context    // Optional context; cancelling it stops the evaluation (nil = never)
+}
+
+// NewTrialEvaluator creates an evaluator running the given number of trials per genome,
+// using all CPUs and mean aggregation.
+func NewTrialEvaluator(trials int, trial TrialFunc) *TrialEvaluator {
+	return &TrialEvaluator{
+		Trials:      trials,
+		Workers:     runtime.NumCPU(),
+		Aggregation: "mean",
+		Trial:       trial,
+	}
+}
+
+// EvaluateGenome runs all trials for g concurrently and returns the aggregated score.
+// It does not modify g.Fitness.
+func (te *TrialEvaluator) EvaluateGenome(g *Genome) (float64, error) {
+	return te.evaluateWithCutoff(g, math.Inf(-1))
+}
+
+// evaluateWithCutoff runs the trials for g, stopping early if the upper bound drops below cutoff.
+func (te *TrialEvaluator) evaluateWithCutoff(g *Genome, cutoff float64) (float64, error) {
+	if te.Trials <= 0 {
+		return 0, fmt.Errorf("trial evaluator requires a positive number of trials, got %d", te.Trials)
+	}
+	aggregation := te.Aggregation
+	if aggregation == "" {
+		aggregation = "mean"
+	}
+	aggregate, ok := StatFunctions[aggregation]
+	if !ok {
+		return 0, fmt.Errorf("unknown trial aggregation function: %s", aggregation)
+	}
+	workers := te.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	progress, err := te.loadProgress(g)
+	if err != nil {
+		return 0, err
+	}
+
+	// Without a bound, all trials form a single batch.
+	batchSize := te.Trials
+	if te.UpperBound != nil {
+		batchSize = workers
+	}
+
+	scores := make([]float64, 0, te.Trials)
+	for start := 0; start < te.Trials; start += batchSize {
+		end := min(start+batchSize, te.Trials)
+		batch, err := te.runTrials(g, start, end, workers, progress)
+		if err != nil {
+			return 0, err
+		}
+		scores = append(scores, batch...)
+		if te.UpperBound != nil && end < te.Trials && te.UpperBound(scores, te.Trials) < cutoff {
+			te.TrialsSkipped += te.Trials - end
+			break
+		}
+	}
+	if err := progress.clear(); err != nil {
+		return 0, err
+	}
+	return aggregate(scores), nil
+}
+
+// runTrials runs trials [start, end) of g with at most workers running concurrently.
+// Trials already recorded in progress are not rerun.
+func (te *TrialEvaluator) runTrials(g *Genome, start, end, workers int, progress *trialProgress) ([]float64, error) {
+	ctx := te.context()
+	scores := make([]float64, end-start)
+	errs := make([]error, end-start)
+	semaphore := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := start; i < end; i++ {
+		if score, ok := progress.completed(i); ok {
+			scores[i-start] = score
+			continue
+		}
+		semaphore <- struct{}{}
+		if ctx.Err() != nil {
+			<-semaphore
+			break
+		}
+		wg.Add(1)
+		go func(trial int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			score, err := te.runTrial(ctx, g, trial, progress)
+			if err == nil {
+				err = progress.record(trial, score)
+			}
+			scores[trial-start], errs[trial-start] = score, err
+		}(i)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("evaluation of genome %d stopped: %w", g.Key, ctx.Err())
+	}
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("trial %d of genome %d failed: %w", start+i, g.Key, err)
+		}
+	}
+	return scores, nil
+}
+
+// runTrial runs a single trial with whichever trial function is configured.
+func (te *TrialEvaluator) runTrial(ctx context.Context, g *Genome, trial int, progress *trialProgress) (float64, error) {
+	if te.ResumableTrial == nil {
+		return te.Trial(g, trial)
+	}
+	cp := &TrialCheckpoint{ctx: ctx, store: te.Progress, key: progress.stateKey(trial)}
+	return te.ResumableTrial(g, trial, cp)
+}
+
+func (te *TrialEvaluator) context() context.Context {
+	if te.Context == nil {
+		return context.Background()
+	}
+	return te.Context
+}
+
+// trialProgress tracks the completed trial scores of one genome and mirrors them to the progress store.
+// A nil *trialProgress records nothing.
+type trialProgress struct {
+	store  ProgressStore
+	hash   string
+	mu     sync.Mutex
+	scores map[int]float64
+}
+
+// loadProgress loads the saved progress of g, or returns nil if no store is configured or g has an
+// extension that can't be hashed.
+func (te *TrialEvaluator) loadProgress(g *Genome) (*trialProgress, error) {
+	if te.Progress == nil {
+		return nil, nil
+	}
+	hash, ok := genomeContentHash(g)
+	if !ok {
+		return nil, nil
+	}
+	tp := &trialProgress{store: te.Progress, hash: hash, scores: map[int]float64{}}
+	data, found, err := tp.store.Load(tp.hash)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		if err := json.Unmarshal(data, &tp.scores); err != nil {
+			return nil, fmt.Errorf("failed to decode trial progress of genome %d: %w", g.Key, err)
+		}
+	}
+	return tp, nil
+}
+
+func (tp *trialProgress) completed(trial int) (float64, bool) {
+	if tp == nil {
+		return 0, false
+	}
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	score, ok := tp.scores[trial]
+	return score, ok
+}
+
+// record saves the score of a completed trial and discards the trial's in-progress state.
+func (tp *trialProgress) record(trial int, score float64) error {
+	if tp == nil {
+		return nil
+	}
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.scores[trial] = score
+	data, err := json.Marshal(tp.scores)
+	if err != nil {
+		return err
+	}
+	if err := tp.store.Save(tp.hash, data); err != nil {
+		return err
+	}
+	return tp.store.Delete(tp.stateKey(trial))
+}
+
+// clear deletes all progress of the genome once its evaluation has finished.
+func (tp *trialProgress) clear() error {
+	if tp == nil {
+		return nil
+	}
+	return tp.store.Delete(tp.hash)
+}
+
+func (tp *trialProgress) stateKey(trial int) string {
+	if tp == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s-trial-%d", tp.hash, trial)
+}
+
+// FitnessFunc returns a FitnessFunc that sets each genome's fitness to its aggregated trial score.
+// Genomes are evaluated in key order so early-exit decisions are reproducible.
+func (te *TrialEvaluator) FitnessFunc() FitnessFunc {
+	return func(genomes map[int]*Genome) error {
+		keys := make([]int, 0, len(genomes))
+		for k := range genomes {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+
+		eliteCount := max(te.EliteCount, 1)
+		elite := make([]float64, 0, eliteCount+1) // Best fitnesses so far, descending
+		for _, k := range keys {
+			g := genomes[k]
+			cutoff := math.Inf(-1)
+			if len(elite) == eliteCount {
+				cutoff = elite[eliteCount-1]
+			}
+			fitness, err := te.evaluateWithCutoff(g, cutoff)
+			if err != nil {
+				return err
+			}
+			g.Fitness = fitness
+
+			elite = append(elite, fitness)
+			sort.Sort(sort.Reverse(sort.Float64Slice(elite)))
+			if len(elite) > eliteCount {
+				elite = elite[:eliteCount]
+			}
+		}
+		return nil
+	}
+}
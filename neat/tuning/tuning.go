@@ -0,0 +1,301 @@
+// Package tuning searches for good NEAT hyperparameters by running many short evolutions with
+// sampled configurations in parallel, either as a plain random search or with successive halving,
+// which gives more generations to the configurations that do well early on.
+package tuning
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/baldhumanity/neat-go/neat"
+)
+
+// Param is one searched parameter: an adjustable config key (see neat.AdjustableConfigKeys)
+// sampled uniformly from [Min, Max].
+type Param struct {
+	Key      string
+	Min, Max float64
+	Log      bool // Sample uniformly in log space (Min and Max must be positive)
+	Integer  bool // Round samples to whole numbers (required for integer parameters such as elitism)
+}
+
+// sample draws a value for the parameter.
+func (p Param) sample(float func() float64) float64 {
+	var v float64
+	if p.Log {
+		v = math.Exp(math.Log(p.Min) + float()*(math.Log(p.Max)-math.Log(p.Min)))
+	} else {
+		v = p.Min + float()*(p.Max-p.Min)
+	}
+	if p.Integer {
+		v = math.Round(v)
+	}
+	return v
+}
+
+// Trial is one sampled configuration and the outcome of its evolution.
+type Trial struct {
+	ID          int
+	Patch       neat.ConfigPatch // Sampled parameter values, applied on top of the config file
+	Generations int              // Generations run
+	Solved      int              // Generation in which the fitness threshold was reached (0 = not reached)
+	Score       float64          // Value of Tuner.Score after the last generation run
+	Statistics  *neat.Statistics // Per-generation statistics of the run
+	Checkpoint  string           // Path of the latest checkpoint (empty without Tuner.Dir)
+
+	population *neat.Population // Kept while the trial may still be continued
+}
+
+// Tuner runs the search. Every trial loads ConfigPath afresh, applies its sampled parameters with
+// Population.UpdateConfig and evolves with Fitness, which is called concurrently by different
+// trials and must therefore be safe for concurrent use.
+//
+// Method "random" (default) runs every trial for Generations generations. Method "halving" runs
+// every trial for Generations generations, keeps the best 1/Eta of them, continues those until
+// they have run Eta times as many generations, and so on until one trial is left. Trials that
+// reach the fitness threshold stop early and keep their score.
+type Tuner struct {
+	ConfigPath  string
+	Fitness     neat.FitnessFunc
+	Params      []Param
+	Trials      int        // Number of sampled configurations
+	Generations int        // Generations per trial ("random") or in the first round ("halving")
+	Method      string     // "random" (default) or "halving"
+	Eta         int        // Halving factor (default 3)
+	Workers     int        // Max trials run at once (0 = runtime.NumCPU())
+	Rand        *rand.Rand // Source for sampling (nil = the math/rand top-level functions)
+	Logger      neat.Logger
+	// Dir, if set, receives a checkpoint of every trial after each round it runs, as
+	// trial-<ID>.gz; resume the best one with neat.LoadCheckpoint(trial.Checkpoint, ConfigPath)
+	// (its sampled parameters are restored from the checkpoint).
+	Dir string
+	// Score rates a trial after a round; higher is better. The default is the best fitness
+	// reached, with ties broken in favour of trials that solved the task sooner.
+	Score func(t *Trial) float64
+}
+
+// NewTuner creates a random search of 20 trials of 20 generations each over params.
+func NewTuner(configPath string, fitness neat.FitnessFunc, params ...Param) *Tuner {
+	return &Tuner{
+		ConfigPath:  configPath,
+		Fitness:     fitness,
+		Params:      params,
+		Trials:      20,
+		Generations: 20,
+		Method:      "random",
+		Eta:         3,
+		Workers:     runtime.NumCPU(),
+	}
+}
+
+// BestFitness is the default trial score: the best fitness of any generation, minus a tiny
+// amount per generation needed to reach the fitness threshold so faster solutions rank higher.
+func BestFitness(t *Trial) float64 {
+	best := math.Inf(-1)
+	for _, gs := range t.Statistics.Generations {
+		best = math.Max(best, gs.BestFitness)
+	}
+	if t.Solved > 0 {
+		best -= 1e-9 * float64(t.Solved)
+	}
+	return best
+}
+
+// Run samples the configurations, evolves them and returns all trials, best first.
+func (t *Tuner) Run() ([]*Trial, error) {
+	if t.Fitness == nil {
+		return nil, fmt.Errorf("tuning requires a fitness function")
+	}
+	if t.Trials <= 0 || t.Generations <= 0 {
+		return nil, fmt.Errorf("tuning requires a positive number of trials and generations")
+	}
+	for _, p := range t.Params {
+		if p.Max < p.Min || (p.Log && p.Min <= 0) {
+			return nil, fmt.Errorf("invalid range [%v, %v] for parameter %s", p.Min, p.Max, p.Key)
+		}
+	}
+	float := rand.Float64
+	if t.Rand != nil {
+		float = t.Rand.Float64
+	}
+	trials := make([]*Trial, t.Trials)
+	for i := range trials {
+		patch := make(neat.ConfigPatch, len(t.Params))
+		for _, p := range t.Params {
+			patch[p.Key] = p.sample(float)
+		}
+		trials[i] = &Trial{ID: i, Patch: patch}
+	}
+
+	switch t.Method {
+	case "", "random":
+		if err := t.runRound(trials, t.Generations); err != nil {
+			return nil, err
+		}
+	case "halving":
+		eta := t.Eta
+		if eta < 2 {
+			eta = 3
+		}
+		active, budget := trials, t.Generations
+		for {
+			if err := t.runRound(active, budget); err != nil {
+				return nil, err
+			}
+			t.rank(active)
+			if len(active) == 1 {
+				break
+			}
+			keep := (len(active) + eta - 1) / eta
+			for _, trial := range active[keep:] {
+				trial.population = nil // Eliminated; its checkpoint, if any, stays on disk
+			}
+			active = active[:keep]
+			budget *= eta
+		}
+	default:
+		return nil, fmt.Errorf("invalid tuning method '%s', must be 'random' or 'halving'", t.Method)
+	}
+	t.rank(trials)
+	for _, trial := range trials {
+		trial.population = nil
+	}
+	return trials, nil
+}
+
+// runRound evolves every trial in parallel until it has run generations generations in total
+// (or solved the task), then scores it.
+func (t *Tuner) runRound(trials []*Trial, generations int) error {
+	workers := t.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	errs := make([]error, len(trials))
+	semaphore := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, trial := range trials {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(i int, trial *Trial) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			errs[i] = t.runTrial(trial, generations)
+		}(i, trial)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("trial %d failed: %w", trials[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// runTrial creates the trial's population if needed and evolves it up to generations.
+func (t *Tuner) runTrial(trial *Trial, generations int) error {
+	p := trial.population
+	if p == nil && trial.Statistics == nil {
+		config, err := neat.LoadConfig(t.ConfigPath)
+		if err != nil {
+			return err
+		}
+		if p, err = neat.NewPopulation(config); err != nil {
+			return err
+		}
+		logger := t.Logger
+		if logger == nil {
+			logger = neat.NewConsoleLogger(io.Discard, slog.LevelError)
+		}
+		p.SetLogger(logger)
+		p.Statistics = neat.NewStatistics()
+		if err := p.UpdateConfig(trial.Patch); err != nil {
+			return err
+		}
+		trial.population = p
+		trial.Statistics = p.Statistics
+	}
+	for p != nil && trial.Solved == 0 && trial.Generations < generations {
+		winner, err := p.RunGeneration(t.Fitness)
+		trial.Generations = p.Generation
+		if errors.Is(err, neat.ErrExtinction) {
+			trial.population = nil // Nothing left to evolve
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if winner != nil {
+			trial.Solved = p.Generation
+		}
+	}
+
+	score := t.Score
+	if score == nil {
+		score = BestFitness
+	}
+	trial.Score = score(trial)
+	if t.Dir != "" && trial.population != nil {
+		trial.Checkpoint = filepath.Join(t.Dir, fmt.Sprintf("trial-%d.gz", trial.ID))
+		if err := trial.population.SaveCheckpoint(trial.Checkpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rank sorts trials by score, best first (ties by lower ID).
+func (t *Tuner) rank(trials []*Trial) {
+	sort.SliceStable(trials, func(i, j int) bool {
+		if trials[i].Score != trials[j].Score {
+			return trials[i].Score > trials[j].Score
+		}
+		return trials[i].ID < trials[j].ID
+	})
+}
+
+// WriteCSV writes one row per trial, in the given order, with its outcome and sampled values.
+func WriteCSV(trials []*Trial, filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create tuning CSV '%s': %w", filePath, err)
+	}
+	defer f.Close()
+
+	var keys []string
+	if len(trials) > 0 {
+		for key := range trials[0].Patch {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+	w := csv.NewWriter(f)
+	w.Write(append([]string{"trial", "score", "generations", "solved"}, keys...))
+	for _, trial := range trials {
+		record := []string{
+			strconv.Itoa(trial.ID),
+			strconv.FormatFloat(trial.Score, 'g', -1, 64),
+			strconv.Itoa(trial.Generations),
+			strconv.Itoa(trial.Solved),
+		}
+		for _, key := range keys {
+			record = append(record, strconv.FormatFloat(trial.Patch[key], 'g', -1, 64))
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write tuning CSV '%s': %w", filePath, err)
+	}
+	return f.Close()
+}
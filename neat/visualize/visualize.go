@@ -0,0 +1,149 @@
+// Package visualize renders plots of NEAT runs with gonum/plot, in the spirit of neat-python's
+// visualize module. The output format follows the file extension (.svg, .png, .pdf, ...).
+package visualize
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/baldhumanity/neat-go/neat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Size of the saved plots.
+var (
+	Width  = 8 * vg.Inch
+	Height = 5 * vg.Inch
+)
+
+// speciesColor returns a distinct fill color for the i-th species.
+func speciesColor(i int) color.Color {
+	// Walk the hue circle by the golden angle so neighbouring species differ clearly.
+	h := float64(i) * 137.508
+	return hsl(h-360*float64(int(h/360)), 0.6, 0.6)
+}
+
+// hsl converts a hue in degrees with saturation and lightness in [0, 1] to a color.
+func hsl(h, s, l float64) color.Color {
+	c := (1 - abs(2*l-1)) * s
+	hp := h / 60
+	x := c * (1 - abs(hp-2*float64(int(hp/2))-1))
+	var r, g, b float64
+	switch int(hp) {
+	case 0:
+		r, g = c, x
+	case 1:
+		r, g = x, c
+	case 2:
+		g, b = c, x
+	case 3:
+		g, b = x, c
+	case 4:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := l - c/2
+	return color.RGBA{R: uint8((r + m) * 255), G: uint8((g + m) * 255), B: uint8((b + m) * 255), A: 255}
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// PlotSpecies draws the species sizes recorded by r as a stacked area chart, one band per
+// species in key order, like neat-python's plot_species, and saves it to filePath.
+func PlotSpecies(r *neat.SpeciesReporter, filePath string) error {
+	if len(r.Generations) == 0 {
+		return fmt.Errorf("no generations recorded to plot")
+	}
+	p := plot.New()
+	p.Title.Text = "Speciation"
+	p.X.Label.Text = "Generation"
+	p.Y.Label.Text = "Size per species"
+
+	matrix := r.Matrix()
+	lower := make([]float64, len(matrix))
+	for j := range r.SpeciesKeys() {
+		// Band polygon: upper edge left to right, then lower edge right to left.
+		band := make(plotter.XYs, 0, 2*len(matrix))
+		upper := make([]float64, len(matrix))
+		for i, row := range matrix {
+			upper[i] = lower[i] + float64(row[j])
+			band = append(band, plotter.XY{X: float64(r.Generations[i]), Y: upper[i]})
+		}
+		for i := len(matrix) - 1; i >= 0; i-- {
+			band = append(band, plotter.XY{X: float64(r.Generations[i]), Y: lower[i]})
+		}
+		polygon, err := plotter.NewPolygon(band)
+		if err != nil {
+			return err
+		}
+		polygon.Color = speciesColor(j)
+		polygon.LineStyle.Width = 0
+		p.Add(polygon)
+		lower = upper
+	}
+	if err := p.Save(Width, Height, filePath); err != nil {
+		return fmt.Errorf("failed to save species plot '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// PlotStats draws the best and mean fitness per generation, with dashed lines one standard
+// deviation above and below the mean, like neat-python's plot_stats, and saves it to filePath.
+func PlotStats(stats *neat.Statistics, filePath string) error {
+	if stats == nil || len(stats.Generations) == 0 {
+		return fmt.Errorf("no generations recorded to plot")
+	}
+	p := plot.New()
+	p.Title.Text = "Population's average and best fitness"
+	p.X.Label.Text = "Generation"
+	p.Y.Label.Text = "Fitness"
+	p.Legend.Top = true
+	p.Legend.Left = true
+
+	n := len(stats.Generations)
+	best, mean := make(plotter.XYs, n), make(plotter.XYs, n)
+	minus, plus := make(plotter.XYs, n), make(plotter.XYs, n)
+	for i, gs := range stats.Generations {
+		x := float64(gs.Generation)
+		best[i] = plotter.XY{X: x, Y: gs.BestFitness}
+		mean[i] = plotter.XY{X: x, Y: gs.MeanFitness}
+		minus[i] = plotter.XY{X: x, Y: gs.MeanFitness - gs.StdevFitness}
+		plus[i] = plotter.XY{X: x, Y: gs.MeanFitness + gs.StdevFitness}
+	}
+	series := []struct {
+		name   string
+		xys    plotter.XYs
+		color  color.Color
+		dashed bool
+	}{
+		{"average", mean, color.RGBA{B: 255, A: 255}, false},
+		{"-1 sd", minus, color.RGBA{G: 160, A: 255}, true},
+		{"+1 sd", plus, color.RGBA{G: 160, A: 255}, true},
+		{"best", best, color.RGBA{R: 255, A: 255}, false},
+	}
+	for _, s := range series {
+		line, err := plotter.NewLine(s.xys)
+		if err != nil {
+			return err
+		}
+		line.Color = s.color
+		if s.dashed {
+			line.Dashes = []vg.Length{vg.Points(4), vg.Points(3)}
+		}
+		p.Add(line)
+		p.Legend.Add(s.name, line)
+	}
+	p.Add(plotter.NewGrid())
+	if err := p.Save(Width, Height, filePath); err != nil {
+		return fmt.Errorf("failed to save fitness plot '%s': %w", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package neat
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultSharedWeights is the weight series from Weight Agnostic Neural Networks (Gaier & Ha, 2019).
+var DefaultSharedWeights = []float64{-2.0, -1.0, -0.5, 0.5, 1.0, 2.0}
+
+// SharedWeightFunc evaluates a genome whose connection weights have all been set to weight.
+type SharedWeightFunc func(g *Genome, weight float64) (float64, error)
+
+// WithSharedWeight returns a copy of g in which every connection weight equals weight.
+// Build the network from the copy to evaluate the topology at that shared weight.
+func WithSharedWeight(g *Genome, weight float64) *Genome {
+	shared := g.Copy()
+	for _, cg := range shared.Connections {
+		cg.Weight = weight
+	}
+	shared.MarkModified()
+	return shared
+}
+
+// SweepSharedWeights evaluates g once per shared weight and returns the scores in the same order.
+func SweepSharedWeights(g *Genome, weights []float64, eval SharedWeightFunc) ([]float64, error) {
+	scores := make([]float64, len(weights))
+	for i, w := range weights {
+		score, err := eval(WithSharedWeight(g, w), w)
+		if err != nil {
+			return nil, fmt.Errorf("evaluation of genome %d at shared weight %g failed: %w", g.Key, w, err)
+		}
+		scores[i] = score
+	}
+	return scores, nil
+}
+
+// WeightAgnosticEvaluator scores each genome's topology over a series of shared weights,
+// aggregating the per-weight scores into the genome's fitness. Use it with weight_agnostic = true
+// so evolution searches topologies only.
+type WeightAgnosticEvaluator struct {
+	Weights     []float64        // Shared weights to evaluate (default DefaultSharedWeights)
+	Aggregation string           // Name of the StatFunctions entry used to combine scores (default "mean")
+	Evaluate    SharedWeightFunc // User-provided evaluation at a single shared weight
+}
+
+// NewWeightAgnosticEvaluator creates an evaluator using DefaultSharedWeights and mean aggregation.
+func NewWeightAgnosticEvaluator(eval SharedWeightFunc) *WeightAgnosticEvaluator {
+	return &WeightAgnosticEvaluator{
+		Weights:     DefaultSharedWeights,
+		Aggregation: "mean",
+		Evaluate:    eval,
+	}
+}
+
+// EvaluateGenome sweeps the shared weights for g and returns the aggregated score.
+// It does not modify g.Fitness.
+func (we *WeightAgnosticEvaluator) EvaluateGenome(g *Genome) (float64, error) {
+	weights := we.Weights
+	if len(weights) == 0 {
+		weights = DefaultSharedWeights
+	}
+	aggregation := we.Aggregation
+	if aggregation == "" {
+		aggregation = "mean"
+	}
+	aggregate, ok := StatFunctions[aggregation]
+	if !ok {
+		return 0, fmt.Errorf("unknown shared weight aggregation function: %s", aggregation)
+	}
+	scores, err := SweepSharedWeights(g, weights, we.Evaluate)
+	if err != nil {
+		return 0, err
+	}
+	return aggregate(scores), nil
+}
+
+// FitnessFunc returns a FitnessFunc that sets each genome's fitness to its aggregated sweep score.
+func (we *WeightAgnosticEvaluator) FitnessFunc() FitnessFunc {
+	return func(genomes map[int]*Genome) error {
+		keys := make([]int, 0, len(genomes))
+		for k := range genomes {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		for _, k := range keys {
+			fitness, err := we.EvaluateGenome(genomes[k])
+			if err != nil {
+				return err
+			}
+			genomes[k].Fitness = fitness
+		}
+		return nil
+	}
+}